@@ -0,0 +1,15 @@
+module github.com/thazy/goal-guy-0
+
+go 1.13
+
+require (
+	github.com/apache/arrow/go/arrow v0.0.0-20211112161151-bc219186db40
+	github.com/chewxy/math32 v1.0.0
+	github.com/emer/emergent v1.0.0
+	github.com/emer/etable v1.0.0
+	github.com/emer/leabra v1.0.0
+	github.com/goki/gi v1.0.0
+	github.com/goki/ki v1.0.0
+	github.com/mattn/go-sqlite3 v1.10.0
+	gonum.org/v1/plot v0.7.0
+)
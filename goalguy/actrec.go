@@ -0,0 +1,188 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// actrec.go has the activation recordings: if ActRecOn, the ActRecVar
+// (e.g., ActM) unit vectors of the ActRecLays are recorded at the end of
+// every training and test trial in the ActRecLog, with the Mode (Train or
+// Test), Epoch, Trial, Row and item Name -- one row per trial, with a
+// tensor column per layer.  For the large recordings of thousands of
+// trials, CSV is too slow and lossy for float32, so they are exported in
+// the Apache Arrow IPC file format (SaveArrow, also known as Feather v2),
+// which loads quickly and exactly with pandas.read_feather or
+// polars.read_ipc (and converts to Parquet in one call from there).  The
+// layer columns are fixed-size lists of float32, with their tensor shapes
+// in the schema metadata as <col>.shape.
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// ConfigActRecLog configures the ActRecLog, with a tensor column per
+// ActRecLays of the shape of the layer -- called at Init
+func (ss *Sim) ConfigActRecLog() {
+	sc := etable.Schema{
+		{"Mode", etensor.STRING, nil, nil},
+		{"Epoch", etensor.INT64, nil, nil},
+		{"Trial", etensor.INT64, nil, nil},
+		{"Row", etensor.INT64, nil, nil},
+		{"Name", etensor.STRING, nil, nil},
+	}
+	for _, lnm := range ss.ActRecLays {
+		ly, ok := ss.Net.LayerByName(lnm).(*leabra.Layer)
+		if !ok {
+			continue
+		}
+		sc = append(sc, etable.Column{lnm, etensor.FLOAT32, ly.Shp.Shp, nil})
+	}
+	ss.ActRecLog.SetFromSchema(sc, 0)
+}
+
+// RecActs adds a row to the ActRecLog with the ActRecVar of the ActRecLays
+// for the current trial of given env, if ActRecOn -- called at the end of
+// each training and test trial
+func (ss *Sim) RecActs(env *Env, row int, train bool) {
+	if !ss.ActRecOn {
+		return
+	}
+	dt := ss.ActRecLog
+	r := dt.NumRows()
+	dt.AddRows(1)
+	mode := "Test"
+	if train {
+		mode = "Train"
+	}
+	dt.ColByName("Mode").SetString1D(r, mode)
+	dt.ColByName("Epoch").SetFloat1D(r, float64(ss.Epoch))
+	dt.ColByName("Trial").SetFloat1D(r, float64(env.Trial))
+	dt.ColByName("Row").SetFloat1D(r, float64(row))
+	dt.ColByName("Name").SetString1D(r, ItemName(env.Table, row))
+	for _, lnm := range ss.ActRecLays {
+		col, ok := dt.ColByName(lnm).(*etensor.Float32)
+		if !ok {
+			continue
+		}
+		acts, err := ss.Net.LayerByName(lnm).(*leabra.Layer).UnitVals(ss.ActRecVar)
+		if err != nil {
+			continue
+		}
+		_, cells := col.RowCellSize()
+		copy(col.Values[r*cells:(r+1)*cells], acts)
+	}
+}
+
+// SaveArrow saves given table to given file in the Arrow IPC file format
+// (Feather v2): scalar columns as int64, float32, float64 or string, and
+// tensor columns as fixed-size lists of float32 (float64 if that is their
+// type), with their cell shapes in the schema metadata as <col>.shape
+func SaveArrow(dt *etable.Table, fname string) error {
+	var fields []arrow.Field
+	var mdk, mdv []string
+	for ci, col := range dt.Cols {
+		nm := dt.ColNames[ci]
+		_, cells := col.RowCellSize()
+		var typ arrow.DataType
+		switch col.DataType() {
+		case etensor.STRING:
+			typ = arrow.BinaryTypes.String
+		case etensor.INT64, etensor.INT32:
+			typ = arrow.PrimitiveTypes.Int64
+		case etensor.FLOAT64:
+			typ = arrow.PrimitiveTypes.Float64
+		default:
+			typ = arrow.PrimitiveTypes.Float32
+		}
+		if cells > 1 {
+			if col.DataType() == etensor.STRING {
+				return fmt.Errorf("SaveArrow: string tensor column %s is not supported", nm)
+			}
+			if typ != arrow.PrimitiveTypes.Float64 {
+				typ = arrow.PrimitiveTypes.Float32
+			}
+			typ = arrow.FixedSizeListOf(int32(cells), typ)
+			shp := col.Shapes()[1:]
+			dims := make([]string, len(shp))
+			for i, s := range shp {
+				dims[i] = fmt.Sprintf("%d", s)
+			}
+			mdk = append(mdk, nm+".shape")
+			mdv = append(mdv, strings.Join(dims, ","))
+		}
+		fields = append(fields, arrow.Field{Name: nm, Type: typ})
+	}
+	md := arrow.NewMetadata(mdk, mdv)
+	schema := arrow.NewSchema(fields, &md)
+	mem := memory.NewGoAllocator()
+	rb := array.NewRecordBuilder(mem, schema)
+	defer rb.Release()
+
+	nr := dt.NumRows()
+	for ci, col := range dt.Cols {
+		_, cells := col.RowCellSize()
+		bld := rb.Field(ci)
+		if lb, ok := bld.(*array.FixedSizeListBuilder); ok {
+			vb := lb.ValueBuilder()
+			for r := 0; r < nr; r++ {
+				lb.Append(true)
+				for i := r * cells; i < (r+1)*cells; i++ {
+					switch b := vb.(type) {
+					case *array.Float64Builder:
+						b.Append(col.FloatVal1D(i))
+					case *array.Float32Builder:
+						b.Append(float32(col.FloatVal1D(i)))
+					}
+				}
+			}
+			continue
+		}
+		for r := 0; r < nr; r++ {
+			switch b := bld.(type) {
+			case *array.StringBuilder:
+				b.Append(col.StringVal1D(r))
+			case *array.Int64Builder:
+				b.Append(int64(col.FloatVal1D(r)))
+			case *array.Float64Builder:
+				b.Append(col.FloatVal1D(r))
+			case *array.Float32Builder:
+				b.Append(float32(col.FloatVal1D(r)))
+			}
+		}
+	}
+	rec := rb.NewRecord()
+	defer rec.Release()
+
+	f, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w, err := ipc.NewFileWriter(f, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+	if err != nil {
+		return err
+	}
+	if err := w.Write(rec); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// SaveActRec saves the ActRecLog to given Arrow file -- nothing if empty
+func (ss *Sim) SaveActRec(fname string) error {
+	if ss.ActRecLog.NumRows() == 0 {
+		return nil
+	}
+	return SaveArrow(ss.ActRecLog, fname)
+}
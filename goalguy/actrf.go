@@ -0,0 +1,130 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// ActRF computes an activation-based receptive field for each unit in a
+// receiving layer, as the receiving-activation-weighted average of the
+// activity pattern in a source layer (i.e., reverse correlation)
+type ActRF struct {
+	Name string           `desc:"name of this RF, e.g., Motor:Goal"`
+	RF   *etensor.Float32 `view:"no-inline" desc:"computed receptive field, as SumProd / SumRecv, shape is recv Y, X, source Y, X"`
+	Grid *etensor.Float32 `view:"-" desc:"RF laid out in 2D for display: each recv unit is a block of source units"`
+
+	SumProd *etensor.Float32 `view:"-" desc:"sum of recv act * source act"`
+	SumRecv []float32        `view:"-" desc:"sum of recv act, per recv unit"`
+}
+
+// Init initializes the RF for given recv and source 2D layer shapes
+func (af *ActRF) Init(name string, rshp, sshp []int) {
+	af.Name = name
+	shp := []int{rshp[0], rshp[1], sshp[0], sshp[1]}
+	af.RF = etensor.NewFloat32(shp, nil, []string{"RY", "RX", "SY", "SX"})
+	af.SumProd = etensor.NewFloat32(shp, nil, []string{"RY", "RX", "SY", "SX"})
+	af.Grid = etensor.NewFloat32([]int{rshp[0] * sshp[0], rshp[1] * sshp[1]}, nil, []string{"Y", "X"})
+	af.SumRecv = make([]float32, rshp[0]*rshp[1])
+}
+
+// Reset resets the accumulated sums, e.g., at the start of a new run
+func (af *ActRF) Reset() {
+	af.RF.SetZeros()
+	af.SumProd.SetZeros()
+	af.Grid.SetZeros()
+	for i := range af.SumRecv {
+		af.SumRecv[i] = 0
+	}
+}
+
+// Add accumulates given recv and source activations
+func (af *ActRF) Add(racts, sacts []float32) {
+	ns := len(sacts)
+	for ri, ra := range racts {
+		if ra < 0.1 {
+			continue
+		}
+		af.SumRecv[ri] += ra
+		st := ri * ns
+		for si, sa := range sacts {
+			af.SumProd.Values[st+si] += ra * sa
+		}
+	}
+}
+
+// Avg computes the RF from the accumulated sums, and updates the Grid display
+func (af *ActRF) Avg() {
+	ns := af.RF.Len() / len(af.SumRecv)
+	for ri, sr := range af.SumRecv {
+		st := ri * ns
+		for si := 0; si < ns; si++ {
+			rf := float32(0)
+			if sr > 0 {
+				rf = af.SumProd.Values[st+si] / sr
+			}
+			af.RF.Values[st+si] = rf
+		}
+	}
+	ry, rx, sy, sx := af.RF.Dim(0), af.RF.Dim(1), af.RF.Dim(2), af.RF.Dim(3)
+	for y := 0; y < ry; y++ {
+		for x := 0; x < rx; x++ {
+			for j := 0; j < sy; j++ {
+				for i := 0; i < sx; i++ {
+					af.Grid.Set([]int{y*sy + j, x*sx + i}, af.RF.Value([]int{y, x, j, i}))
+				}
+			}
+		}
+	}
+}
+
+// ConfigActRFs configures the Motor unit receptive fields for Context and Goal
+func (ss *Sim) ConfigActRFs() {
+	ctxtLay := ss.Net.LayerByName("Context").(*leabra.Layer)
+	goalLay := ss.Net.LayerByName("Goal").(*leabra.Layer)
+	motorLay := ss.Net.LayerByName("Motor").(*leabra.Layer)
+	ss.MotCtxtRF.Init("Motor:Context", motorLay.Shape().Shapes(), ctxtLay.Shape().Shapes())
+	ss.MotGoalRF.Init("Motor:Goal", motorLay.Shape().Shapes(), goalLay.Shape().Shapes())
+}
+
+// ResetActRFs resets the accumulated receptive fields -- called in Init
+func (ss *Sim) ResetActRFs() {
+	ss.MotCtxtRF.Reset()
+	ss.MotGoalRF.Reset()
+}
+
+// UpdtActRFs accumulates the Motor receptive fields after an alpha cycle:
+// the Context RF on the OutStep (context-driven) alpha cycle and the
+// Goal RF on the MotStep (goal-driven) alpha cycle of the TrialSpec, using
+// minus-phase activity
+func (ss *Sim) UpdtActRFs() {
+	motorLay := ss.Net.LayerByName("Motor").(*leabra.Layer)
+	mact, err := motorLay.UnitVals("ActM")
+	if err != nil {
+		return
+	}
+	ts := ss.CurTrialSpec()
+	if ss.AlphaCycle == ts.OutStep() {
+		ctxtLay := ss.Net.LayerByName("Context").(*leabra.Layer)
+		if cact, err := ctxtLay.UnitVals("ActM"); err == nil {
+			ss.MotCtxtRF.Add(mact, cact)
+		}
+	}
+	if ss.AlphaCycle == ts.MotStep() {
+		goalLay := ss.Net.LayerByName("Goal").(*leabra.Layer)
+		if gact, err := goalLay.UnitVals("ActM"); err == nil {
+			ss.MotGoalRF.Add(mact, gact)
+		}
+	}
+}
+
+// PlotActRFs computes and plots the Motor receptive fields into their tabs
+func (ss *Sim) PlotActRFs() {
+	ss.MotCtxtRF.Avg()
+	ss.MotGoalRF.Avg()
+	PlotTensorGrid(ss.CtxtRFSvg, ss.MotCtxtRF.Grid, "Motor Unit RFs: Context", "Motor X * Context X", "Motor Y * Context Y")
+	PlotTensorGrid(ss.GoalRFSvg, ss.MotGoalRF.Grid, "Motor Unit RFs: Goal", "Motor X * Goal X", "Motor Y * Goal Y")
+}
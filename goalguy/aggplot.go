@@ -0,0 +1,174 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// aggplot.go has the aggregate learning curve report across runs (e.g.,
+// different seeds): the epc_log.tsv of each run bundle in a run directory
+// (one sub-directory per run, as written by ExportRunBundle / RunPipeline)
+// is opened, and the curve of one column (e.g., OutGoalPctErr) is plotted
+// for each run, overlaid with the mean across runs and a mean +/- SEM band.
+// Runs can stop at different epochs (NZeroStop), so each epoch is
+// aggregated over the runs that reached it, recorded in the N column.
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"path/filepath"
+	"sort"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// OpenRunEpcLogs opens the epc_log.tsv of each run bundle directly under
+// given run directory, in name order, returning the logs and run names
+func OpenRunEpcLogs(dir string) ([]*etable.Table, []string, error) {
+	fns, err := filepath.Glob(filepath.Join(dir, "*", "epc_log.tsv"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(fns) == 0 {
+		return nil, nil, fmt.Errorf("no */epc_log.tsv run logs found in: %s", dir)
+	}
+	sort.Strings(fns)
+	logs := make([]*etable.Table, len(fns))
+	nms := make([]string, len(fns))
+	for i, fn := range fns {
+		dt := &etable.Table{}
+		if err := OpenTable(dt, fn); err != nil {
+			return nil, nil, err
+		}
+		logs[i] = dt
+		nms[i] = filepath.Base(filepath.Dir(fn))
+	}
+	return logs, nms, nil
+}
+
+// AggCurves returns a table with the N, Mean and SEM across given epoch
+// logs of given column, per Epoch, over the runs that reached each epoch
+func AggCurves(logs []*etable.Table, colNm string) (*etable.Table, error) {
+	vals := map[int][]float64{}
+	for i, dt := range logs {
+		ec := dt.ColByName("Epoch")
+		vc := dt.ColByName(colNm)
+		if ec == nil || vc == nil {
+			return nil, fmt.Errorf("AggCurves: run %d log has no Epoch or %s column", i, colNm)
+		}
+		for r := 0; r < dt.NumRows(); r++ {
+			epc := int(ec.FloatVal1D(r))
+			vals[epc] = append(vals[epc], vc.FloatVal1D(r))
+		}
+	}
+	epcs := make([]int, 0, len(vals))
+	for epc := range vals {
+		epcs = append(epcs, epc)
+	}
+	sort.Ints(epcs)
+
+	at := &etable.Table{}
+	at.SetFromSchema(etable.Schema{
+		{"Epoch", etensor.INT64, nil, nil},
+		{"N", etensor.INT64, nil, nil},
+		{"Mean", etensor.FLOAT64, nil, nil},
+		{"SEM", etensor.FLOAT64, nil, nil},
+	}, len(epcs))
+	for r, epc := range epcs {
+		vs := vals[epc]
+		n := float64(len(vs))
+		sum, ssq := 0.0, 0.0
+		for _, v := range vs {
+			sum += v
+		}
+		mean := sum / n
+		for _, v := range vs {
+			ssq += (v - mean) * (v - mean)
+		}
+		sem := 0.0
+		if n > 1 {
+			sem = math.Sqrt(ssq/(n-1)) / math.Sqrt(n)
+		}
+		at.ColByName("Epoch").SetFloat1D(r, float64(epc))
+		at.ColByName("N").SetFloat1D(r, n)
+		at.ColByName("Mean").SetFloat1D(r, mean)
+		at.ColByName("SEM").SetFloat1D(r, sem)
+	}
+	return at, nil
+}
+
+// AggPlot returns the plot of given column of each of the given epoch logs,
+// overlaid with the mean and mean +/- SEM band of the given AggCurves table
+func AggPlot(logs []*etable.Table, colNm string, at *etable.Table) *plot.Plot {
+	plt := NewPlot()
+	plt.Title.Text = fmt.Sprintf("%s: %d runs, mean +/- SEM", colNm, len(logs))
+	plt.X.Label.Text = "Epoch"
+	plt.Y.Label.Text = colNm
+
+	for _, dt := range logs {
+		ec := dt.ColByName("Epoch")
+		vc := dt.ColByName(colNm)
+		xy := make(plotter.XYs, dt.NumRows())
+		for r := range xy {
+			xy[r].X = ec.FloatVal1D(r)
+			xy[r].Y = vc.FloatVal1D(r)
+		}
+		l, _ := plotter.NewLine(xy)
+		l.LineStyle.Width = vg.Points(0.5 * CurPlotStyle.LineWidth)
+		l.LineStyle.Color = color.Gray{Y: 180}
+		plt.Add(l)
+	}
+
+	nr := at.NumRows()
+	mxy := make(plotter.XYs, nr)
+	band := make(plotter.XYs, 2*nr) // upper edge forward, lower edge back
+	for r := 0; r < nr; r++ {
+		epc := at.ColByName("Epoch").FloatVal1D(r)
+		mean := at.ColByName("Mean").FloatVal1D(r)
+		sem := at.ColByName("SEM").FloatVal1D(r)
+		mxy[r].X, mxy[r].Y = epc, mean
+		band[r].X, band[r].Y = epc, mean+sem
+		band[2*nr-1-r].X, band[2*nr-1-r].Y = epc, mean-sem
+	}
+	clr := CurPlotStyle.Color(1)
+	if nr > 0 {
+		pg, _ := plotter.NewPolygon(band)
+		pg.Color = color.NRGBA{clr.R, clr.G, clr.B, 64}
+		pg.LineStyle.Width = 0
+		plt.Add(pg)
+	}
+	l, _ := plotter.NewLine(mxy)
+	l.LineStyle.Width = vg.Points(2 * CurPlotStyle.LineWidth)
+	l.LineStyle.Color = clr
+	plt.Add(l)
+	plt.Legend.Add("Mean", l)
+	plt.Legend.Top = true
+	return plt
+}
+
+// SaveAggReport writes the aggregate learning curve of given column across
+// the runs in given run directory into it: the plot as agg_<col>.svg and the
+// AggCurves table as agg_<col>.tsv
+func SaveAggReport(dir, colNm string) error {
+	logs, _, err := OpenRunEpcLogs(dir)
+	if err != nil {
+		return err
+	}
+	at, err := AggCurves(logs, colNm)
+	if err != nil {
+		return err
+	}
+	fn := filepath.Join(dir, "agg_"+colNm)
+	if err := SaveTable(at, fn+".tsv"); err != nil {
+		return err
+	}
+	if err := AggPlot(logs, colNm, at).Save(5, 5, fn+".svg"); err != nil {
+		return err
+	}
+	fmt.Printf("saved aggregate %s report of %d runs to: %s.svg, .tsv\n", colNm, len(logs), fn)
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// anim.go has the recording of the NetView across the cycles of one test
+// trial, e.g., for showing in presentations how the Goal activation drives
+// the Motor settling: RecordTrialAnim runs the AnimRow item of the TestEnv
+// as a TestTrial, capturing the first NetView every AnimCycles cycles, and
+// SaveAnim writes the frames as an animated GIF, or as a numbered sequence
+// of PNG images (for conversion to video with, e.g., ffmpeg).
+
+import (
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CaptureFrame returns a copy of the current rendering of the first
+// NetView, scaled to NetViewImgWidth if > 0
+func (ss *Sim) CaptureFrame() (*image.RGBA, error) {
+	if !ss.HasNetView() {
+		return nil, fmt.Errorf("CaptureFrame: no NetView")
+	}
+	vp := ss.NetViews[0].Viewport()
+	if vp == nil || vp.Pixels == nil {
+		return nil, fmt.Errorf("CaptureFrame: NetView has not been rendered")
+	}
+	if ss.NetViewImgWidth > 0 {
+		return ScaleImage(vp.Pixels, ss.NetViewImgWidth), nil
+	}
+	img := image.NewRGBA(vp.Pixels.Bounds())
+	draw.Draw(img, img.Bounds(), vp.Pixels, vp.Pixels.Bounds().Min, draw.Src)
+	return img, nil
+}
+
+// RecordTrialAnim runs the item at given row of the TestEnv table as a
+// TestTrial, recording the NetView into AnimFrames every AnimCycles cycles
+// of each alpha cycle, and at the end of each quarter
+func (ss *Sim) RecordTrialAnim(row int) error {
+	et := ss.TestEnv.Table
+	if et == nil || row < 0 || row >= et.NumRows() {
+		return fmt.Errorf("RecordTrialAnim: row %d out of range of the TestEnv table", row)
+	}
+	if !ss.HasNetView() {
+		return fmt.Errorf("RecordTrialAnim: no NetView")
+	}
+	cbuf, pact := ss.CarryBuf, ss.PrevAct
+	ss.CarryReset()
+	defer func() { ss.CarryBuf, ss.PrevAct = cbuf, pact }()
+
+	ss.AnimFrames = nil
+	var ferr error
+	capture := func() {
+		ss.ViewLast = time.Time{} // bypass the ViewMaxHz throttling
+		ss.UpdateView()
+		img, err := ss.CaptureFrame()
+		if err != nil {
+			ferr = err
+			return
+		}
+		ss.AnimFrames = append(ss.AnimFrames, img)
+	}
+	ncyc := ss.AnimCycles
+	if ncyc < 1 {
+		ncyc = 1
+	}
+	prvCyc, prvQtr := ss.OnCycleEnd, ss.OnQuarterEnd
+	defer func() { ss.OnCycleEnd, ss.OnQuarterEnd = prvCyc, prvQtr }()
+	ss.OnCycleEnd = func(ss *Sim, cyc int) {
+		if prvCyc != nil {
+			prvCyc(ss, cyc)
+		}
+		if (cyc+1)%ncyc == 0 {
+			capture()
+		}
+	}
+	ss.OnQuarterEnd = func(ss *Sim, qtr int) {
+		if prvQtr != nil {
+			prvQtr(ss, qtr)
+		}
+		if ss.Time.CycPerQtr%ncyc != 0 { // last cycle not captured yet
+			capture()
+		}
+	}
+
+	env := &Env{Nm: "AnimEnv", Order: Sequential}
+	env.Init(et)
+	env.Trial = row
+	ss.TestTrial(env, true)
+	if ferr != nil {
+		return ferr
+	}
+	fmt.Printf("recorded %d NetView frames of test item %s\n", len(ss.AnimFrames), ItemName(et, row))
+	return nil
+}
+
+// SaveAnim saves the AnimFrames as an animated GIF if given file name ends
+// in .gif, and otherwise as a sequence of PNG images named by inserting the
+// frame number before the extension (e.g., anim_000.png, anim_001.png ..)
+func (ss *Sim) SaveAnim(fname string) error {
+	if len(ss.AnimFrames) == 0 {
+		return fmt.Errorf("SaveAnim: no frames recorded")
+	}
+	ext := filepath.Ext(fname)
+	if strings.ToLower(ext) == ".gif" {
+		return SaveGIF(ss.AnimFrames, ss.AnimDelay, fname)
+	}
+	base := strings.TrimSuffix(fname, ext)
+	for i, img := range ss.AnimFrames {
+		f, err := os.Create(fmt.Sprintf("%s_%03d.png", base, i))
+		if err != nil {
+			return err
+		}
+		err = png.Encode(f, img)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveGIF saves given frames as an animated GIF to given file, with given
+// delay between frames, in 100ths of a second, looping forever
+func SaveGIF(frames []*image.RGBA, delay int, fname string) error {
+	ag := &gif.GIF{}
+	for _, img := range frames {
+		pi := image.NewPaletted(img.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(pi, img.Bounds(), img, img.Bounds().Min)
+		ag.Image = append(ag.Image, pi)
+		ag.Delay = append(ag.Delay, delay)
+	}
+	f, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return gif.EncodeAll(f, ag)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// WtFmDWt updates the weights from the DWt's computed in the current alpha
+// cycle if BatchSize <= 1, and otherwise accumulates the DWt's into the
+// batch buffer, to be applied by BatchTrialDone once BatchSize trials are done
+func (ss *Sim) WtFmDWt() {
+	if ss.BatchSize <= 1 {
+		ss.Net.WtFmDWt()
+		ss.ApplyPrjnDecays()
+		ss.FreezePruned()
+		ss.WtUpdtCnt++
+		return
+	}
+	pjs := ss.AllPrjns()
+	if len(ss.BatchDWts) != len(pjs) {
+		ss.BatchDWts = make([][]float32, len(pjs))
+	}
+	for pi, pj := range pjs {
+		if len(ss.BatchDWts[pi]) != len(pj.Syns) {
+			ss.BatchDWts[pi] = make([]float32, len(pj.Syns))
+		}
+		bd := ss.BatchDWts[pi]
+		for si := range pj.Syns {
+			sy := &pj.Syns[si]
+			bd[si] += sy.DWt
+			sy.DWt = 0
+		}
+	}
+}
+
+// BatchTrialDone is called at the end of each training trial, and applies the
+// accumulated DWt's once BatchSize trials have been accumulated
+func (ss *Sim) BatchTrialDone() {
+	if ss.BatchSize <= 1 {
+		return
+	}
+	ss.BatchTrials++
+	if ss.BatchTrials < ss.BatchSize {
+		return
+	}
+	for pi, pj := range ss.AllPrjns() {
+		if pi >= len(ss.BatchDWts) {
+			break
+		}
+		bd := ss.BatchDWts[pi]
+		for si := range pj.Syns {
+			pj.Syns[si].DWt = bd[si]
+			bd[si] = 0
+		}
+	}
+	ss.Net.WtFmDWt()
+	ss.ApplyPrjnDecays()
+	ss.FreezePruned()
+	ss.WtUpdtCnt++
+	ss.BatchTrials = 0
+}
+
+// ResetBatch discards any partially-accumulated batch DWt's -- called in Init
+func (ss *Sim) ResetBatch() {
+	ss.BatchDWts = nil
+	ss.BatchTrials = 0
+	ss.WtUpdtCnt = 0
+}
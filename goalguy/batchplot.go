@@ -0,0 +1,49 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// batchplot.go has the headless plots of the EpcLog saved in the run bundle
+// at the end of a -nogui run: they are rendered directly to files by gonum
+// plot, without the svg.Editor of the gui, which requires a live window.
+
+import (
+	"path/filepath"
+	"strings"
+
+	"gonum.org/v1/plot/vg"
+)
+
+// BatchPlot is an epoch plot saved in the run bundle: the EpcLog Cols
+// against Epoch
+type BatchPlot struct {
+	Name  string   `desc:"file name of the plot, without the extension"`
+	Title string   `desc:"title of the plot"`
+	Cols  []string `desc:"EpcLog columns to plot against Epoch"`
+}
+
+// DefaultBatchPlots are the BatchPlots used by default
+var DefaultBatchPlots = []BatchPlot{
+	{"epc_err", "Goal Guy Epoch Errors", []string{"OutGoalPctErr", "OutPredPctErr", "OutPatPctErr"}},
+	{"epc_cos", "Goal Guy Epoch Cosines", []string{"OutCosDiff", "MotCosDiff", "OutGoalCos"}},
+	{"epc_sse", "Goal Guy Epoch SSE", []string{"OutSSE", "MotSSE"}},
+}
+
+// SaveBatchPlots saves each of the BatchPlots in each of the
+// BatchPlotFmts in given directory, returning the files saved
+func (ss *Sim) SaveBatchPlots(dir string) ([]BundleFile, error) {
+	var fs []BundleFile
+	sz := vg.Length(ss.BatchPlotSize) * vg.Inch
+	for _, bp := range ss.BatchPlots {
+		plt := ss.EpcColsPlot(bp.Title, bp.Cols)
+		for _, ext := range ss.BatchPlotFmts {
+			fn := bp.Name + "." + strings.TrimPrefix(ext, ".")
+			if err := plt.Save(sz, sz, filepath.Join(dir, fn)); err != nil {
+				return fs, err
+			}
+			fs = append(fs, BundleFile{fn, "epoch plot of " + strings.Join(bp.Cols, ", ")})
+		}
+	}
+	return fs, nil
+}
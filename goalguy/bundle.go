@@ -0,0 +1,165 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/emer/etable/etable"
+	"github.com/goki/gi/gi"
+)
+
+// A run bundle (ExportRunBundle) is a single directory holding everything
+// needed to analyze a run, in a fixed layout so that downstream analysis
+// notebooks can consume runs uniformly:
+//
+//	manifest.json   BundleManifest: format version, creation time, experiment,
+//	                seed, epoch, and the list of files with descriptions
+//	params.json     the Params used for the run
+//	epc_log.tsv     the EpcLog, tab-separated with header row
+//	epc_plot.svg    the epoch plot of the PlotVals
+//	tst/*.tsv       test / analysis logs (DevalLog, RevLog, DelayStats, ...),
+//	                only those that have rows
+//	weights.wts     the network weights, in the standard JSON weights format
+//
+// All .tsv files use the etable CSV format with a tab delimiter, where the
+// header row encodes the column types and tensor shapes.
+
+// BundleFormat is the version of the run bundle layout, recorded in the manifest
+const BundleFormat = 1
+
+// BundleFile describes one file in a run bundle
+type BundleFile struct {
+	Name string `desc:"path of the file relative to the bundle directory"`
+	Desc string `desc:"description of the contents of the file"`
+}
+
+// BundleManifest is the contents of the manifest.json file of a run bundle
+type BundleManifest struct {
+	Format     int          `desc:"version of the bundle layout (BundleFormat)"`
+	Created    string       `desc:"creation time, in RFC3339 format"`
+	Expt       string       `desc:"name of the experiment preset in use, if any"`
+	NetVariant string       `desc:"network architecture variant (Sim.NetVariant)"`
+	RndSeed    int64        `desc:"random seed of the run"`
+	Epoch      int          `desc:"epoch at which the bundle was exported"`
+	PatsHash   string       `desc:"hash of the ExtReps pattern set the network was trained on (Sim.PatsHash)"`
+	Stopped    string       `desc:"signal that stopped the run before completion, if any -- the bundle is then a checkpoint of the incomplete run"`
+	Files      []BundleFile `desc:"the files in the bundle"`
+}
+
+// ExportRunBundle writes the run bundle into given directory, which is
+// created if needed -- if dir is empty, a time-stamped directory name is used
+func (ss *Sim) ExportRunBundle(dir string) error {
+	if dir == "" {
+		dir = "goal_guy_0_run_" + time.Now().Format("20060102_150405")
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "tst"), 0755); err != nil {
+		return err
+	}
+	man := &BundleManifest{Format: BundleFormat, Created: time.Now().Format(time.RFC3339),
+		Expt: ss.Expt, NetVariant: ss.NetVariant.String(), RndSeed: ss.RndSeed, Epoch: ss.Epoch, PatsHash: ss.PatsHash}
+	if ss.StopSignal != nil {
+		man.Stopped = ss.StopSignal.String()
+	}
+
+	pb, err := json.MarshalIndent(ss.Params, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "params.json"), pb, 0644); err != nil {
+		return err
+	}
+	man.Files = append(man.Files, BundleFile{"params.json", "Params used for the run"})
+
+	if err := SaveTable(ss.EpcLog, filepath.Join(dir, "epc_log.tsv")); err != nil {
+		return err
+	}
+	man.Files = append(man.Files, BundleFile{"epc_log.tsv", "epoch log (EpcLog)"})
+
+	if err := ss.SaveEpcPlot(filepath.Join(dir, "epc_plot.svg")); err != nil {
+		return err
+	}
+	man.Files = append(man.Files, BundleFile{"epc_plot.svg", "epoch plot of the PlotVals"})
+
+	bfs, err := ss.SaveBatchPlots(dir)
+	if err != nil {
+		return err
+	}
+	man.Files = append(man.Files, bfs...)
+
+	cfs, err := ss.SaveClustPlots(dir)
+	if err != nil {
+		return err
+	}
+	man.Files = append(man.Files, cfs...)
+
+	if ss.ActRecLog.NumRows() > 0 {
+		if err := ss.SaveActRec(filepath.Join(dir, "actrec.arrow")); err != nil {
+			return err
+		}
+		man.Files = append(man.Files, BundleFile{"actrec.arrow", "per-trial activation recordings (ActRecLog), in the Arrow IPC (Feather v2) format"})
+	}
+
+	if err := ss.SaveOrderHist(filepath.Join(dir, "order_hist.tsv")); err != nil {
+		return err
+	}
+	man.Files = append(man.Files, BundleFile{"order_hist.tsv", "training item presentation order, for replay with ReplayFile"})
+
+	tsts := []struct {
+		nm, desc string
+		dt       *etable.Table
+	}{
+		{"DevalLog", "devaluation protocol results", ss.DevalLog},
+		{"RevLog", "reversal protocol results", ss.RevLog},
+		{"RunLog", "summary of each Train run", ss.RunLog},
+		{"PartLog", "dead and overused units per layer at each TestAll", ss.PartLog},
+		{"DriftLog", "representational drift per layer at each DriftInterval checkpoint", ss.DriftLog},
+		{"PruneLog", "sparsity per pruned projection at each PruneInterval pruning step", ss.PruneLog},
+		{"Policy", "learned goal -> action policy from the last Extract Policy", ss.Policy},
+		{"TstTrlLog", "last TestAll's per-trial decoded results", ss.TstTrlLog},
+		{"TstGrpLog", "last TestAll's stats per item Group", ss.TstGrpLog},
+		{"DelayStats", "last epoch's stats per delay", ss.DelayStats},
+		{"DWtLog", "max and mean |DWt| per projection per epoch", ss.DWtLog},
+		{"ContingStats", "last epoch's predicted vs. true contingency probabilities", ss.ContingStats},
+		{"DriveStats", "last epoch's stats per drive state", ss.DriveStats},
+		{"WtDiffs", "per-projection weight changes from the last CompareWts", ss.WtDiffs},
+	}
+	for _, ts := range tsts {
+		if ts.dt == nil || ts.dt.NumRows() == 0 {
+			continue
+		}
+		fn := filepath.Join("tst", ts.nm+".tsv")
+		if err := SaveTable(ts.dt, filepath.Join(dir, fn)); err != nil {
+			return err
+		}
+		man.Files = append(man.Files, BundleFile{fn, ts.desc + " (" + ts.nm + ")"})
+	}
+
+	if err := ss.SaveWts(gi.FileName(filepath.Join(dir, "weights.wts"))); err != nil {
+		return err
+	}
+	man.Files = append(man.Files, BundleFile{"weights.wts", "network weights"})
+
+	rfs, err := ss.SaveReport(dir, man.Files)
+	if err != nil {
+		return err
+	}
+	man.Files = append(man.Files, rfs...)
+
+	mb, err := json.MarshalIndent(man, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "manifest.json"), mb, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("exported run bundle to: %s\n", dir)
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// carry.go has the goal carry-over mode (CarryOn), modeling persistent
+// intentions: the Goal layer activity at the end of each trial is kept in
+// an inter-trial buffer (CarryBuf), which is added, scaled by CarryGain, to
+// the Context input of the next trial as a soft (graded) input -- a
+// recurrent context.  The buffer integrates over trials, losing CarryDecay
+// of its activity each trial:
+//
+//	CarryBuf = (1 - CarryDecay) * (Goal ActM + CarryBuf), clipped to 1
+//
+// The buffer is reset at Init, and saved and restored around TestAll, which
+// starts with an empty one.  Carried-over goals can cause perseveration:
+// the action of the previous trial is produced again when another one was
+// called for -- counted on every trial (whether CarryOn or not) in the
+// PerseverPct stats.
+
+import (
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// CarryReset empties the carry-over buffer and forgets the previous action
+func (ss *Sim) CarryReset() {
+	ss.CarryBuf = nil
+	ss.PrevAct = -1
+}
+
+// CarryStore integrates the current Goal layer activity into the carry-over
+// buffer -- called at the end of each trial
+func (ss *Sim) CarryStore() {
+	if !ss.CarryOn {
+		return
+	}
+	acts, err := ss.Net.LayerByName("Goal").(*leabra.Layer).UnitVals("ActM")
+	if err != nil {
+		return
+	}
+	if len(ss.CarryBuf) != len(acts) {
+		ss.CarryBuf = make([]float32, len(acts))
+	}
+	for i, a := range acts {
+		v := (1 - ss.CarryDecay) * (a + ss.CarryBuf[i])
+		if v > 1 {
+			v = 1
+		}
+		ss.CarryBuf[i] = v
+	}
+}
+
+// CarryCtxt returns the given Context input pattern with the carry-over
+// buffer added, if CarryOn and there is one -- units are matched by index
+// and the sum is clipped to 1
+func (ss *Sim) CarryCtxt(c etensor.Tensor) etensor.Tensor {
+	if !ss.CarryOn || len(ss.CarryBuf) == 0 {
+		return c
+	}
+	if ss.CarryC == nil || ss.CarryC.Len() != c.Len() {
+		ss.CarryC = etensor.NewFloat32(c.Shapes(), nil, nil)
+	}
+	for i := range ss.CarryC.Values {
+		v := float32(c.FloatVal1D(i))
+		if i < len(ss.CarryBuf) {
+			v += ss.CarryGain * ss.CarryBuf[i]
+		}
+		if v > 1 {
+			v = 1
+		}
+		ss.CarryC.Values[i] = v
+	}
+	return ss.CarryC
+}
+
+// PerseverCheck returns whether the decoded action dact is a perseveration
+// error: the same as the previous trial's decoded action, when the action
+// called for, tact, is a different one -- and records dact as the previous
+// action for the next trial
+func (ss *Sim) PerseverCheck(tact, dact int) bool {
+	pers := dact >= 0 && dact == ss.PrevAct && dact != tact
+	ss.PrevAct = dact
+	return pers
+}
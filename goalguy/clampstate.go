@@ -0,0 +1,91 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// clampstate.go has the ClampState, which makes the dynamic clamping of
+// the 2nd AlphaCycle safe: the Motor and Outcome plus-phase activations of
+// the 1st AlphaCycle are written into the pattern table (StoreActP), to be
+// clamped as the Motor target, and the ClampState records the original
+// value of every table cell written during the trial, so they are all
+// restored at the end of the trial (RestoreClamps) -- including when the
+// trial returns early, and whatever the sizes of the layers vs. the table
+// cells.
+
+import (
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// ClampCell is the original value of one table cell written during a trial
+type ClampCell struct {
+	Col  etensor.Tensor `desc:"table column written"`
+	Idx  int            `desc:"1D index of the value written in the column"`
+	Orig float64        `desc:"original value, before the first write"`
+}
+
+// ClampState records the table cells written during a trial, to restore
+// their original values at the end of the trial
+type ClampState struct {
+	Cells []ClampCell `desc:"cells written, in order"`
+}
+
+// Set sets the value at given 1D index of given column, recording its
+// original value
+func (cs *ClampState) Set(col etensor.Tensor, idx int, val float64) {
+	if idx < 0 || idx >= col.Len() {
+		return
+	}
+	cs.Cells = append(cs.Cells, ClampCell{Col: col, Idx: idx, Orig: col.FloatVal1D(idx)})
+	col.SetFloat1D(idx, val)
+}
+
+// SetRow sets the cell at given row of given column to given values,
+// recording the original values -- values beyond the size of the cell are
+// ignored, and cell values beyond the values are left as is
+func (cs *ClampState) SetRow(col etensor.Tensor, row int, vals []float32) {
+	_, cells := col.RowCellSize()
+	n := len(vals)
+	if n > cells {
+		n = cells
+	}
+	st := row * cells
+	for i := 0; i < n; i++ {
+		cs.Set(col, st+i, float64(vals[i]))
+	}
+}
+
+// Restore restores the original values of all the cells written since the
+// last Restore, in reverse order so that cells written more than once get
+// their value from before the first write
+func (cs *ClampState) Restore() {
+	for i := len(cs.Cells) - 1; i >= 0; i-- {
+		c := &cs.Cells[i]
+		c.Col.SetFloat1D(c.Idx, c.Orig)
+	}
+	cs.Cells = cs.Cells[:0]
+}
+
+// StoreActP writes the current Motor and Outcome plus-phase activations into
+// their columns at given row of given table, for clamping on the 2nd
+// AlphaCycle -- the original patterns are restored by RestoreClamps
+func (ss *Sim) StoreActP(et *etable.Table, row int) {
+	for _, lnm := range []string{"Motor", "Outcome"} {
+		ly := ss.Net.LayerByName(lnm).(*leabra.Layer)
+		av, err := ly.UnitVals("ActP")
+		if err != nil {
+			continue
+		}
+		if col := et.ColByName(lnm); col != nil {
+			ss.Clamps.SetRow(col, row, av)
+		}
+	}
+}
+
+// RestoreClamps restores the pattern table cells written during the trial
+// -- deferred at the start of TrainTrial and TestTrial
+func (ss *Sim) RestoreClamps() {
+	ss.Clamps.Restore()
+}
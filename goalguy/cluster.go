@@ -0,0 +1,253 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// cluster.go has the hierarchical clustering of the Outcome layer
+// representations recorded in the last TestAll (OutReps), and of the
+// Outcome patterns of the same test items, each shown as a dendrogram (in
+// the Out Clust and Pat Clust tabs, and saved as SVG), so the learned
+// outcome similarity structure can be compared against that of the
+// generated patterns.  Clustering is agglomerative with average linkage,
+// on the similarity matrix (SimMat) of Euclidean distances.
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+
+	"github.com/emer/etable/eplot"
+	"github.com/emer/etable/etable"
+	"github.com/emer/leabra/leabra"
+	"github.com/goki/gi/svg"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// ClustNode is a node of the cluster tree: a leaf (one item), or the
+// merge of its Kids at given Dist
+type ClustNode struct {
+	Name string       `desc:"name of the item, for a leaf"`
+	Idx  int          `desc:"index of the item, for a leaf -- -1 otherwise"`
+	Dist float64      `desc:"distance at which the Kids were merged -- 0 for a leaf"`
+	Kids []*ClustNode `desc:"the merged nodes -- empty for a leaf"`
+}
+
+// Leaves returns the leaves under the node, in tree order
+func (cn *ClustNode) Leaves() []*ClustNode {
+	if len(cn.Kids) == 0 {
+		return []*ClustNode{cn}
+	}
+	var lvs []*ClustNode
+	for _, k := range cn.Kids {
+		lvs = append(lvs, k.Leaves()...)
+	}
+	return lvs
+}
+
+// SimMat returns the matrix of Euclidean distances between given vectors
+func SimMat(vecs [][]float32) [][]float64 {
+	n := len(vecs)
+	sm := make([][]float64, n)
+	for i := range sm {
+		sm[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			d := 0.0
+			for k := range vecs[i] {
+				df := float64(vecs[i][k] - vecs[j][k])
+				d += df * df
+			}
+			d = math.Sqrt(d)
+			sm[i][j], sm[j][i] = d, d
+		}
+	}
+	return sm
+}
+
+// Glom returns the cluster tree of the items with given names and
+// distance matrix, by agglomerative clustering with average linkage
+func Glom(sm [][]float64, names []string) *ClustNode {
+	var nodes []*ClustNode
+	for i, nm := range names {
+		nodes = append(nodes, &ClustNode{Name: nm, Idx: i})
+	}
+	avgDist := func(a, b *ClustNode) float64 {
+		la, lb := a.Leaves(), b.Leaves()
+		d := 0.0
+		for _, x := range la {
+			for _, y := range lb {
+				d += sm[x.Idx][y.Idx]
+			}
+		}
+		return d / float64(len(la)*len(lb))
+	}
+	for len(nodes) > 1 {
+		mi, mj, md := 0, 1, math.MaxFloat64
+		for i := range nodes {
+			for j := i + 1; j < len(nodes); j++ {
+				if d := avgDist(nodes[i], nodes[j]); d < md {
+					mi, mj, md = i, j, d
+				}
+			}
+		}
+		mn := &ClustNode{Idx: -1, Dist: md, Kids: []*ClustNode{nodes[mi], nodes[mj]}}
+		nodes[mi] = mn
+		nodes = append(nodes[:mj], nodes[mj+1:]...)
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes[0]
+}
+
+// ItemName returns the Name of given row of given table, or row<row> if
+// it has none
+func ItemName(et *etable.Table, row int) string {
+	if nc := et.ColByName("Name"); nc != nil {
+		if nm := nc.StringVal1D(row); nm != "" {
+			return nm
+		}
+	}
+	return fmt.Sprintf("row%d", row)
+}
+
+// RecOutRep records the current Outcome layer ActM as the representation
+// of given row of given table in OutReps -- called for each trial of TestAll
+func (ss *Sim) RecOutRep(et *etable.Table, row int) {
+	ly := ss.Net.LayerByName("Outcome").(*leabra.Layer)
+	acts, err := ly.UnitVals("ActM")
+	if err != nil {
+		return
+	}
+	ss.OutReps = append(ss.OutReps, append([]float32{}, acts...))
+	ss.OutRepRows = append(ss.OutRepRows, row)
+	ss.OutRepTable = et
+}
+
+// ResetOutReps clears the OutReps, at the start of TestAll
+func (ss *Sim) ResetOutReps() {
+	ss.OutReps = nil
+	ss.OutRepRows = nil
+	ss.OutRepTable = nil
+}
+
+// OutRepNames returns the names of the items of the OutReps
+func (ss *Sim) OutRepNames() []string {
+	nms := make([]string, len(ss.OutRepRows))
+	for i, row := range ss.OutRepRows {
+		nms[i] = ItemName(ss.OutRepTable, row)
+	}
+	return nms
+}
+
+// PatReps returns the Outcome patterns of the items of the OutReps
+func (ss *Sim) PatReps() [][]float32 {
+	oc := ss.OutRepTable.ColByName("Outcome")
+	_, cells := oc.RowCellSize()
+	pats := make([][]float32, len(ss.OutRepRows))
+	for i, row := range ss.OutRepRows {
+		pats[i] = make([]float32, cells)
+		for j := range pats[i] {
+			pats[i][j] = float32(oc.FloatVal1D(row*cells + j))
+		}
+	}
+	return pats
+}
+
+// ClustPlot returns the dendrogram plot of the cluster tree of given
+// vectors with given names, with given title -- nil if there are none
+func ClustPlot(vecs [][]float32, names []string, title string) *plot.Plot {
+	root := Glom(SimMat(vecs), names)
+	if root == nil {
+		return nil
+	}
+	plt := NewPlot()
+	plt.Title.Text = title
+	plt.X.Label.Text = "Distance"
+	lvs := root.Leaves()
+	ys := map[*ClustNode]float64{}
+	lnms := make([]string, len(lvs))
+	for i, lf := range lvs {
+		ys[lf] = float64(i)
+		lnms[i] = lf.Name
+	}
+	addLine := func(x0, y0, x1, y1 float64) {
+		l, _ := plotter.NewLine(plotter.XYs{{x0, y0}, {x1, y1}})
+		l.LineStyle.Width = vg.Points(CurPlotStyle.LineWidth)
+		l.LineStyle.Color = CurPlotStyle.Color(0)
+		plt.Add(l)
+	}
+	var draw func(cn *ClustNode) float64
+	draw = func(cn *ClustNode) float64 {
+		if len(cn.Kids) == 0 {
+			return ys[cn]
+		}
+		kys := make([]float64, len(cn.Kids))
+		for i, k := range cn.Kids {
+			kys[i] = draw(k)
+			addLine(k.Dist, kys[i], cn.Dist, kys[i])
+		}
+		addLine(cn.Dist, kys[0], cn.Dist, kys[len(kys)-1])
+		y := (kys[0] + kys[len(kys)-1]) / 2
+		ys[cn] = y
+		return y
+	}
+	draw(root)
+	plt.NominalY(lnms...)
+	return plt
+}
+
+// OutClustPlot returns the dendrogram of the OutReps of the last TestAll
+func (ss *Sim) OutClustPlot() *plot.Plot {
+	return ClustPlot(ss.OutReps, ss.OutRepNames(), "Outcome ActM Clusters (last TestAll)")
+}
+
+// PatClustPlot returns the dendrogram of the Outcome patterns of the
+// items of the last TestAll
+func (ss *Sim) PatClustPlot() *plot.Plot {
+	if ss.OutRepTable == nil {
+		return nil
+	}
+	return ClustPlot(ss.PatReps(), ss.OutRepNames(), "Outcome Pattern Clusters")
+}
+
+// PlotClust plots the OutReps and pattern dendrograms into the OutClustSvg
+// and PatClustSvg -- called at the end of TestAll
+func (ss *Sim) PlotClust() {
+	plotIn := func(svge *svg.Editor, plt *plot.Plot) {
+		if svge == nil || !svge.IsVisible() || plt == nil {
+			return
+		}
+		eplot.PlotViewSVG(plt, svge, 5)
+	}
+	plotIn(ss.OutClustSvg, ss.OutClustPlot())
+	plotIn(ss.PatClustSvg, ss.PatClustPlot())
+}
+
+// SaveClustPlots saves the OutReps and pattern dendrograms as out_clust.svg
+// and pat_clust.svg in given directory, returning the files saved -- none
+// if there are no OutReps
+func (ss *Sim) SaveClustPlots(dir string) ([]BundleFile, error) {
+	if len(ss.OutReps) == 0 {
+		return nil, nil
+	}
+	var fs []BundleFile
+	for _, cp := range []struct {
+		fn, desc string
+		plt      *plot.Plot
+	}{
+		{"out_clust.svg", "cluster plot of the Outcome ActM of the last TestAll", ss.OutClustPlot()},
+		{"pat_clust.svg", "cluster plot of the Outcome patterns of the last TestAll items", ss.PatClustPlot()},
+	} {
+		if err := cp.plt.Save(5*vg.Inch, 5*vg.Inch, filepath.Join(dir, cp.fn)); err != nil {
+			return fs, err
+		}
+		fs = append(fs, BundleFile{cp.fn, cp.desc})
+	}
+	return fs, nil
+}
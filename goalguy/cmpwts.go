@@ -0,0 +1,143 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/chewxy/math32"
+
+	"github.com/emer/etable/eplot"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+	"github.com/goki/gi/gi"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// AllPrjns returns all the projections in the network, in layer order
+func (ss *Sim) AllPrjns() []*leabra.Prjn {
+	var pjs []*leabra.Prjn
+	for _, ly := range ss.Net.Layers {
+		for _, p := range ly.(*leabra.Layer).RcvPrjns {
+			pjs = append(pjs, p.(*leabra.Prjn))
+		}
+	}
+	return pjs
+}
+
+// PrjnPath returns the Send:Recv name of the projection
+func PrjnPath(pj *leabra.Prjn) string {
+	return pj.Send.Name() + ":" + pj.Recv.Name()
+}
+
+// PrjnWtVals returns a copy of the current weights of the projection
+func PrjnWtVals(pj *leabra.Prjn) []float32 {
+	wts := make([]float32, len(pj.Syns))
+	for i := range pj.Syns {
+		wts[i] = pj.Syns[i].Wt
+	}
+	return wts
+}
+
+// CompareWts loads the two given weight files and computes the magnitude
+// of weight change between them for each projection, recording the results
+// in the WtDiffs table: mean and max absolute difference, and the
+// root-mean-square difference.  The current weights are restored afterward.
+func (ss *Sim) CompareWts(fa, fb gi.FileName) error {
+	tmp, err := ioutil.TempFile("", "goal-guy-0-*.wts")
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+	if err := ss.Net.SaveWtsJSON(gi.FileName(tmp.Name())); err != nil {
+		return err
+	}
+	defer ss.Net.OpenWtsJSON(gi.FileName(tmp.Name()))
+
+	pjs := ss.AllPrjns()
+	if err := ss.Net.OpenWtsJSON(fa); err != nil {
+		return err
+	}
+	wtsa := make([][]float32, len(pjs))
+	for i, pj := range pjs {
+		wtsa[i] = PrjnWtVals(pj)
+	}
+	if err := ss.Net.OpenWtsJSON(fb); err != nil {
+		return err
+	}
+
+	dt := ss.WtDiffs
+	dt.SetFromSchema(etable.Schema{
+		{"Prjn", etensor.STRING, nil, nil},
+		{"MeanAbsDiff", etensor.FLOAT32, nil, nil},
+		{"MaxAbsDiff", etensor.FLOAT32, nil, nil},
+		{"RMSDiff", etensor.FLOAT32, nil, nil},
+	}, len(pjs))
+	for i, pj := range pjs {
+		var sum, sumsq, max float32
+		for si := range pj.Syns {
+			d := math32.Abs(pj.Syns[si].Wt - wtsa[i][si])
+			sum += d
+			sumsq += d * d
+			if d > max {
+				max = d
+			}
+		}
+		n := float32(len(pj.Syns))
+		if n == 0 {
+			n = 1
+		}
+		dt.ColByName("Prjn").SetString1D(i, PrjnPath(pj))
+		dt.ColByName("MeanAbsDiff").SetFloat1D(i, float64(sum/n))
+		dt.ColByName("MaxAbsDiff").SetFloat1D(i, float64(max))
+		dt.ColByName("RMSDiff").SetFloat1D(i, float64(math32.Sqrt(sumsq/n)))
+	}
+	return nil
+}
+
+// ReportWtDiffs prints the WtDiffs table computed by CompareWts
+func (ss *Sim) ReportWtDiffs(fa, fb gi.FileName) {
+	dt := ss.WtDiffs
+	fmt.Printf("Weight changes from %s to %s:\n", filepath.Base(string(fa)), filepath.Base(string(fb)))
+	fmt.Printf("%-16s\t%12s\t%12s\t%12s\n", "Prjn", "MeanAbsDiff", "MaxAbsDiff", "RMSDiff")
+	for i := 0; i < dt.NumRows(); i++ {
+		fmt.Printf("%-16s\t%12.6g\t%12.6g\t%12.6g\n", dt.ColByName("Prjn").StringVal1D(i),
+			dt.ColByName("MeanAbsDiff").FloatVal1D(i), dt.ColByName("MaxAbsDiff").FloatVal1D(i),
+			dt.ColByName("RMSDiff").FloatVal1D(i))
+	}
+}
+
+// PlotWtDiffs plots the per-projection RMSDiff from CompareWts as a bar
+// chart into WtDiffSvg
+func (ss *Sim) PlotWtDiffs() *plot.Plot {
+	if ss.WtDiffSvg == nil || !ss.WtDiffSvg.IsVisible() {
+		return nil
+	}
+	dt := ss.WtDiffs
+	nr := dt.NumRows()
+	vals := make(plotter.Values, nr)
+	nms := make([]string, nr)
+	for i := 0; i < nr; i++ {
+		vals[i] = dt.ColByName("RMSDiff").FloatVal1D(i)
+		nms[i] = dt.ColByName("Prjn").StringVal1D(i)
+	}
+	plt := NewPlot()
+	plt.Title.Text = "Weight Change per Projection"
+	plt.Y.Label.Text = "RMS Wt Diff"
+	bc, _ := plotter.NewBarChart(vals, vg.Points(20))
+	bc.Color = CurPlotStyle.Color(1)
+	plt.Add(bc)
+	plt.NominalX(nms...)
+	eplot.PlotViewSVG(plt, ss.WtDiffSvg, 5)
+	return plt
+}
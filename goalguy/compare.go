@@ -0,0 +1,137 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// compare.go has the Compare Runs tab: two or more saved epoch logs (e.g.,
+// the epc_log.tsv of run bundles, or logs saved with Save EpcLog) are
+// loaded, each with a run tag, and the selected columns are overlaid in one
+// plot: each run has its own color, and each column its own dash pattern.
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/emer/etable/eplot"
+	"github.com/emer/etable/etable"
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/giv"
+	"github.com/goki/gi/svg"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// CmpRun is one saved epoch log in the Compare Runs tab
+type CmpRun struct {
+	Tag  string        `desc:"run tag shown in the legend -- defaults to the file name if empty"`
+	File gi.FileName   `ext:".tsv" desc:"saved epoch log .tsv file"`
+	Log  *etable.Table `view:"-" desc:"the loaded epoch log"`
+}
+
+// CmpDashes are the dash patterns of the successive compared columns
+var CmpDashes = [][]vg.Length{
+	nil,
+	{vg.Points(4), vg.Points(4)},
+	{vg.Points(1), vg.Points(3)},
+	{vg.Points(6), vg.Points(2), vg.Points(1), vg.Points(2)},
+}
+
+// LoadCmpRuns opens the epoch log of each of the CmpRuns, setting the Tag
+// of those without one to the file name
+func (ss *Sim) LoadCmpRuns() error {
+	for i := range ss.CmpRuns {
+		cr := &ss.CmpRuns[i]
+		if cr.File == "" {
+			return fmt.Errorf("Compare Runs: no file for run %d", i)
+		}
+		dt := &etable.Table{}
+		if err := OpenTable(dt, string(cr.File)); err != nil {
+			return err
+		}
+		cr.Log = dt
+		if cr.Tag == "" {
+			cr.Tag = strings.TrimSuffix(filepath.Base(string(cr.File)), filepath.Ext(string(cr.File)))
+		}
+	}
+	return nil
+}
+
+// CmpPlot returns the plot of the CmpCols of the loaded CmpRuns vs. Epoch
+func (ss *Sim) CmpPlot() *plot.Plot {
+	plt := NewPlot()
+	plt.Title.Text = fmt.Sprintf("Compare Runs: %s", strings.Join(ss.CmpCols, ", "))
+	plt.X.Label.Text = "Epoch"
+	plt.Y.Label.Text = "Y"
+
+	for ri, cr := range ss.CmpRuns {
+		if cr.Log == nil {
+			continue
+		}
+		for ci, cl := range ss.CmpCols {
+			if cr.Log.ColByName(cl) == nil {
+				log.Printf("Compare Runs: column %s not found in: %s\n", cl, cr.File)
+				continue
+			}
+			xy, _ := eplot.NewTableXYNames(cr.Log, "Epoch", cl)
+			l, _ := plotter.NewLine(xy)
+			l.LineStyle.Width = vg.Points(CurPlotStyle.LineWidth)
+			l.LineStyle.Color = CurPlotStyle.Color(ri)
+			l.LineStyle.Dashes = CmpDashes[ci%len(CmpDashes)]
+			plt.Add(l)
+			plt.Legend.Add(cr.Tag+": "+cl, l)
+		}
+	}
+	plt.Legend.Top = true
+	return plt
+}
+
+// ConfigCmpTab adds the Compare Runs tab to given tab view
+func (ss *Sim) ConfigCmpTab(tv *gi.TabView, vp *gi.Viewport2D, width, height int) {
+	fr := tv.AddNewTab(gi.KiT_Frame, "Compare Runs").(*gi.Frame)
+	fr.Lay = gi.LayoutVert
+
+	gi.AddNewLabel(fr, "msg", "Add saved epoch logs (with an optional Tag) and the columns to compare, then Load && Plot:")
+	giv.AddNewSliceView(fr, "runs").SetSlice(&ss.CmpRuns, nil)
+	giv.AddNewSliceView(fr, "cols").SetSlice(&ss.CmpCols, nil)
+
+	btns := gi.AddNewFrame(fr, "btns", gi.LayoutHoriz)
+	addBtn := func(nm, txt string, fun func()) {
+		bt := gi.AddNewButton(btns, nm)
+		bt.SetText(txt)
+		bt.ButtonSig.Connect(fr.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig == int64(gi.ButtonClicked) {
+				fun()
+				vp.FullRender2DTree()
+			}
+		})
+	}
+	addBtn("plot", "Load && Plot", func() {
+		if err := ss.LoadCmpRuns(); err != nil {
+			log.Println(err)
+			return
+		}
+		eplot.PlotViewSVG(ss.CmpPlot(), ss.CmpSvg, 5)
+	})
+	addBtn("save", "Save Plot", func() {
+		fn := "goal_guy_0_compare.svg"
+		if err := ss.CmpPlot().Save(8*vg.Inch, 5*vg.Inch, fn); err != nil {
+			log.Println(err)
+		}
+	})
+
+	svge := svg.AddNewEditor(fr, "plot")
+	svge.InitScale()
+	svge.Fill = true
+	svge.SetProp("background-color", CurPlotStyle.Background)
+	svge.SetProp("width", units.NewValue(float32(width/2), units.Px))
+	svge.SetProp("height", units.NewValue(float32(height-300), units.Px))
+	svge.SetStretchMaxWidth()
+	svge.SetStretchMaxHeight()
+	ss.CmpSvg = svge
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+	"gonum.org/v1/plot"
+)
+
+// ConfigConfMat configures the Motor confusion matrix to the number of
+// Motor actions (NActs): rows are the true (outcome-appropriate) action, and
+// columns the action decoded from the Motor layer on the goal-driven alpha cycle
+func (ss *Sim) ConfigConfMat() {
+	na := ss.NActs()
+	ss.MotConfMat = etensor.NewFloat32([]int{na, na}, nil, []string{"True", "Decoded"})
+}
+
+// MaxUnitIdx returns the index of the unit with the highest value of given
+// variable (e.g., ActM) in the layer -- i.e., the decoded localist action
+func MaxUnitIdx(ly *leabra.Layer, varNm string) int {
+	vals, err := ly.UnitVals(varNm)
+	if err != nil || len(vals) == 0 {
+		return -1
+	}
+	mi := 0
+	for i, v := range vals {
+		if v > vals[mi] {
+			mi = i
+		}
+	}
+	return mi
+}
+
+// ConfMatReset zeros the Motor confusion matrix, at start of TestAll
+func (ss *Sim) ConfMatReset() {
+	ss.MotConfMat.SetZeros()
+}
+
+// ConfMatAdd increments the count for given true and decoded action indexes
+func (ss *Sim) ConfMatAdd(tact, dact int) {
+	if tact < 0 || dact < 0 {
+		return
+	}
+	idx := []int{tact, dact}
+	ss.MotConfMat.Set(idx, ss.MotConfMat.Value(idx)+1)
+}
+
+// ConfMatPlot returns the plot of the Motor confusion matrix
+func (ss *Sim) ConfMatPlot() *plot.Plot {
+	return TensorGridPlot(ss.MotConfMat, "Motor Confusion Matrix", "Decoded Action", "True Action")
+}
+
+// PlotConfMat plots the Motor confusion matrix into ConfMatSvg
+func (ss *Sim) PlotConfMat() {
+	PlotTensorGrid(ss.ConfMatSvg, ss.MotConfMat, "Motor Confusion Matrix", "Decoded Action", "True Action")
+}
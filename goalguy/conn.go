@@ -0,0 +1,69 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"github.com/emer/emergent/prjn"
+	"github.com/goki/ki/kit"
+)
+
+// ConnPats are the connectivity patterns that can be selected per pathway
+type ConnPats int32
+
+//go:generate stringer -type=ConnPats
+
+var KiT_ConnPats = kit.Enums.AddEnum(ConnPatsN, false, nil)
+
+const (
+	// OneToOneConn connects each sending unit to the receiving unit at the
+	// same index only
+	OneToOneConn ConnPats = iota
+
+	// FullConn connects every sending unit to every receiving unit
+	FullConn
+
+	// RndSparseConn connects each receiving unit to a uniformly random
+	// subset of PCon of the sending units
+	RndSparseConn
+
+	ConnPatsN
+)
+
+// PathConn specifies the connectivity pattern for one pathway
+type PathConn struct {
+	Prjn string   `desc:"pathway as Send:Recv layer names, e.g., Context:Goal"`
+	Pat  ConnPats `desc:"connectivity pattern"`
+	PCon float32  `desc:"for RndSparseConn, the proportion of sending units each receiving unit is connected to"`
+}
+
+// DefaultPathConns are the connectivity patterns the network was built with
+// before they were configurable
+var DefaultPathConns = []PathConn{
+	{"Context:Goal", OneToOneConn, 1},
+	{"Goal:Motor", FullConn, 1},
+	{"Motor:Outcome", FullConn, 1},
+	{"Outcome:Motor", FullConn, 1},
+}
+
+// PathPat returns the prjn.Pattern for given Send:Recv pathway according
+// to the PathConns, or Full if it is not listed there -- used in ConfigNet
+func (ss *Sim) PathPat(path string) prjn.Pattern {
+	for _, pc := range ss.PathConns {
+		if pc.Prjn != path {
+			continue
+		}
+		switch pc.Pat {
+		case OneToOneConn:
+			return prjn.NewOneToOne()
+		case RndSparseConn:
+			pat := prjn.NewUnifRnd()
+			pat.PCon = pc.PCon
+			pat.RndSeed = ss.RndSeed
+			return pat
+		}
+		return prjn.NewFull()
+	}
+	return prjn.NewFull()
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// console.go has the Console tab, for exploratory experiments without
+// recompiling: each line entered is evaluated on the Sim (the ss. prefix
+// is optional), as one of:
+//
+//	Method(args...)   call an exported Sim method, e.g., TrainNEpochs(10),
+//	                  with int, float, bool and "string" args
+//	Field             show the value of a field, e.g., EpcOutSSE
+//	Field = value     set a field of int, float, bool or string kind
+//	help              list the methods that can be called
+//
+// Commands run in the background, as with Train, so Stop works, and the
+// results are shown below the command line, most recent last.
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/ki/ki"
+)
+
+// ConsoleMaxLines is the number of lines of output kept in the Console tab
+var ConsoleMaxLines = 40
+
+// SplitArgs splits given comma-separated args, ignoring commas in quotes
+func SplitArgs(s string) []string {
+	var args []string
+	var cur strings.Builder
+	inq := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inq = !inq
+			cur.WriteRune(r)
+		case r == ',' && !inq:
+			args = append(args, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if a := strings.TrimSpace(cur.String()); a != "" || len(args) > 0 {
+		args = append(args, a)
+	}
+	return args
+}
+
+// ParseVal returns the value of given type parsed from given string --
+// only int, uint, float, bool and string kinds are supported, strings with
+// or without quotes
+func ParseVal(s string, typ reflect.Type) (reflect.Value, error) {
+	v := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 0, 64)
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 0, 64)
+		if err != nil {
+			return v, err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return v, err
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return v, err
+		}
+		v.SetBool(b)
+	case reflect.String:
+		if uq, err := strconv.Unquote(s); err == nil {
+			s = uq
+		}
+		v.SetString(s)
+	default:
+		return v, fmt.Errorf("values of type %v are not supported", typ)
+	}
+	return v, nil
+}
+
+// ConsoleHelp returns the list of the Sim methods that can be called from
+// the Console: those with only supported arg types
+func (ss *Sim) ConsoleHelp() string {
+	st := reflect.TypeOf(ss)
+	var mths []string
+	for i := 0; i < st.NumMethod(); i++ {
+		m := st.Method(i)
+		var args []string
+		ok := true
+		for a := 1; a < m.Type.NumIn(); a++ {
+			at := m.Type.In(a)
+			if _, err := ParseVal("0", at); err != nil {
+				ok = false
+				break
+			}
+			args = append(args, at.String())
+		}
+		if ok {
+			mths = append(mths, m.Name+"("+strings.Join(args, ", ")+")")
+		}
+	}
+	sort.Strings(mths)
+	return strings.Join(mths, "  ")
+}
+
+// ConsoleEval evaluates given Console command on the Sim, returning the
+// result to show
+func (ss *Sim) ConsoleEval(cmd string) (string, error) {
+	cmd = strings.TrimPrefix(strings.TrimSpace(cmd), "ss.")
+	sv := reflect.ValueOf(ss)
+	switch {
+	case cmd == "":
+		return "", nil
+	case cmd == "help":
+		return ss.ConsoleHelp(), nil
+	case strings.HasSuffix(cmd, ")"):
+		op := strings.Index(cmd, "(")
+		if op < 0 {
+			return "", fmt.Errorf("missing ( in call: %s", cmd)
+		}
+		nm := strings.TrimSpace(cmd[:op])
+		m := sv.MethodByName(nm)
+		if !m.IsValid() {
+			return "", fmt.Errorf("no Sim method named: %s", nm)
+		}
+		args := SplitArgs(cmd[op+1 : len(cmd)-1])
+		mt := m.Type()
+		if len(args) != mt.NumIn() {
+			return "", fmt.Errorf("%s takes %d args, got %d", nm, mt.NumIn(), len(args))
+		}
+		avs := make([]reflect.Value, len(args))
+		for i, a := range args {
+			av, err := ParseVal(a, mt.In(i))
+			if err != nil {
+				return "", fmt.Errorf("%s arg %d: %v", nm, i, err)
+			}
+			avs[i] = av
+		}
+		var outs []string
+		for _, r := range m.Call(avs) {
+			if err, ok := r.Interface().(error); ok && err != nil {
+				return "", err
+			}
+			outs = append(outs, fmt.Sprintf("%v", r.Interface()))
+		}
+		return strings.Join(outs, " "), nil
+	case strings.Contains(cmd, "="):
+		eq := strings.Index(cmd, "=")
+		nm := strings.TrimSpace(cmd[:eq])
+		f := sv.Elem().FieldByName(nm)
+		if !f.IsValid() || !f.CanSet() {
+			return "", fmt.Errorf("no settable Sim field named: %s", nm)
+		}
+		v, err := ParseVal(strings.TrimSpace(cmd[eq+1:]), f.Type())
+		if err != nil {
+			return "", err
+		}
+		f.Set(v)
+		return fmt.Sprintf("%s = %v", nm, f.Interface()), nil
+	default:
+		f := sv.Elem().FieldByName(cmd)
+		if !f.IsValid() {
+			return "", fmt.Errorf("no Sim field named: %s", cmd)
+		}
+		return fmt.Sprintf("%v", f.Interface()), nil
+	}
+}
+
+// ConsoleOut adds given lines to the Console output, keeping the last
+// ConsoleMaxLines of them
+func (ss *Sim) ConsoleOut(lines ...string) {
+	ss.ConsoleLines = append(ss.ConsoleLines, lines...)
+	if n := len(ss.ConsoleLines); n > ConsoleMaxLines {
+		ss.ConsoleLines = ss.ConsoleLines[n-ConsoleMaxLines:]
+	}
+	if ss.ConsoleLbl != nil {
+		ss.ConsoleLbl.SetText(strings.Join(ss.ConsoleLines, "<br>\n"))
+	}
+}
+
+// ConfigConsoleTab adds the Console tab to given tab view
+func (ss *Sim) ConfigConsoleTab(tv *gi.TabView, vp *gi.Viewport2D) {
+	fr := tv.AddNewTab(gi.KiT_Frame, "Console").(*gi.Frame)
+	fr.Lay = gi.LayoutVert
+
+	tf := gi.AddNewTextField(fr, "cmd")
+	tf.SetStretchMaxWidth()
+	ss.ConsoleLbl = gi.AddNewLabel(fr, "out", "enter a Sim method call, field or field = value, or help")
+	tf.TextFieldSig.Connect(fr.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig != int64(gi.TextFieldDone) {
+			return
+		}
+		cmd := tf.Text()
+		tf.SetText("")
+		go func() {
+			defer func() { // e.g., a method panicking on bad args
+				if r := recover(); r != nil {
+					ss.ConsoleOut("&gt; "+cmd, fmt.Sprintf("error: panic: %v", r))
+					ss.UpdateCtrlPanel()
+					vp.FullRender2DTree()
+				}
+			}()
+			res, err := ss.ConsoleEval(cmd)
+			if err != nil {
+				res = "error: " + err.Error()
+			}
+			ss.ConsoleOut("&gt; "+cmd, res)
+			ss.UpdateCtrlPanel()
+			vp.FullRender2DTree()
+		}()
+	})
+}
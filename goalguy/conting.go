@@ -0,0 +1,189 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"log"
+	"math/rand"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+	"github.com/goki/gi/gi"
+)
+
+// Probabilistic action-outcome contingencies (ContingOn): the Outcome that
+// results from the Motor action on the 1st AlphaCycle is sampled from the
+// Contings table, where action A (the most active Motor unit at the end of
+// the minus phase) yields outcome Out1 with probability P, and Out2 with
+// probability 1-P.  The sampled outcome replaces the Outcome target at the
+// start of the plus phase, so the minus phase Outcome activity is the
+// network's prediction.  The predicted probability of Out1 is taken as
+// the relative cosine of the minus phase activity to Out1 vs. Out2, and
+// is compared against the true P in ContingStats.
+//
+// When the contingency of DegradAction is degraded (Degraded, see
+// DegradProtocol), its Out1 outcome is also delivered after any other
+// action with probability DegradP, so it no longer depends on the action.
+// When the outcomes of the ExtinctActs actions are extinguished (Extinct,
+// see ExtinctProtocol), no outcome follows those actions.
+
+// ConfigContings configures the Contings table: opened from ContingFile if
+// set, otherwise generated with random Out1, Out2 patterns for each Motor
+// action, with probability ContingP -- called in Config
+func (ss *Sim) ConfigContings() {
+	ct := ss.Contings
+	oshp, onms := ss.OutShape()
+	ct.SetFromSchema(etable.Schema{
+		{"Action", etensor.INT64, nil, nil},
+		{"P", etensor.FLOAT32, nil, nil},
+		{"Out1", etensor.FLOAT32, oshp, onms},
+		{"Out2", etensor.FLOAT32, oshp, onms},
+	}, ss.NActs())
+	if ss.ContingFile != "" {
+		err := OpenTable(ct, string(ss.ContingFile))
+		if err != nil {
+			log.Println(err)
+		}
+	} else {
+		ss.GenOutPats(ct.Cols[2])
+		ss.GenOutPats(ct.Cols[3])
+		for i := 0; i < ct.NumRows(); i++ {
+			ct.ColByName("Action").SetFloat1D(i, float64(i))
+			ct.ColByName("P").SetFloat1D(i, float64(ss.ContingP))
+		}
+	}
+	ss.ContingOut = etensor.NewFloat32(oshp, nil, onms)
+
+	st := ss.ContingStats
+	st.SetFromSchema(etable.Schema{
+		{"Action", etensor.INT64, nil, nil},
+		{"N", etensor.INT64, nil, nil},
+		{"P", etensor.FLOAT32, nil, nil},
+		{"PredP", etensor.FLOAT32, nil, nil},
+		{"Out1Frac", etensor.FLOAT32, nil, nil},
+	}, ct.NumRows())
+	ss.ContingSums = make([]ContingSum, ct.NumRows())
+	for i := 0; i < ct.NumRows(); i++ {
+		st.ColByName("Action").SetFloat1D(i, ct.ColByName("Action").FloatVal1D(i))
+		st.ColByName("P").SetFloat1D(i, ct.ColByName("P").FloatVal1D(i))
+	}
+}
+
+// SaveContings saves the Contings table to given .tsv file, for later use as ContingFile
+func (ss *Sim) SaveContings(fname gi.FileName) error {
+	return SaveTable(ss.Contings, string(fname))
+}
+
+// ContingRow returns the row of the Contings table for given Motor action, -1 if none
+func (ss *Sim) ContingRow(act int) int {
+	ac := ss.Contings.ColByName("Action")
+	for i := 0; i < ss.Contings.NumRows(); i++ {
+		if int(ac.FloatVal1D(i)) == act {
+			return i
+		}
+	}
+	return -1
+}
+
+// ContingSum accumulates the stats over an epoch for one action
+type ContingSum struct {
+	N     int
+	PredP float32
+	Out1  int
+}
+
+// ContingPlusPhase samples the outcome of the Motor action at the start of
+// the plus phase of the 1st AlphaCycle, and applies it as the Outcome target.
+// Stats are accumulated only if training.
+func (ss *Sim) ContingPlusPhase(train bool) {
+	if !ss.ContingOn || ss.AlphaCycle != ss.CurTrialSpec().OutStep() {
+		return
+	}
+	motorLay := ss.Net.LayerByName("Motor").(*leabra.Layer)
+	outcomeLay := ss.Net.LayerByName("Outcome").(*leabra.Layer)
+	act := ss.ActIdx(motorLay, "ActM")
+	ri := ss.ContingRow(act)
+	if ri < 0 {
+		return
+	}
+	p := float32(ss.Contings.ColByName("P").FloatVal1D(ri))
+	o1 := RowCell(ss.Contings.ColByName("Out1"), ri)
+	o2 := RowCell(ss.Contings.ColByName("Out2"), ri)
+	o1v := o1.(*etensor.Float32).Values
+	o2v := o2.(*etensor.Float32).Values
+	out1 := rand.Float32() < p
+	if out1 {
+		copy(ss.ContingOut.Values, o1v)
+	} else {
+		copy(ss.ContingOut.Values, o2v)
+	}
+	if ss.Degraded && act != ss.DegradAction && rand.Float32() < ss.DegradP {
+		if di := ss.ContingRow(ss.DegradAction); di >= 0 {
+			d1 := RowCell(ss.Contings.ColByName("Out1"), di)
+			copy(ss.ContingOut.Values, d1.(*etensor.Float32).Values)
+			out1 = false
+		}
+	}
+	if ss.IsExtinct(act) {
+		ss.ContingOut.SetZeros()
+		out1 = false
+	}
+	outcomeLay.ApplyExt(ss.ContingOut)
+	if !train {
+		return
+	}
+	ss.ExtinctTrialStats(act)
+	ss.RevTrialStats(ss.ContingOut)
+	if act == ss.DegradAction {
+		ss.DegradActCnt++
+	}
+	acts, _ := outcomeLay.UnitVals("ActM")
+	c1 := Cosine(acts, o1v)
+	c2 := Cosine(acts, o2v)
+	predp := float32(0.5)
+	if c1+c2 > 0 {
+		predp = c1 / (c1 + c2)
+	}
+	cs := &ss.ContingSums[ri]
+	cs.N++
+	cs.PredP += predp
+	if out1 {
+		cs.Out1++
+	}
+}
+
+// LogContingStats records the epoch averages of the predicted probabilities
+// per action into ContingStats, and returns the average absolute difference
+// between predicted and true probabilities over the actions taken -- called
+// in LogEpoch
+func (ss *Sim) LogContingStats() float32 {
+	if !ss.ContingOn {
+		return 0
+	}
+	st := ss.ContingStats
+	sum := float32(0)
+	n := 0
+	for i := range ss.ContingSums {
+		cs := &ss.ContingSums[i]
+		st.ColByName("N").SetFloat1D(i, float64(cs.N))
+		if cs.N > 0 {
+			predp := cs.PredP / float32(cs.N)
+			st.ColByName("PredP").SetFloat1D(i, float64(predp))
+			st.ColByName("Out1Frac").SetFloat1D(i, float64(cs.Out1)/float64(cs.N))
+			d := predp - float32(st.ColByName("P").FloatVal1D(i))
+			if d < 0 {
+				d = -d
+			}
+			sum += d
+			n++
+		}
+		*cs = ContingSum{}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float32(n)
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// controlpanel.go has the Control Panel shown at the left of the GUI: the
+// fields of the Sim most often used are grouped into the RunCtrl, SimConfig
+// and SimStats structs embedded in the Sim, each shown in its own StructView
+// under a heading, and the full Sim, with all of its internals, is only shown
+// in a dialog by the Advanced button.
+
+import (
+	"github.com/emer/leabra/leabra"
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/giv"
+	"github.com/goki/ki/ki"
+)
+
+// RunCtrl has the Sim fields controlling a run: how long to train, when to
+// stop, and what to display while running.
+type RunCtrl struct {
+	MaxEpcs       int               `desc:"maximum number of epochs to run"`
+	NZeroStop     int               `desc:"if > 0, stop training after this number of consecutive epochs with OutGoalPctErr == 0"`
+	MoreEpcs      int               `desc:"number of epochs to train by the Train More action (TrainNEpochs), beyond MaxEpcs"`
+	ViewOn        bool              `desc:"whether to update the network view while running"`
+	TrainUpdt     leabra.TimeScales `desc:"at what time scale to update the display during training? Anything longer that Epoch updates at Epoch in the model"`
+	TestUpdt      leabra.TimeScales `desc:"at what time scale to update the display during training? Anything longer that Epoch updates at Epoch in the model"`
+	ViewMaxHz     float32           `desc:"maximum number of display updates per second while running -- more frequent ones (e.g., at the Cycle TrainUpdt) are coalesced -- 0 = no limit"`
+	Plot          bool              `desc:"update the epoch plot while running?"`
+	PlotVals      []string          `desc:"values to plot in epoch plot"`
+	Test          bool              `desc:"set to true to not call learning methods"`
+	ProgressEvery int               `desc:"if > 0, print a progress line with key stats, elapsed time and estimated time remaining to stdout every ProgressEvery training epochs -- see -progress"`
+	NotifyURL     string            `desc:"if set, webhook URL (e.g., a Slack incoming webhook) to post a summary to at the completion of -nogui runs and -sweeps, and a divergence alert -- see notify.go"`
+	NaNCheck      bool              `desc:"check the layer activities and weights for NaN / Inf at the end of each training epoch, halting training and dumping the offending state to a file if any -- see nancheck.go"`
+	DivergeSSE    float32           `desc:"if > 0, epoch Outcome or Motor SSE above which the run is considered to have diverged, in addition to NaN and Inf"`
+	ValInterval   int               `desc:"if > 0, run Validate (learning off) every ValInterval training epochs, logging results in the Val* columns of EpcLog -- NaN on the other epochs"`
+	DriftInterval int               `desc:"if > 0, test all the ExtReps items (learning off) every DriftInterval training epochs, logging the drift of the DriftLays activations since the previous and first such checkpoint in DriftLog -- see drift.go"`
+	DriftLays     []string          `desc:"layers whose representational drift is measured every DriftInterval epochs"`
+	PruneInterval int               `desc:"if > 0, prune the PrunePrjns every PruneInterval training epochs: their weights below PruneThr are zeroed and frozen for the rest of the run, logging the sparsity in PruneLog -- see prune.go"`
+	PrunePrjns    []string          `desc:"Send:Recv projections pruned every PruneInterval epochs"`
+	PruneThr      float32           `desc:"weights below this magnitude are pruned at each pruning step"`
+}
+
+// SimConfig has the main configuration of the network and of the trial
+// timing -- changes to the network structure take effect at the next Config.
+type SimConfig struct {
+	Expt         string        `inactive:"+" desc:"name of the experiment preset in use (see Expts) -- empty if none"`
+	PatsHash     string        `inactive:"+" desc:"hash of the ExtReps pattern set (see PatsHashCols), recorded with saved weights and run bundles"`
+	CycPerQtr    int           `desc:"number of cycles per quarter of each alpha cycle -- see AlphaTimings for per-AlphaCycle overrides"`
+	NQuarters    int           `min:"2" desc:"number of quarters per alpha cycle, the last NPlusQtrs of which are the plus phase -- extra quarters are added to the start of the minus phase"`
+	NPlusQtrs    int           `min:"1" desc:"number of quarters of the plus phase, at the end of each alpha cycle -- 1 = the standard leabra 3+1 with NQuarters = 4 -- the rest of the NQuarters are the minus phase"`
+	AlphaTimings []AlphaTiming `desc:"per-AlphaCycle overrides of CycPerQtr, NQuarters and NPlusQtrs, indexed by AlphaCycle (0 = outcome / goal setting, 1 = goal -> motor)"`
+	BatchSize    int           `desc:"number of trials over which to accumulate DWt before updating weights -- 1 or less = update weights after every alpha cycle"`
+	MotorTarg    MotorTargs    `desc:"source of the Motor plus phase target on the 2nd AlphaCycle: the 1st AlphaCycle's own ActP (self-supervision), the MotorTarg column of the pattern table, or the correct action computed by the environment"`
+	PatNOn       int           `desc:"number of active units in each generated Context and Outcome pattern"`
+	PatMinDiff   int           `desc:"if > 0, minimum number of units different (Hamming distance) between any two generated Context patterns, and any two Outcome patterns"`
+	SplitOn      bool          `desc:"if true, hold out SplitValPct of the ExtReps for validation and SplitTestPct for TestAll, stratified by item Group, and train on the rest -- see split.go"`
+	SplitValPct  float32       `desc:"proportion of the items of each Group held out for validation, if SplitOn"`
+	SplitTestPct float32       `desc:"proportion of the items of each Group held out for TestAll, if SplitOn"`
+	SplitFile    string        `desc:"if set, file the split row assignments (train, val, test) are written to at each Init, if SplitOn"`
+	MapMode      MapModes      `desc:"structure of the generated Context -> Outcome mappings: one-to-one, many-to-one or one-to-many -- see mapping.go"`
+	MapFan       int           `desc:"number of Contexts per Outcome (ManyToOne) or valid Outcomes per Context (OneToMany)"`
+	PatOverlap   float32       `min:"0" max:"1" desc:"proportion of the PatNOn active units of the generated Outcome patterns that are the same for all the items, so Outcomes share features across contexts -- not used if PoolsOn"`
+	OutMotBack   bool          `desc:"include the Outcome -> Motor back projection in the network"`
+	WtSym        WtSyms        `desc:"symmetry of the initial weights of reciprocal projections (e.g., Motor -> Outcome and Outcome -> Motor): the library default / Params, symmetric (transposed), or independent random -- PrjnWtInits override it per projection -- takes effect at the next Init"`
+	NetVariant   NetVariants   `desc:"alternative network architecture to build -- see NetVariants -- set before Config"`
+	PoolsOn      bool          `desc:"build Motor and Outcome as 4D layers of NPools pools, one per action / outcome category, with pool-level inhibition -- actions are decoded as the most active pool"`
+	NPools       int           `desc:"number of Motor and Outcome pools if PoolsOn -- must evenly divide the 25 units"`
+}
+
+// SimStats has the last epoch's and last TestAll's stats, as also
+// recorded in the EpcLog.
+type SimStats struct {
+	EpcMotSSE          float32 `inactive:"+" desc:"last epoch's total sum squared error - motor layer"`
+	EpcOutSSE          float32 `inactive:"+" desc:"last epoch's total sum squared error - motor layer"`
+	EpcMotAvgSSE       float32 `inactive:"+" desc:"last epoch's average sum squared error (average over trials, and over units within motor layer)"`
+	EpcOutAvgSSE       float32 `inactive:"+" desc:"last epoch's average sum squared error (average over trials, and over units within outcome layer)"`
+	EpcOutGoalPctErr   float32 `inactive:"+" desc:"last epoch's percent of trials that had SSE > 0 (subject to .5 unit-wise tolerance) - compares Outcome to Goal "`
+	EpcOutPredPctErr   float32 `inactive:"+" desc:"last epoch's percent of trials that had SSE > 0 (subject to .5 unit-wise tolerance) - Outcome layer prediction"`
+	EpcOutGoalPctCor   float32 `inactive:"+" desc:"last epoch's percent of trials that had SSE == 0 (subject to .5 unit-wise tolerance)"`
+	FirstZero          int     `inactive:"+" desc:"first epoch at which OutGoalPctErr was 0 -- -1 if not yet"`
+	LastZero           int     `inactive:"+" desc:"first epoch of the current unbroken stretch of epochs with OutGoalPctErr == 0 -- -1 if the last epoch had errors"`
+	NZero              int     `inactive:"+" desc:"number of consecutive epochs up to the last one with OutGoalPctErr == 0"`
+	EpcOutPredPctCor   float32 `inactive:"+" desc:"last epoch's percent of trials that had SSE == 0 (subject to .5 unit-wise tolerance)"`
+	EpcMotCosDiff      float32 `inactive:"+" desc:"last epoch's average cosine difference for output layer (a normalized error measure, maximum of 1 when the minus phase exactly matches the plus)"`
+	EpcOutCosDiff      float32 `inactive:"+" desc:"last epoch's average cosine difference for output layer (a normalized error measure, maximum of 1 when the minus phase exactly matches the plus)"`
+	EpcOutGoalCos      float32 `inactive:"+" desc:"last epoch's average cosine between the Outcome and Goal minus phase activations"`
+	EpcOutPatPctErr    float32 `inactive:"+" desc:"last epoch's proportion of trials on which the Outcome minus phase activation did not match the item's Outcome pattern"`
+	EpcOutValidPctCor  float32 `inactive:"+" desc:"last epoch's proportion of trials on which the Outcome minus phase activation matched any of the Outcomes valid for the item's Context (see MapMode)"`
+	EpcOutClassPctCor  float32 `inactive:"+" desc:"last epoch's forced-choice accuracy: proportion of trials on which the Outcome minus phase activation was closest (by cosine) to the correct item Outcome pattern of all of them"`
+	EpcGoalClassPctCor float32 `inactive:"+" desc:"last epoch's Goal readout accuracy: proportion of trials on which the Goal minus phase activation of the 1st AlphaCycle (driven by Context alone) was closest (by cosine) to the correct item Outcome pattern of all of them"`
+	EpcOutPatCos       float32 `inactive:"+" desc:"last epoch's average cosine between the Outcome minus phase activation and the item's Outcome pattern"`
+	EpcWtUpdts         int     `inactive:"+" desc:"last epoch's number of weight updates (WtFmDWt calls), which depends on BatchSize"`
+	EpcWtDecay         float32 `inactive:"+" desc:"last epoch's total absolute linear weight change made by the PrjnDecays"`
+	EpcMaxDWt          float32 `inactive:"+" desc:"last epoch's max |DWt| over all synapses, before DWtClip"`
+	EpcMeanDWt         float32 `inactive:"+" desc:"last epoch's mean |DWt| over all synapses and weight changes, before DWtClip"`
+	EpcTracePctCommit  float32 `inactive:"+" desc:"last epoch's percent of trials where the eligibility trace was committed (rewarded), if TraceOn"`
+	EpcCriticV         float32 `inactive:"+" desc:"last epoch's average Critic value prediction, if CriticOn"`
+	EpcSeqPctCor       float32 `inactive:"+" desc:"last epoch's proportion of action sequences that reached their goal"`
+	EpcDegradActPct    float32 `inactive:"+" desc:"last epoch's proportion of trials on which DegradAction was selected, if ContingOn"`
+	EpcExtinctActPct   float32 `inactive:"+" desc:"last epoch's proportion of trials on which one of ExtinctActs was selected, if ContingOn"`
+	EpcExtinctMotAct   float32 `inactive:"+" desc:"last epoch's average minus phase Motor activity of the ExtinctActs units, if ContingOn"`
+	EpcApproachPct     float32 `inactive:"+" desc:"last epoch's proportion of appetitive trials on which the action leading to the outcome was produced, if ValenceOn"`
+	EpcAvoidPct        float32 `inactive:"+" desc:"last epoch's proportion of aversive trials on which the action leading to the outcome was not produced, if ValenceOn"`
+	EpcContingErr      float32 `inactive:"+" desc:"last epoch's average absolute difference between predicted and true outcome probabilities over actions taken, if ContingOn"`
+	EpcTDErr           float32 `inactive:"+" desc:"last epoch's average Critic TD error, if CriticOn"`
+	EpcPerseverPct     float32 `inactive:"+" desc:"last epoch's proportion of trials with a perseveration error: the previous trial's action repeated when a different one was called for"`
+	EpcMotEntropy      float32 `inactive:"+" desc:"last epoch's entropy (bits) of the distribution of actions decoded from Motor over trials -- 0 = collapse onto a single action, log2(NActs) = all equally often"`
+	TstMotSSE          float32 `inactive:"+" desc:"last TestAll's average sum squared error - motor layer"`
+	TstOutSSE          float32 `inactive:"+" desc:"last TestAll's average sum squared error - outcome layer"`
+	TstMotCosDiff      float32 `inactive:"+" desc:"last TestAll's average cosine difference - motor layer"`
+	TstOutCosDiff      float32 `inactive:"+" desc:"last TestAll's average cosine difference - outcome layer"`
+	TstOutGoalPctErr   float32 `inactive:"+" desc:"last TestAll's percent of trials where Outcome did not match Goal (subject to .5 unit-wise tolerance)"`
+	TstOutPredPctErr   float32 `inactive:"+" desc:"last TestAll's percent of trials that had Outcome SSE > 0 (subject to .5 unit-wise tolerance)"`
+	TstOutClassPctCor  float32 `inactive:"+" desc:"last TestAll's forced-choice accuracy of the Outcome minus phase activation among the test item Outcome patterns"`
+	TstGoalClassPctCor float32 `inactive:"+" desc:"last TestAll's Goal readout accuracy of the 1st AlphaCycle Goal minus phase activation among the test item Outcome patterns"`
+	TstMaintCos        float32 `inactive:"+" desc:"last TestAll's Goal maintenance fidelity: average cosine between the Goal activity after MaintDelay alpha cycles and the originally clamped goal, if GoalMaint"`
+	TstSeqPctCor       float32 `inactive:"+" desc:"last TestAll's proportion of action sequences that reached their goal, if SeqSteps > 1"`
+	TstPerseverPct     float32 `inactive:"+" desc:"last TestAll's proportion of trials with a perseveration error"`
+	TstMotEntropy      float32 `inactive:"+" desc:"last TestAll's entropy (bits) of the distribution of actions decoded from Motor over trials"`
+	ValMotSSE          float32 `inactive:"+" desc:"last Validate's average sum squared error - motor layer"`
+	ValOutSSE          float32 `inactive:"+" desc:"last Validate's average sum squared error - outcome layer"`
+	ValMotCosDiff      float32 `inactive:"+" desc:"last Validate's average cosine difference - motor layer"`
+	ValOutCosDiff      float32 `inactive:"+" desc:"last Validate's average cosine difference - outcome layer"`
+	ValOutGoalPctErr   float32 `inactive:"+" desc:"last Validate's percent of trials where Outcome did not match Goal (subject to .5 unit-wise tolerance)"`
+	ValOutPredPctErr   float32 `inactive:"+" desc:"last Validate's percent of trials that had Outcome SSE > 0 (subject to .5 unit-wise tolerance)"`
+	ValOutClassPctCor  float32 `inactive:"+" desc:"last Validate's forced-choice accuracy of the Outcome minus phase activation among the validation item Outcome patterns"`
+	ValGoalClassPctCor float32 `inactive:"+" desc:"last Validate's Goal readout accuracy of the 1st AlphaCycle Goal minus phase activation among the validation item Outcome patterns"`
+	ValMaintCos        float32 `inactive:"+" desc:"last Validate's Goal maintenance fidelity, if GoalMaint"`
+	ValSeqPctCor       float32 `inactive:"+" desc:"last Validate's proportion of action sequences that reached their goal, if SeqSteps > 1"`
+	ValPerseverPct     float32 `inactive:"+" desc:"last Validate's proportion of trials with a perseveration error"`
+	ValMotEntropy      float32 `inactive:"+" desc:"last Validate's entropy (bits) of the distribution of actions decoded from Motor over trials"`
+}
+
+// ControlPanel is the grouped view of the Sim shown at the left of the GUI.
+type ControlPanel struct {
+	Run    *RunCtrl   `desc:"run control group"`
+	Config *SimConfig `desc:"configuration group"`
+	Stats  *SimStats  `desc:"stats group"`
+
+	Views []*giv.StructView `view:"-" desc:"the StructView of each group, updated by UpdateCtrlPanel"`
+}
+
+// ConfigCtrlPanel adds the Control Panel to par: a heading and StructView
+// for each of the Run, Config and Stats groups, and the Advanced button,
+// which shows the full Sim in a dialog.
+func (ss *Sim) ConfigCtrlPanel(par ki.Ki, vp *gi.Viewport2D) *gi.Frame {
+	cp := &ss.Panel
+	cp.Run = &ss.RunCtrl
+	cp.Config = &ss.SimConfig
+	cp.Stats = &ss.SimStats
+	cp.Views = nil
+
+	fr := gi.AddNewFrame(par, "ctrlpanel", gi.LayoutVert)
+	addGroup := func(nm, label string, grp interface{}) {
+		gi.AddNewLabel(fr, nm+"Lbl", "<b>"+label+"</b>")
+		sv := giv.AddNewStructView(fr, nm)
+		sv.SetStruct(grp, nil)
+		cp.Views = append(cp.Views, sv)
+	}
+	addGroup("run", "Run Control", cp.Run)
+	addGroup("config", "Config", cp.Config)
+	addGroup("stats", "Stats", cp.Stats)
+
+	bt := gi.AddNewButton(fr, "advanced")
+	bt.SetText("Advanced...")
+	bt.ButtonSig.Connect(fr.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonClicked) {
+			giv.StructViewDialog(vp, ss, giv.DlgOpts{Title: "Sim: Advanced"}, nil, nil)
+		}
+	})
+	return fr
+}
+
+// UpdateCtrlPanel updates the StructViews of the Control Panel, e.g., after
+// a run or a change of the Sim fields from code.
+func (ss *Sim) UpdateCtrlPanel() {
+	for _, sv := range ss.Panel.Views {
+		sv.UpdateFields()
+	}
+}
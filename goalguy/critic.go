@@ -0,0 +1,67 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// The optional actor-critic subsystem (CriticOn) adds a single-unit Critic
+// layer that learns to predict goal attainment (the Outcome matching the
+// Goal on the 1st AlphaCycle) from Context and Goal.  At the start of the
+// plus phase the reward r is computed from the minus phase activity and
+// clamped as the Critic target, and the TD error (r - V, where V is the
+// Critic minus phase activity) modulates the learning of projections into
+// the Motor layer, by a factor of (1 + CriticDAGain * TD), floored at 0.
+// Each trial is a single-step episode, so there is no discounting.
+
+// CriticPlusPhase computes the reward and TD error at the start of the plus
+// phase of the 1st AlphaCycle, and clamps the reward as the Critic target.
+// Epoch stats are accumulated only if training.
+func (ss *Sim) CriticPlusPhase(train bool) {
+	if !ss.CriticOn || ss.AlphaCycle != ss.CurTrialSpec().OutStep() {
+		return
+	}
+	criticLay := ss.Net.LayerByName("Critic").(*leabra.Layer)
+	goalLay := ss.Net.LayerByName("Goal").(*leabra.Layer)
+	outcomeLay := ss.Net.LayerByName("Outcome").(*leabra.Layer)
+	ss.TrialRew = 0
+	if CompareLayers(goalLay, outcomeLay, "ActM", 0.5).Match && ss.TrialValence >= 0 {
+		ss.TrialRew = 1 // aversive outcomes are never rewarding
+	}
+	ss.TrialV = criticLay.Neurons[0].ActM
+	ss.TrialTD = ss.TrialRew - ss.TrialV
+	ss.CriticTarg.Values[0] = ss.TrialRew
+	criticLay.ApplyExt(ss.CriticTarg)
+	if train {
+		ss.CriticSumV += ss.TrialV
+		ss.CriticSumTD += ss.TrialTD
+	}
+}
+
+// CriticModDWt scales the DWt's of all projections into the Motor layer by
+// the TD error modulation factor -- called after DWt in AlphaCyc
+func (ss *Sim) CriticModDWt() {
+	if !ss.CriticOn {
+		return
+	}
+	mod := 1 + ss.CriticDAGain*ss.TrialTD
+	if mod < 0 {
+		mod = 0
+	}
+	motorLay := ss.Net.LayerByName("Motor").(*leabra.Layer)
+	for _, p := range motorLay.RcvPrjns {
+		pj := p.(*leabra.Prjn)
+		for si := range pj.Syns {
+			pj.Syns[si].DWt *= mod
+		}
+	}
+}
+
+// ConfigCritic configures the critic target tensor -- called in Config
+func (ss *Sim) ConfigCritic() {
+	ss.CriticTarg = etensor.NewFloat32([]int{1, 1}, nil, []string{"Y", "X"})
+}
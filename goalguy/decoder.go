@@ -0,0 +1,176 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// decoder.go has a generic Decoder mapping the activity of any layer to the
+// Name of the nearest (by cosine) of a set of prototype patterns.  The
+// prototypes are either defined analytically from a column of a pattern
+// table (InitFromTable), or trained from activity vectors with known labels
+// (Train), or both.  In TestAll, the OutDecoder is initialized from the
+// Outcome patterns of the test items, and used to report by name in the
+// TstTrlLog which outcome the network predicted, and which goal it acted on.
+
+import (
+	"fmt"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// Decoder maps activity vectors to the name of the nearest prototype pattern
+type Decoder struct {
+	Names []string    `desc:"name of each prototype"`
+	Pats  [][]float32 `desc:"prototype pattern for each name"`
+}
+
+// InitFromTable sets the prototypes to copies of the patterns in given
+// column of given table, named by its Name column (or the row number if none)
+func (dc *Decoder) InitFromTable(et *etable.Table, colNm string) error {
+	col := et.ColByName(colNm)
+	if col == nil {
+		return fmt.Errorf("Decoder: table has no %s column", colNm)
+	}
+	nc := et.ColByName("Name")
+	nr := et.NumRows()
+	_, cells := col.RowCellSize()
+	dc.Names = make([]string, nr)
+	dc.Pats = make([][]float32, nr)
+	for row := 0; row < nr; row++ {
+		if nc != nil {
+			dc.Names[row] = nc.StringVal1D(row)
+		} else {
+			dc.Names[row] = fmt.Sprintf("%d", row)
+		}
+		pat := make([]float32, cells)
+		for i := range pat {
+			pat[i] = float32(col.FloatVal1D(row*cells + i))
+		}
+		dc.Pats[row] = pat
+	}
+	return nil
+}
+
+// Train moves the prototype of given name toward given activity vector by
+// lrate, adding it (as a copy of vals) if it does not exist yet
+func (dc *Decoder) Train(name string, vals []float32, lrate float32) {
+	for i, nm := range dc.Names {
+		if nm != name {
+			continue
+		}
+		pat := dc.Pats[i]
+		for j := range pat {
+			if j < len(vals) {
+				pat[j] += lrate * (vals[j] - pat[j])
+			}
+		}
+		return
+	}
+	dc.Names = append(dc.Names, name)
+	dc.Pats = append(dc.Pats, append([]float32{}, vals...))
+}
+
+// Decode returns the name and index of the prototype nearest to given
+// activity vector, and its cosine -- "", -1 if there are no prototypes or
+// the activity is all zero
+func (dc *Decoder) Decode(vals []float32) (name string, idx int, cos float32) {
+	idx = -1
+	for i, pat := range dc.Pats {
+		c := Cosine(vals, pat)
+		if c > cos {
+			idx, cos = i, c
+		}
+	}
+	if idx >= 0 {
+		name = dc.Names[idx]
+	}
+	return
+}
+
+// Classify returns whether the prototype nearest to given activity vector
+// is (identical to) the given correct pattern -- the forced-choice
+// classification of the activity among all the prototypes
+func (dc *Decoder) Classify(vals, correct []float32) bool {
+	_, idx, _ := dc.Decode(vals)
+	if idx < 0 {
+		return false
+	}
+	pat := dc.Pats[idx]
+	if len(pat) != len(correct) {
+		return false
+	}
+	for i := range pat {
+		if pat[i] != correct[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// DecodeLayer decodes the values of given variable (e.g., ActM) of given layer
+func (dc *Decoder) DecodeLayer(ly *leabra.Layer, varNm string) (name string, idx int, cos float32) {
+	vals, err := ly.UnitVals(varNm)
+	if err != nil {
+		return "", -1, 0
+	}
+	return dc.Decode(vals)
+}
+
+// ConfigTstTrlLog sets up the TstTrlLog table of decoded test trial results
+func (ss *Sim) ConfigTstTrlLog() {
+	dt := ss.TstTrlLog
+	dt.SetFromSchema(etable.Schema{
+		{"Trial", etensor.INT64, nil, nil},
+		{"Name", etensor.STRING, nil, nil},
+		{"Group", etensor.STRING, nil, nil},
+		{"PredOut", etensor.STRING, nil, nil},
+		{"PredOutCos", etensor.FLOAT32, nil, nil},
+		{"Goal", etensor.STRING, nil, nil},
+		{"GoalCos", etensor.FLOAT32, nil, nil},
+		{"Action", etensor.INT64, nil, nil},
+		{"OutGoalErr", etensor.FLOAT32, nil, nil},
+	}, 0)
+}
+
+// LogTstTrlPred records the Outcome predicted by the network (decoded from
+// Outcome ActM) for the current test trial -- called after the 1st AlphaCycle
+func (ss *Sim) LogTstTrlPred(et *etable.Table, row int) {
+	dt := ss.TstTrlLog
+	if dt.NumRows() <= row {
+		dt.SetNumRows(row + 1)
+	}
+	if ss.OutDecoder.Pats == nil {
+		ss.OutDecoder.InitFromTable(et, "Outcome")
+	}
+	outcomeLay := ss.Net.LayerByName("Outcome").(*leabra.Layer)
+	nm, _, cos := ss.OutDecoder.DecodeLayer(outcomeLay, "ActM")
+	dt.ColByName("Trial").SetFloat1D(row, float64(row))
+	if nc := et.ColByName("Name"); nc != nil {
+		dt.ColByName("Name").SetString1D(row, nc.StringVal1D(row))
+	}
+	dt.ColByName("Group").SetString1D(row, ItemGroup(et, row))
+	dt.ColByName("PredOut").SetString1D(row, nm)
+	dt.ColByName("PredOutCos").SetFloat1D(row, float64(cos))
+}
+
+// LogTstTrlAct records the Goal the network acted on (decoded from Goal
+// ActM), the Motor action and whether the Outcome failed to match the Goal
+// for the current test trial -- called at the end of TestTrial
+func (ss *Sim) LogTstTrlAct(row, act int, outgoalerr bool) {
+	dt := ss.TstTrlLog
+	if dt.NumRows() <= row {
+		return
+	}
+	goalLay := ss.Net.LayerByName("Goal").(*leabra.Layer)
+	nm, _, cos := ss.OutDecoder.DecodeLayer(goalLay, "ActM")
+	dt.ColByName("Goal").SetString1D(row, nm)
+	dt.ColByName("GoalCos").SetFloat1D(row, float64(cos))
+	dt.ColByName("Action").SetFloat1D(row, float64(act))
+	oge := 0.0
+	if outgoalerr {
+		oge = 1
+	}
+	dt.ColByName("OutGoalErr").SetFloat1D(row, oge)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"math/rand"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// NewTrialDelay chooses the delay for the current trial at random from
+// DelayVals (0 if empty)
+func (ss *Sim) NewTrialDelay() {
+	ss.TrialDelay = 0
+	if len(ss.DelayVals) > 0 {
+		ss.TrialDelay = ss.DelayVals[rand.Intn(len(ss.DelayVals))]
+	}
+}
+
+// DelayCycs runs the TrialDelay alpha cycles of the delay period between
+// the outcome / goal-setting AlphaCycle and the motor-production AlphaCycle,
+// during which no external input is clamped and there is no learning.
+// The Goal maintenance gate (if GoalMaint) is open during the delay.
+func (ss *Sim) DelayCycs() {
+	if ss.TrialDelay <= 0 {
+		return
+	}
+	ss.SetGoalGate(true)
+	ss.Net.InitExt()
+	for _, lnm := range []string{"Goal", "Motor", "Outcome"} {
+		ss.Net.LayerByName(lnm).SetType(emer.Hidden)
+	}
+	for ss.DelayCyc = 0; ss.DelayCyc < ss.TrialDelay; ss.DelayCyc++ {
+		ss.AlphaCyc(false)
+	}
+	ss.DelayCyc = 0
+}
+
+// GoalClampAfterDelay returns whether the Goal should be clamped on the
+// motor-production AlphaCycle of the current trial
+func (ss *Sim) GoalClampAfterDelay() bool {
+	return ss.TrialDelay == 0 || !ss.DelayNoGoal
+}
+
+// ConfigDelayStats configures the DelayStats table, with one row per DelayVals
+func (ss *Sim) ConfigDelayStats() {
+	dt := ss.DelayStats
+	dt.SetFromSchema(etable.Schema{
+		{"Delay", etensor.INT64, nil, nil},
+		{"N", etensor.INT64, nil, nil},
+		{"MotSSE", etensor.FLOAT32, nil, nil},
+		{"MotCosDiff", etensor.FLOAT32, nil, nil},
+		{"OutGoalPctErr", etensor.FLOAT32, nil, nil},
+	}, len(ss.DelayVals))
+	for i, d := range ss.DelayVals {
+		dt.ColByName("Delay").SetFloat1D(i, float64(d))
+	}
+	ss.DelaySums = make([]DelaySum, len(ss.DelayVals))
+}
+
+// DelaySum accumulates stats over an epoch for one delay value
+type DelaySum struct {
+	N       int
+	MotSSE  float32
+	MotCos  float32
+	GoalErr int
+}
+
+// DelayStatsAdd accumulates the trial stats for the current TrialDelay
+func (ss *Sim) DelayStatsAdd(msse, mcd float32, outgoalerr bool) {
+	for i, d := range ss.DelayVals {
+		if d != ss.TrialDelay || i >= len(ss.DelaySums) {
+			continue
+		}
+		ds := &ss.DelaySums[i]
+		ds.N++
+		ds.MotSSE += msse
+		ds.MotCos += mcd
+		if outgoalerr {
+			ds.GoalErr++
+		}
+		return
+	}
+}
+
+// LogDelayStats records the epoch averages of the stats per delay into
+// DelayStats, and resets the sums -- called in LogEpoch
+func (ss *Sim) LogDelayStats() {
+	dt := ss.DelayStats
+	for i := range ss.DelaySums {
+		ds := &ss.DelaySums[i]
+		n := float32(ds.N)
+		if n == 0 {
+			n = 1
+		}
+		dt.ColByName("N").SetFloat1D(i, float64(ds.N))
+		dt.ColByName("MotSSE").SetFloat1D(i, float64(ds.MotSSE/n))
+		dt.ColByName("MotCosDiff").SetFloat1D(i, float64(ds.MotCos/n))
+		dt.ColByName("OutGoalPctErr").SetFloat1D(i, float64(float32(ds.GoalErr)/n))
+		*ds = DelaySum{}
+	}
+}
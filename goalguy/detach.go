@@ -0,0 +1,58 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// detach.go has the popping out of tabs (e.g., the NetView or Epc Plot)
+// into separate windows, for when the split view of the main window makes
+// them too small: the tab contents are moved, not copied, into the new
+// window, so they keep being updated as usual while training, and are
+// moved back into the tab view (re-docked) when the window is closed.
+
+import (
+	"fmt"
+
+	"github.com/goki/gi/gi"
+)
+
+// DetachTab moves the current tab of given tab view into a new window of
+// given size, recording the window in Detached -- closing the window moves
+// the tab back into the tab view
+func (ss *Sim) DetachTab(tv *gi.TabView, width, height int) error {
+	_, idx, ok := tv.CurTab()
+	if !ok {
+		return fmt.Errorf("DetachTab: no current tab")
+	}
+	widg, label, ok := tv.DeleteTabIndex(idx, false) // !destroy
+	if !ok {
+		return fmt.Errorf("DetachTab: could not remove tab %d", idx)
+	}
+	win := gi.NewWindow2D("goal-guy-0-"+label, "Goal Guy Phase 0: "+label, width, height, true)
+	vp := win.WinViewport2D()
+	updt := vp.UpdateStart()
+	mfr := win.SetMainFrame()
+	mfr.AddChild(widg)
+	vp.UpdateEndNoSig(updt)
+
+	if ss.Detached == nil {
+		ss.Detached = make(map[string]*gi.Window)
+	}
+	ss.Detached[label] = win
+	win.SetCloseCleanFunc(func(w *gi.Window) {
+		mfr.DeleteChild(widg, false) // !destroy
+		tv.AddTab(widg, label)
+		tv.SelectTabByName(label)
+		delete(ss.Detached, label)
+	})
+	win.GoStartEventLoop()
+	return nil
+}
+
+// DockAll closes all the Detached windows, moving their contents back into
+// the tab view
+func (ss *Sim) DockAll() {
+	for _, win := range ss.Detached {
+		win.Close()
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// The devaluation protocol (RunDeval) tests whether action selection is
+// goal-directed or habitual: after training, the Outcome of item DevalItem
+// is devalued, so its Goal is never set.  The network is optionally trained
+// for DevalTrainEpcs further epochs in this state, and then the Motor output
+// to the item's Context without its Goal is compared to the output with the
+// Goal set (the action leading to the devalued Outcome).  If the action is
+// still emitted, behavior is habitual (driven by the Context); if it is
+// not, it was goal-directed.  Each run adds a row to DevalLog.
+
+// GoalClampRow returns whether the Goal is clamped on the motor-production
+// AlphaCycle for given row, taking into account delays and devaluation
+func (ss *Sim) GoalClampRow(row int) bool {
+	if ss.Devalued && row == ss.DevalItem {
+		return false
+	}
+	return ss.GoalClampAfterDelay()
+}
+
+// DevalProbe runs one motor-production AlphaCycle of testing for given row
+// of given table, with the Context clamped and the Goal set (clamped to the
+// Outcome pattern) only if goal is true.  Returns the Motor minus phase
+// activations.
+func (ss *Sim) DevalProbe(et *etable.Table, row int, goal bool) []float32 {
+	c, _ := et.ColByName("Context").(*etensor.Float32).SubSpace(2, []int{row})
+	var g etensor.Tensor
+	if goal {
+		g = RowCell(et.ColByName("Outcome"), row)
+	}
+	ss.ProbeCyc(c, g)
+	acts, _ := ss.Net.LayerByName("Motor").(*leabra.Layer).UnitVals("ActM")
+	return acts
+}
+
+// DevalTest tests the current network on the devalued item, returning
+// whether the action leading to the devalued Outcome is still emitted
+// without its Goal, and the habitual index: the cosine between the Motor
+// output without vs. with the Goal.  The goal-directed index is 1 - habitual.
+func (ss *Sim) DevalTest(et *etable.Table) (act, noGoalAct int, habit float32) {
+	motorLay := ss.Net.LayerByName("Motor").(*leabra.Layer)
+	wgoal := ss.DevalProbe(et, ss.DevalItem, true)
+	act = ss.ActIdx(motorLay, "ActM")
+	nogoal := ss.DevalProbe(et, ss.DevalItem, false)
+	noGoalAct = ss.ActIdx(motorLay, "ActM")
+	habit = Cosine(nogoal, wgoal)
+	return
+}
+
+// RunDeval runs the devaluation protocol: devalues DevalItem, trains for
+// DevalTrainEpcs epochs with its Goal never set, tests, and logs the result
+// to DevalLog.  Devaluation is removed at the end, so subsequent training
+// is back to normal.
+func (ss *Sim) RunDeval() {
+	if ss.DevalItem < 0 || ss.DevalItem >= ss.ExtReps.NumRows() {
+		return
+	}
+	ss.StopNow = false
+	ss.Devalued = true
+	for epc := 0; epc < ss.DevalTrainEpcs; epc++ {
+		ss.TrainEpoch()
+		if ss.StopNow {
+			break
+		}
+	}
+	act, nga, habit := ss.DevalTest(ss.ExtReps)
+	ss.Devalued = false
+
+	dt := ss.DevalLog
+	row := dt.NumRows()
+	dt.SetNumRows(row + 1)
+	emit := 0.0
+	if nga == act {
+		emit = 1
+	}
+	dt.ColByName("Epoch").SetFloat1D(row, float64(ss.Epoch))
+	dt.ColByName("Item").SetFloat1D(row, float64(ss.DevalItem))
+	dt.ColByName("Action").SetFloat1D(row, float64(act))
+	dt.ColByName("NoGoalAction").SetFloat1D(row, float64(nga))
+	dt.ColByName("Emit").SetFloat1D(row, emit)
+	dt.ColByName("HabitIdx").SetFloat1D(row, float64(habit))
+	dt.ColByName("GoalDirIdx").SetFloat1D(row, float64(1-habit))
+}
+
+// ConfigDevalLog configures the DevalLog table
+func (ss *Sim) ConfigDevalLog() {
+	ss.DevalLog.SetFromSchema(etable.Schema{
+		{"Epoch", etensor.INT64, nil, nil},
+		{"Item", etensor.INT64, nil, nil},
+		{"Action", etensor.INT64, nil, nil},
+		{"NoGoalAction", etensor.INT64, nil, nil},
+		{"Emit", etensor.FLOAT32, nil, nil},
+		{"HabitIdx", etensor.FLOAT32, nil, nil},
+		{"GoalDirIdx", etensor.FLOAT32, nil, nil},
+	}, 0)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// drift.go measures representational drift: how much the activation
+// patterns for the same items change over training.  Every DriftInterval
+// training epochs (a checkpoint), all the ExtReps items are tested (learning
+// off) and the ActM of each of the DriftLays is recorded per item.  A row is
+// added to DriftLog with, for each layer, the drift since the previous
+// checkpoint (<Lay>Drift) and since the first one (<Lay>RefDrift), as
+// 1 - the cosine between the two patterns of each item, averaged over items.
+// Stable goal / motor codes have drift near 0.
+
+import (
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// DriftActs tests all the items of given table in order (learning off),
+// returning the ActM of each of the DriftLays for each item, indexed by
+// layer then row
+func (ss *Sim) DriftActs(et *etable.Table) [][][]float32 {
+	cbuf, pact := ss.CarryBuf, ss.PrevAct
+	ss.CarryReset()
+	defer func() { ss.CarryBuf, ss.PrevAct = cbuf, pact }()
+	env := &Env{Nm: "DriftEnv", Order: Sequential}
+	env.Init(et)
+	nr := env.NumRows()
+	acts := make([][][]float32, len(ss.DriftLays))
+	for li := range acts {
+		acts[li] = make([][]float32, nr)
+	}
+	for trl := 0; trl < nr; trl++ {
+		row := env.Row()
+		ss.TestTrial(env, true)
+		for li, lnm := range ss.DriftLays {
+			ly, ok := ss.Net.LayerByName(lnm).(*leabra.Layer)
+			if !ok {
+				continue
+			}
+			a, _ := ly.UnitVals("ActM")
+			acts[li][row] = append([]float32{}, a...)
+		}
+	}
+	return acts
+}
+
+// DriftAvg returns the average over items of 1 - the cosine between the
+// patterns of each item in a and b -- 0 if there are none
+func DriftAvg(a, b [][]float32) float32 {
+	var sum float32
+	n := 0
+	for i := range a {
+		if i >= len(b) || a[i] == nil || b[i] == nil {
+			continue
+		}
+		sum += 1 - Cosine(a[i], b[i])
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float32(n)
+}
+
+// CheckDrift runs a drift checkpoint (LogDrift) if DriftInterval epochs are
+// done -- called at the end of each training epoch
+func (ss *Sim) CheckDrift() {
+	if ss.DriftInterval <= 0 || (ss.Epoch+1)%ss.DriftInterval != 0 {
+		return
+	}
+	ss.LogDrift()
+}
+
+// LogDrift records the DriftLays activations of all the ExtReps items at
+// the current epoch, adding a row to DriftLog with the drift per layer since
+// the previous checkpoint and since the first one
+func (ss *Sim) LogDrift() {
+	acts := ss.DriftActs(ss.ExtReps)
+	if ss.DriftRef == nil {
+		ss.DriftRef = acts
+		ss.DriftRefEpc = ss.Epoch
+		ss.DriftPrev = acts
+		ss.DriftPrevEpc = ss.Epoch
+	}
+	dt := ss.DriftLog
+	row := dt.NumRows()
+	dt.SetNumRows(row + 1)
+	dt.ColByName("Epoch").SetFloat1D(row, float64(ss.Epoch))
+	dt.ColByName("PrevEpoch").SetFloat1D(row, float64(ss.DriftPrevEpc))
+	dt.ColByName("RefEpoch").SetFloat1D(row, float64(ss.DriftRefEpc))
+	for li, lnm := range ss.DriftLays {
+		dt.ColByName(lnm+"Drift").SetFloat1D(row, float64(DriftAvg(ss.DriftPrev[li], acts[li])))
+		dt.ColByName(lnm+"RefDrift").SetFloat1D(row, float64(DriftAvg(ss.DriftRef[li], acts[li])))
+	}
+	ss.DriftPrev = acts
+	ss.DriftPrevEpc = ss.Epoch
+}
+
+// ResetDrift clears the drift checkpoints and reconfigures the DriftLog
+// for the current DriftLays -- called in Init
+func (ss *Sim) ResetDrift() {
+	ss.DriftRef = nil
+	ss.DriftPrev = nil
+	ss.ConfigDriftLog()
+}
+
+// ConfigDriftLog configures the DriftLog table, with a Drift and RefDrift
+// column per DriftLays
+func (ss *Sim) ConfigDriftLog() {
+	sc := etable.Schema{
+		{"Epoch", etensor.INT64, nil, nil},
+		{"PrevEpoch", etensor.INT64, nil, nil},
+		{"RefEpoch", etensor.INT64, nil, nil},
+	}
+	for _, lnm := range ss.DriftLays {
+		sc = append(sc, etable.Column{lnm + "Drift", etensor.FLOAT32, nil, nil})
+		sc = append(sc, etable.Column{lnm + "RefDrift", etensor.FLOAT32, nil, nil})
+	}
+	ss.DriftLog.SetFromSchema(sc, 0)
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"math/rand"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// Drive states (DriveOn): a Drive input layer, with one unit per drive
+// (e.g., hunger, thirst), projects to the Goal layer and combines with the
+// Context to determine the currently desired Outcome.  Each trial the
+// environment samples a drive state at random, and the Outcome of the item
+// is the one for that drive in the DriveOuts table (rows are item *
+// NDrives + drive), where drive 0 has the original ExtReps Outcome.  The
+// Goal selected on the 1st AlphaCycle from Context and Drive is thus
+// state-dependent, and its errors are recorded per drive in DriveStats.
+
+// NDrives returns the number of drive states
+func (ss *Sim) NDrives() int {
+	return len(ss.DriveNames)
+}
+
+// ConfigDrives generates the DriveOuts table of per-drive Outcomes for each
+// item in ExtReps, and configures DriveStats -- called in Config if DriveOn
+func (ss *Sim) ConfigDrives() {
+	nd := ss.NDrives()
+	nr := ss.ExtReps.NumRows()
+	dt := ss.DriveOuts
+	oshp, onms := ss.OutShape()
+	dt.SetFromSchema(etable.Schema{
+		{"Outcome", etensor.FLOAT32, oshp, onms},
+	}, nr*nd)
+	ss.GenOutPats(dt.Cols[0])
+	oc := ss.ExtReps.ColByName("Outcome")
+	doc := dt.ColByName("Outcome")
+	_, cells := oc.RowCellSize()
+	for row := 0; row < nr; row++ {
+		for i := 0; i < cells; i++ {
+			doc.SetFloat1D(row*nd*cells+i, oc.FloatVal1D(row*cells+i))
+		}
+	}
+	ss.DriveInput = etensor.NewFloat32([]int{1, nd}, nil, []string{"Y", "X"})
+	ss.DriveOut = etensor.NewFloat32(oshp, nil, onms)
+
+	st := ss.DriveStats
+	st.SetFromSchema(etable.Schema{
+		{"Drive", etensor.STRING, nil, nil},
+		{"N", etensor.INT64, nil, nil},
+		{"MotSSE", etensor.FLOAT32, nil, nil},
+		{"OutGoalPctErr", etensor.FLOAT32, nil, nil},
+	}, nd)
+	for d, nm := range ss.DriveNames {
+		st.ColByName("Drive").SetString1D(d, nm)
+	}
+	ss.DriveSums = make([]DriveSum, nd)
+}
+
+// NewTrialDrive samples the drive state for the current trial, and sets
+// the DriveInput pattern and desired DriveOut Outcome for given item row
+func (ss *Sim) NewTrialDrive(row int) {
+	if !ss.DriveOn || ss.NDrives() == 0 {
+		return
+	}
+	nd := ss.NDrives()
+	ss.TrialDrive = rand.Intn(nd)
+	ss.DriveInput.SetZeros()
+	ss.DriveInput.Values[ss.TrialDrive] = 1
+	o := RowCell(ss.DriveOuts.ColByName("Outcome"), row*nd+ss.TrialDrive)
+	copy(ss.DriveOut.Values, o.(*etensor.Float32).Values)
+}
+
+// DriveSum accumulates stats over an epoch for one drive state
+type DriveSum struct {
+	N       int
+	MotSSE  float32
+	GoalErr int
+}
+
+// DriveStatsAdd accumulates the trial stats for the current TrialDrive
+func (ss *Sim) DriveStatsAdd(msse float32, outgoalerr bool) {
+	if !ss.DriveOn || ss.TrialDrive >= len(ss.DriveSums) {
+		return
+	}
+	ds := &ss.DriveSums[ss.TrialDrive]
+	ds.N++
+	ds.MotSSE += msse
+	if outgoalerr {
+		ds.GoalErr++
+	}
+}
+
+// LogDriveStats records the epoch averages of the stats per drive into
+// DriveStats, and resets the sums -- called in LogEpoch
+func (ss *Sim) LogDriveStats() {
+	if !ss.DriveOn {
+		return
+	}
+	st := ss.DriveStats
+	for d := range ss.DriveSums {
+		ds := &ss.DriveSums[d]
+		n := float32(ds.N)
+		if n == 0 {
+			n = 1
+		}
+		st.ColByName("N").SetFloat1D(d, float64(ds.N))
+		st.ColByName("MotSSE").SetFloat1D(d, float64(ds.MotSSE/n))
+		st.ColByName("OutGoalPctErr").SetFloat1D(d, float64(float32(ds.GoalErr)/n))
+		*ds = DriveSum{}
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// dwtclip.go has the DWt clipping and stats, for diagnosing instability
+// when the learning rate or Gi params are pushed: after each DWt, the
+// magnitude of the DWt of each synapse is recorded per projection, before
+// it is clipped to +/- DWtClip if set.  At the end of each epoch, the max
+// and mean |DWt| of each projection, and the percent of its DWt's that
+// were clipped, are appended to the DWtLog, and the max and mean over all
+// projections are logged as MaxDWt and MeanDWt in the EpcLog.
+
+import (
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// DWtSum accumulates the DWt stats over an epoch for one projection
+type DWtSum struct {
+	Max   float32
+	Sum   float32
+	N     int
+	NClip int
+}
+
+// ConfigDWtLog sets up the DWtLog table of per-projection DWt stats per epoch
+func (ss *Sim) ConfigDWtLog() {
+	ss.DWtLog.SetFromSchema(etable.Schema{
+		{"Epoch", etensor.INT64, nil, nil},
+		{"Prjn", etensor.STRING, nil, nil},
+		{"MaxDWt", etensor.FLOAT32, nil, nil},
+		{"MeanDWt", etensor.FLOAT32, nil, nil},
+		{"PctClip", etensor.FLOAT32, nil, nil},
+	}, 0)
+	ss.DWtSums = nil
+}
+
+// ClipDWts records the |DWt| stats of each projection, and clips the DWt's
+// to +/- DWtClip if > 0 -- called after DWt and prior to WtFmDWt in AlphaCyc
+func (ss *Sim) ClipDWts() {
+	pjs := ss.AllPrjns()
+	if len(ss.DWtSums) != len(pjs) {
+		ss.DWtSums = make([]DWtSum, len(pjs))
+	}
+	clip := ss.DWtClip
+	for pi, pj := range pjs {
+		ds := &ss.DWtSums[pi]
+		for si := range pj.Syns {
+			sy := &pj.Syns[si]
+			ad := sy.DWt
+			if ad < 0 {
+				ad = -ad
+			}
+			if ad > ds.Max {
+				ds.Max = ad
+			}
+			ds.Sum += ad
+			ds.N++
+			if clip <= 0 || ad <= clip {
+				continue
+			}
+			ds.NClip++
+			if sy.DWt > 0 {
+				sy.DWt = clip
+			} else {
+				sy.DWt = -clip
+			}
+		}
+	}
+}
+
+// LogDWtStats appends the epoch's DWt stats of each projection to the
+// DWtLog, and sets the EpcMaxDWt and EpcMeanDWt over all projections
+func (ss *Sim) LogDWtStats() {
+	dt := ss.DWtLog
+	var max, sum float32
+	n := 0
+	for pi, pj := range ss.AllPrjns() {
+		if pi >= len(ss.DWtSums) {
+			break
+		}
+		ds := &ss.DWtSums[pi]
+		mean, pclip := float32(0), float32(0)
+		if ds.N > 0 {
+			mean = ds.Sum / float32(ds.N)
+			pclip = 100 * float32(ds.NClip) / float32(ds.N)
+		}
+		row := dt.NumRows()
+		dt.AddRows(1)
+		dt.ColByName("Epoch").SetFloat1D(row, float64(ss.Epoch))
+		dt.ColByName("Prjn").SetString1D(row, PrjnPath(pj))
+		dt.ColByName("MaxDWt").SetFloat1D(row, float64(ds.Max))
+		dt.ColByName("MeanDWt").SetFloat1D(row, float64(mean))
+		dt.ColByName("PctClip").SetFloat1D(row, float64(pclip))
+		if ds.Max > max {
+			max = ds.Max
+		}
+		sum += ds.Sum
+		n += ds.N
+		*ds = DWtSum{}
+	}
+	ss.EpcMaxDWt = max
+	ss.EpcMeanDWt = 0
+	if n > 0 {
+		ss.EpcMeanDWt = sum / float32(n)
+	}
+}
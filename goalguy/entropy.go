@@ -0,0 +1,60 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// entropy.go has the Motor selection entropy stat: the entropy (in bits)
+// of the distribution of the actions decoded from the Motor layer over the
+// trials of an epoch (MotEntropy in EpcLog), and of the last TestAll or
+// Validate (TstActCnts: TstMotEntropy, ValMotEntropy).  It ranges from 0, when
+// the same action is selected on every trial -- the collapse onto a single
+// action that happens with k=1 inhibition -- up to log2(NActs) when all the
+// actions are selected equally often.
+
+import (
+	"github.com/chewxy/math32"
+	"github.com/emer/leabra/leabra"
+)
+
+// Entropy returns the entropy in bits of the distribution given by counts
+// -- 0 if they are all 0
+func Entropy(counts []float32) float32 {
+	var sum float32
+	for _, c := range counts {
+		sum += c
+	}
+	if sum == 0 {
+		return 0
+	}
+	var h float32
+	for _, c := range counts {
+		if c > 0 {
+			p := c / sum
+			h -= p * math32.Log2(p)
+		}
+	}
+	return h
+}
+
+// MotActAdd counts the action decoded from the Motor minus phase activity
+// on the motor-production AlphaCycle of the current training trial
+func (ss *Sim) MotActAdd() {
+	na := ss.NActs()
+	if len(ss.MotActCnts) != na {
+		ss.MotActCnts = make([]float32, na)
+	}
+	act := ss.ActIdx(ss.Net.LayerByName("Motor").(*leabra.Layer), "ActM")
+	if act >= 0 && act < na {
+		ss.MotActCnts[act]++
+	}
+}
+
+// LogMotEntropy sets EpcMotEntropy from the epoch's decoded action counts,
+// and resets them -- called in LogEpoch
+func (ss *Sim) LogMotEntropy() {
+	ss.EpcMotEntropy = Entropy(ss.MotActCnts)
+	for i := range ss.MotActCnts {
+		ss.MotActCnts[i] = 0
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// env.go has the Env environment, which holds everything about where the
+// items of a trial loop come from: the pattern table, the order in which its
+// rows are presented, the position within the current epoch, and the noise
+// added to the Context input.  The Sim has independent TrainEnv and TestEnv
+// instances, so training and testing can use different pattern tables,
+// orders and noise, and running a test in the middle of training does not
+// disturb the training epoch.
+
+import (
+	"log"
+	"math/rand"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// Env is a source of trial items: rows of a pattern table, in a given order
+type Env struct {
+	Nm     string           `desc:"name of this environment"`
+	Table  *etable.Table    `view:"-" desc:"pattern table the items are drawn from -- ExtReps format"`
+	Order  Orders           `desc:"order in which to present items within each epoch"`
+	Noise  float32          `desc:"standard deviation of gaussian noise added to the Context input pattern -- 0 = none"`
+	Porder []int            `view:"-" inactive:"+" desc:"row order for current epoch, per Order"`
+	Trial  int              `inactive:"+" desc:"current trial within the epoch -- index into Porder"`
+	NoisyC *etensor.Float32 `view:"-" desc:"Context pattern with noise added, if Noise > 0"`
+	OutDec Decoder          `view:"-" desc:"decoder of the Outcome patterns of the Table, as of when it was set -- for the forced-choice OutClass stat"`
+	Hist   [][]int          `view:"-" desc:"Porder of each epoch since the Table was set -- the presentation order history"`
+	Replay [][]int          `view:"-" desc:"if set, Porders of successive epochs to replay instead of the Order, e.g., from the Hist of an earlier run"`
+
+	ReplayEpc int `view:"-" desc:"next epoch of Replay"`
+}
+
+// Init starts a new epoch of given table (if non-nil, in which case the
+// OutDec is initialized from its Outcome patterns and the Hist and Replay
+// start over), with a new Porder
+func (ev *Env) Init(et *etable.Table) {
+	if et != nil {
+		ev.Table = et
+		ev.OutDec.InitFromTable(et, "Outcome")
+		ev.Hist = nil
+		ev.ReplayEpc = 0
+	}
+	ev.Trial = 0
+	ev.NewPorder()
+}
+
+// NumRows returns the number of rows in the pattern table
+func (ev *Env) NumRows() int {
+	if ev.Table == nil {
+		return 0
+	}
+	return ev.Table.NumRows()
+}
+
+// Row returns the pattern table row of the current trial, -1 if the table
+// is empty.  A new Porder is started if the table changed size since the
+// last NewPorder.
+func (ev *Env) Row() int {
+	if ev.NumRows() == 0 {
+		return -1
+	}
+	if ev.Trial >= len(ev.Porder) {
+		ev.Init(nil)
+	}
+	return ev.Porder[ev.Trial]
+}
+
+// Step advances to the next trial, returning true (and starting a new
+// Porder) at the end of the epoch
+func (ev *Env) Step() bool {
+	ev.Trial++
+	if ev.Trial < ev.NumRows() {
+		return false
+	}
+	ev.Init(nil)
+	return true
+}
+
+// CtxtInput returns the Context input pattern given, with Noise added if > 0
+func (ev *Env) CtxtInput(c etensor.Tensor) etensor.Tensor {
+	if ev.Noise <= 0 {
+		return c
+	}
+	if ev.NoisyC == nil || ev.NoisyC.Len() != c.Len() {
+		ev.NoisyC = etensor.NewFloat32(c.Shapes(), nil, nil)
+	}
+	for i := range ev.NoisyC.Values {
+		ev.NoisyC.Values[i] = float32(c.FloatVal1D(i) + rand.NormFloat64()*float64(ev.Noise))
+	}
+	return ev.NoisyC
+}
+
+// ConfigEnvs sets the pattern tables of the TrainEnv (ExtReps), the ValEnv
+// (ValReps) and the TestEnv (ValReps, or ExtReps if ValReps is empty) -- or
+// the train, validation and test splits of the ExtReps if SplitOn, or if
+// ValInterval > 0 and there are no ValReps, so validation is never on the
+// training items -- and starts new epochs of all of them
+func (ss *Sim) ConfigEnvs() {
+	ss.TrainEnv.Nm = "TrainEnv"
+	if err := ss.OpenReplayFile(); err != nil {
+		log.Println(err)
+	}
+	trn, val, tst := ss.ExtReps, ss.ValReps, ss.ValReps
+	if val.NumRows() == 0 {
+		tst = ss.ExtReps
+	}
+	if ss.SplitOn || (ss.ValInterval > 0 && val.NumRows() == 0) {
+		if ss.DriveOn {
+			log.Println("ConfigEnvs: the ExtReps split is ignored if DriveOn, as the DriveOuts are per ExtReps row")
+		} else {
+			trn, val, tst = ss.SplitPats()
+			if tst.NumRows() == 0 {
+				log.Println("ConfigEnvs: empty test split -- TestAll uses all the ExtReps")
+				tst = ss.ExtReps
+			}
+		}
+	}
+	ss.TrainEnv.Init(trn)
+	ss.ValEnv.Nm = "ValEnv"
+	ss.ValEnv.Init(val)
+	ss.TestEnv.Nm = "TestEnv"
+	ss.TestEnv.Init(tst)
+}
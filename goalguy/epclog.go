@@ -0,0 +1,127 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// epclog.go streams the EpcLog to disk as training proceeds: if EpcLogFile
+// is set, the file is (re)created with a header row at Init, and each epoch
+// row is appended to it at the end of LogEpoch, so a long run that crashes
+// still has all the epochs up to that point.  If EpcLogMax is also set,
+// only the most recent EpcLogMax epochs are kept in the in-memory EpcLog
+// (for the plot), so memory does not grow without bound -- the full log is
+// in the file.  EpcLogOff is the number of rows dropped so far.
+//
+// Rows are appended to the EpcLog (AddRows) rather than indexed by Epoch, and
+// its columns are preallocated at Init for all the epochs expected, so they
+// are not reallocated as the log grows.  For very long runs, EpcLogEvery
+// downsamples the log to every Nth epoch: the row of an epoch that is not
+// kept is overwritten by the next epoch.
+
+import (
+	"log"
+	"os"
+
+	"github.com/emer/etable/etensor"
+)
+
+// OpenEpcLogFile creates the EpcLogFile, writing the EpcLog column headers
+// -- closes any previously open file, and does nothing if EpcLogFile is empty
+func (ss *Sim) OpenEpcLogFile() error {
+	ss.CloseEpcLogFile()
+	if ss.EpcLogFile == "" {
+		return nil
+	}
+	f, err := os.Create(ss.EpcLogFile)
+	if err != nil {
+		return err
+	}
+	ss.EpcLogW = f
+	_, err = ss.EpcLog.WriteCSVHeaders(f, TableDelim)
+	return err
+}
+
+// CloseEpcLogFile closes the EpcLogFile, if open
+func (ss *Sim) CloseEpcLogFile() {
+	if ss.EpcLogW == nil {
+		return
+	}
+	ss.EpcLogW.Close()
+	ss.EpcLogW = nil
+}
+
+// PreallocEpcLog empties the EpcLog, preallocating its columns for the
+// number of rows expected in the run: MaxEpcs / EpcLogEvery, or at most
+// 2*EpcLogMax if the older rows are dropped -- the columns keep their
+// capacity when shrunk
+func (ss *Sim) PreallocEpcLog() {
+	n := ss.MaxEpcs
+	if ss.EpcLogEvery > 1 {
+		n = n/ss.EpcLogEvery + 1
+	}
+	if ss.EpcLogFile != "" && ss.EpcLogMax > 0 && n > 2*ss.EpcLogMax {
+		n = 2 * ss.EpcLogMax
+	}
+	ss.EpcLog.SetNumRows(n)
+	ss.EpcLog.SetNumRows(0)
+}
+
+// EpcLogKeep returns whether the current Epoch is kept in the EpcLog, per
+// EpcLogEvery
+func (ss *Sim) EpcLogKeep() bool {
+	return ss.EpcLogEvery <= 1 || (ss.Epoch+1)%ss.EpcLogEvery == 0
+}
+
+// EpcLogRow returns the row of the in-memory EpcLog for the current Epoch:
+// the last row if it holds an epoch not kept per EpcLogEvery, else a new row
+// appended after first dropping the oldest rows if EpcLogMax is exceeded
+func (ss *Sim) EpcLogRow() int {
+	et := ss.EpcLog
+	if ss.EpcLogTmp && et.NumRows() > 0 {
+		ss.EpcLogTmp = false
+		return et.NumRows() - 1
+	}
+	ss.EpcLogTmp = false
+	if ss.EpcLogW != nil && ss.EpcLogMax > 0 && et.NumRows() >= 2*ss.EpcLogMax {
+		ss.TrimEpcLog(et.NumRows() - ss.EpcLogMax)
+	}
+	et.AddRows(1)
+	return et.NumRows() - 1
+}
+
+// TrimEpcLog drops the first n rows of the in-memory EpcLog -- only done in
+// chunks of EpcLogMax epochs, so the cost of the copy is amortized
+func (ss *Sim) TrimEpcLog(n int) {
+	et := ss.EpcLog
+	nr := et.NumRows()
+	if n <= 0 || n > nr {
+		return
+	}
+	for _, col := range et.Cols {
+		_, cells := col.RowCellSize()
+		str := col.DataType() == etensor.STRING
+		for i := 0; i < (nr-n)*cells; i++ {
+			if str {
+				col.SetString1D(i, col.StringVal1D(n*cells+i))
+			} else {
+				col.SetFloat1D(i, col.FloatVal1D(n*cells+i))
+			}
+		}
+	}
+	et.SetNumRows(nr - n)
+	ss.EpcLogOff += n
+}
+
+// WriteEpcLogRow appends given row of the EpcLog to the EpcLogFile, if
+// open -- called at the end of LogEpoch
+func (ss *Sim) WriteEpcLogRow(row int) {
+	if ss.EpcLogW == nil {
+		return
+	}
+	if err := ss.EpcLog.WriteCSVRow(ss.EpcLogW, row, TableDelim); err != nil {
+		log.Println(err)
+		ss.CloseEpcLogFile()
+		return
+	}
+	ss.EpcLogW.Sync()
+}
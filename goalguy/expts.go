@@ -0,0 +1,133 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"fmt"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/leabra/leabra"
+)
+
+// Expt is a named experiment preset, bundling the configuration, params
+// and pattern generation needed to reproduce a canonical condition
+type Expt struct {
+	Name   string          `desc:"name of the experiment, as given to the -expt flag"`
+	Desc   string          `desc:"description of the experiment"`
+	Params emer.ParamStyle `desc:"params to use for this experiment"`
+	Config func(ss *Sim)   `desc:"sets the Sim config fields (network, pattern generation) for this experiment -- called prior to ConfigNet and ConfigExtReps"`
+}
+
+// ExptParams returns a copy of DefaultParams with the given Outcome layer
+// inhibition, for k = the number of active units in the Outcome patterns,
+// and without the params of the given sels, e.g., for projections the
+// experiment's network does not have
+func ExptParams(outGi float32, drop ...string) emer.ParamStyle {
+	ps := emer.ParamStyle{}
+	for _, psel := range CopyParams(DefaultParams) {
+		keep := true
+		for _, d := range drop {
+			if psel.Sel == d {
+				keep = false
+			}
+		}
+		if keep {
+			ps = append(ps, psel)
+		}
+	}
+	return SetParamVal(ps, "#Outcome", "Layer.Inhib.Layer.Gi", outGi)
+}
+
+var (
+	// LocalistParams are the params of the localist experiment: strong
+	// Outcome inhibition for the single active unit (k = 1)
+	LocalistParams = ExptParams(2.2)
+
+	// DistParams are the params of the 3-of-25 distributed experiments:
+	// weaker Outcome inhibition, for k = 3
+	DistParams = ExptParams(1.8)
+
+	// ForwardParams are the DistParams without the .Back projection params,
+	// as the forward-only network has no back projections
+	ForwardParams = ExptParams(1.8, ".Back")
+)
+
+// Expts is the registry of experiment presets, in the order shown in the gui
+var Expts = []*Expt{
+	{Name: "phase0-localist", Desc: "Phase 0: single-unit (localist) Context and Outcome patterns, full architecture",
+		Params: LocalistParams, Config: func(ss *Sim) {
+			ss.PatNOn = 1
+			ss.OutMotBack = true
+		}},
+	{Name: "phase0.5-distributed", Desc: "Phase 0.5: 3-of-25 distributed Context and Outcome patterns, full architecture",
+		Params: DistParams, Config: func(ss *Sim) {
+			ss.PatNOn = 3
+			ss.OutMotBack = true
+		}},
+	{Name: "forward-only", Desc: "3-of-25 distributed patterns, without the Outcome -> Motor back projection",
+		Params: ForwardParams, Config: func(ss *Sim) {
+			ss.PatNOn = 3
+			ss.OutMotBack = false
+		}},
+	{Name: "goal-out-direct", Desc: "3-of-25 distributed patterns, full architecture plus a direct Goal -> Outcome projection",
+		Params: DistParams, Config: func(ss *Sim) {
+			ss.PatNOn = 3
+			ss.OutMotBack = true
+			ss.NetVariant = GoalOutDirect
+		}},
+	{Name: "mot-goal-back", Desc: "3-of-25 distributed patterns, full architecture plus a Motor -> Goal back projection",
+		Params: DistParams, Config: func(ss *Sim) {
+			ss.PatNOn = 3
+			ss.OutMotBack = true
+			ss.NetVariant = MotGoalBack
+		}},
+}
+
+// ExptNames returns the names of all registered experiments, in order
+func ExptNames() []string {
+	nms := make([]string, len(Expts))
+	for i, ex := range Expts {
+		nms[i] = ex.Name
+	}
+	return nms
+}
+
+// ExptByName returns the experiment preset of given name, or error if not found
+func ExptByName(name string) (*Expt, error) {
+	for _, ex := range Expts {
+		if ex.Name == name {
+			return ex, nil
+		}
+	}
+	return nil, fmt.Errorf("experiment %q not found -- valid names: %v", name, ExptNames())
+}
+
+// SetExpt sets the config fields and params for the given named experiment.
+// Must be followed by Config (or ReConfig once running) to take effect.
+func (ss *Sim) SetExpt(name string) error {
+	ex, err := ExptByName(name)
+	if err != nil {
+		return err
+	}
+	ss.Expt = ex.Name
+	ss.Params = CopyParams(ex.Params)
+	ss.NetVariant = BaseNet // only set by the experiments using another
+	ss.PatMinDiff, ss.PatOverlap = 0, 0
+	ss.MapMode = OneToOne
+	if ex.Config != nil {
+		ex.Config(ss)
+	}
+	return nil
+}
+
+// ReConfig regenerates the patterns, rebuilds the network and re-initializes,
+// e.g., after selecting a different experiment in the gui
+func (ss *Sim) ReConfig() {
+	ss.ConfigExtReps()
+	ss.Net = &leabra.Network{}
+	ss.Config()
+	ss.Init()
+	ss.SetNetViewsNet()
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"github.com/emer/leabra/leabra"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// Extinction (ExtinctProtocol): after an acquisition phase with the
+// closed-loop Contings environment, the outcomes of the ExtinctActs actions
+// stop occurring (nothing is clamped on the Outcome in the plus phase),
+// and the decline of the corresponding Motor outputs is recorded in the
+// ExtinctActPct and ExtinctMotAct epoch stats.
+
+// IsExtinct returns whether the outcome of given Motor action is currently extinguished
+func (ss *Sim) IsExtinct(act int) bool {
+	if !ss.Extinct {
+		return false
+	}
+	for _, ea := range ss.ExtinctActs {
+		if ea == act {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtinctTrialStats accumulates the extinction stats for the current trial
+// from the Motor minus phase activity -- called in ContingPlusPhase if training
+func (ss *Sim) ExtinctTrialStats(act int) {
+	if len(ss.ExtinctActs) == 0 {
+		return
+	}
+	motorLay := ss.Net.LayerByName("Motor").(*leabra.Layer)
+	acts, _ := motorLay.UnitVals("ActM")
+	sum := float32(0)
+	for _, ea := range ss.ExtinctActs {
+		if ea == act {
+			ss.ExtinctActCnt++
+		}
+		if ea >= 0 && ea < len(acts) {
+			sum += acts[ea]
+		}
+	}
+	ss.ExtinctSumMotAct += sum / float32(len(ss.ExtinctActs))
+}
+
+// ExtinctProtocol is the extinction protocol: an acquisition phase of
+// AcqEpcs epochs with the closed-loop Contings environment, followed by an
+// extinction phase of ExtinctEpcs epochs in which the ExtinctActs actions
+// no longer produce their outcomes
+func ExtinctProtocol(ss *Sim) []*Phase {
+	return []*Phase{
+		{Name: "acquisition", Epochs: ss.AcqEpcs, Start: func(ss *Sim) {
+			ss.ContingOn = true
+			ss.Degraded = false
+			ss.Extinct = false
+		}},
+		{Name: "extinction", Epochs: ss.ExtinctEpcs, Start: func(ss *Sim) {
+			ss.ContingOn = true
+			ss.Degraded = false
+			ss.Extinct = true
+		}},
+	}
+}
+
+// PlotPhaseMarks adds a vertical line, labeled with the phase name, at the
+// start of each protocol phase recorded in the Phase column of the EpcLog
+func (ss *Sim) PlotPhaseMarks(plt *plot.Plot) {
+	et := ss.EpcLog
+	pc := et.ColByName("Phase")
+	prv := ""
+	var lxy plotter.XYs
+	var lbls []string
+	for r := 0; r < et.NumRows(); r++ {
+		ph := pc.StringVal1D(r)
+		if ph == prv {
+			continue
+		}
+		prv = ph
+		if ph == "" {
+			continue
+		}
+		x := et.ColByName("Epoch").FloatVal1D(r)
+		xy := plotter.XYs{{x, 0}, {x, 1}}
+		l, _ := plotter.NewLine(xy)
+		l.LineStyle.Width = vg.Points(CurPlotStyle.LineWidth)
+		l.LineStyle.Color = CurPlotStyle.FgColor()
+		l.LineStyle.Dashes = []vg.Length{vg.Points(4), vg.Points(4)}
+		plt.Add(l)
+		lxy = append(lxy, struct{ X, Y float64 }{x, 1})
+		lbls = append(lbls, ph)
+	}
+	if len(lbls) > 0 {
+		lb, _ := plotter.NewLabels(plotter.XYLabels{XYs: lxy, Labels: lbls})
+		plt.Add(lb)
+	}
+}
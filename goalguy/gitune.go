@@ -0,0 +1,110 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"fmt"
+
+	"github.com/chewxy/math32"
+
+	"github.com/emer/leabra/leabra"
+)
+
+// GiTuneTarg specifies the target number of active units (k) for one layer,
+// used by TuneGi to calibrate Layer.Inhib.Layer.Gi
+type GiTuneTarg struct {
+	Layer string  `desc:"name of layer to tune"`
+	K     float32 `desc:"target average number of units active (ActM > .5) per alpha cycle"`
+}
+
+// TuneGi runs a few settling trials (no learning) and adjusts the
+// Layer.Inhib.Layer.Gi of each layer in GiTuneTargs until the average
+// number of active units matches the target k, within GiTuneTol.
+// The found values are reported and set in the "#<layer>" ParamSel of
+// Params (added if there is none), so they are used on subsequent Init.
+// Returns an error if a GiTuneTargs layer does not exist.
+func (ss *Sim) TuneGi() error {
+	nr := ss.ExtReps.NumRows()
+	if nr == 0 || len(ss.GiTuneTargs) == 0 {
+		return nil
+	}
+	lays := make([]*leabra.Layer, len(ss.GiTuneTargs))
+	for i, tg := range ss.GiTuneTargs {
+		ly, ok := ss.Net.LayerByName(tg.Layer).(*leabra.Layer)
+		if !ok || ly == nil {
+			return fmt.Errorf("TuneGi: no layer named: %s", tg.Layer)
+		}
+		lays[i] = ly
+	}
+	avgk := make([]float32, len(lays))
+	for itr := 0; itr < ss.GiTuneMaxItrs; itr++ {
+		ss.GiTuneActive(lays, avgk)
+		done := true
+		for i, ly := range lays {
+			k := ss.GiTuneTargs[i].K
+			dk := avgk[i] - k
+			if math32.Abs(dk) <= ss.GiTuneTol {
+				continue
+			}
+			done = false
+			gi := ly.Inhib.Layer.Gi * (1 + 0.1*dk/math32.Max(k, 1))
+			if gi < 0.5 {
+				gi = 0.5
+			}
+			ly.Inhib.Layer.Gi = gi
+		}
+		if done {
+			break
+		}
+	}
+	ss.AlphaCycle = 0
+	ss.Params = CopyParams(ss.Params) // as for the "set" script command
+	for i, ly := range lays {
+		fmt.Printf("TuneGi: %s Gi: %g avg k: %g (target: %g)\n", ly.Nm, ly.Inhib.Layer.Gi, avgk[i], ss.GiTuneTargs[i].K)
+		ss.Params = SetParamVal(ss.Params, "#"+ly.Nm, "Layer.Inhib.Layer.Gi", ly.Inhib.Layer.Gi)
+	}
+	ss.UpdateView()
+	return nil
+}
+
+// GiTuneActive runs GiTuneTrials settling trials, both alpha cycles each,
+// without learning, and records in avgk the average number of units
+// with ActM > .5 per alpha cycle for each of the given layers.
+// As in TrainTrial, the ActP is stored after the ActPStep of the TrialSpec
+// to drive the later steps, and the patterns are restored after each trial.
+func (ss *Sim) GiTuneActive(lays []*leabra.Layer, avgk []float32) {
+	for i := range avgk {
+		avgk[i] = 0
+	}
+	et := ss.TrainEnv.Table
+	nr := et.NumRows()
+	if nr == 0 {
+		return
+	}
+	ts := ss.CurTrialSpec()
+	nc := 0
+	for trl := 0; trl < ss.GiTuneTrials; trl++ {
+		row := trl % nr
+		for ss.AlphaCycle = 0; ss.AlphaCycle < ss.NTrialSteps(); ss.AlphaCycle++ {
+			ss.ApplyInputs(&ss.TrainEnv, row)
+			ss.AlphaCyc(false)
+			if ss.AlphaCycle == ts.ActPStep() {
+				ss.StoreActP(et, row)
+			}
+			for i, ly := range lays {
+				for ni := range ly.Neurons {
+					if ly.Neurons[ni].ActM > 0.5 {
+						avgk[i]++
+					}
+				}
+			}
+			nc++
+		}
+		ss.RestoreClamps()
+	}
+	for i := range avgk {
+		avgk[i] /= float32(nc)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"github.com/emer/etable/eplot"
+	"github.com/emer/etable/etensor"
+	"github.com/goki/gi/svg"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette"
+	"gonum.org/v1/plot/plotter"
+)
+
+// TensorGrid adapts a 2D etensor.Tensor (Y, X) to the plotter.GridXYZ
+// interface, so it can be displayed as a color-grid heatmap
+type TensorGrid struct {
+	Tsr etensor.Tensor
+}
+
+// Dims returns the number of columns (X) and rows (Y)
+func (tg *TensorGrid) Dims() (c, r int) {
+	return tg.Tsr.Dim(1), tg.Tsr.Dim(0)
+}
+
+// Z returns the value at given column, row
+func (tg *TensorGrid) Z(c, r int) float64 {
+	return tg.Tsr.FloatVal([]int{r, c})
+}
+
+// X returns the coordinate of given column
+func (tg *TensorGrid) X(c int) float64 {
+	return float64(c)
+}
+
+// Y returns the coordinate of given row
+func (tg *TensorGrid) Y(r int) float64 {
+	return float64(r)
+}
+
+// TensorGridPlot returns the plot of the given 2D tensor as a color-grid
+// heatmap, with given title and axis labels -- nil if it is not 2D.  The
+// color range of a uniform tensor is widened by .5 on each side, as the
+// heatmap needs Min < Max.
+func TensorGridPlot(tsr etensor.Tensor, title, xlab, ylab string) *plot.Plot {
+	if tsr == nil || tsr.NumDims() != 2 {
+		return nil
+	}
+	plt := NewPlot()
+	plt.Title.Text = title
+	plt.X.Label.Text = xlab
+	plt.Y.Label.Text = ylab
+	hm := plotter.NewHeatMap(&TensorGrid{tsr}, palette.Heat(12, 1))
+	if hm.Min == hm.Max {
+		hm.Min -= 0.5
+		hm.Max += 0.5
+	}
+	plt.Add(hm)
+	return plt
+}
+
+// PlotTensorGrid plots the given 2D tensor as a color-grid heatmap into
+// given svg editor, with given title and axis labels.  Returns nil if
+// the editor is not visible.
+func PlotTensorGrid(svge *svg.Editor, tsr etensor.Tensor, title, xlab, ylab string) *plot.Plot {
+	if svge == nil || !svge.IsVisible() {
+		return nil
+	}
+	plt := TensorGridPlot(tsr, title, xlab, ylab)
+	if plt == nil {
+		return nil
+	}
+	eplot.PlotViewSVG(plt, svge, 5)
+	return plt
+}
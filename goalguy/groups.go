@@ -0,0 +1,92 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// groups.go has the per-group breakdown of the test stats: each item has a
+// Group label in the Group column of ExtReps (by default its valence class,
+// "appetitive" or "aversive", but it can be edited to any stimulus classes
+// of interest), and TestAll records the key stats per group in the
+// TstGrpLog, in addition to the overall Tst* stats -- so performance
+// differences across stimulus classes can be analyzed.  Tables without a
+// Group column have all items in the "all" group.
+
+import (
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// GroupSums are the sums of the test stats over the trials of one group
+type GroupSums struct {
+	N        int     `desc:"number of trials"`
+	GoalErr  int     `desc:"number of trials with the Outcome not matching the Goal"`
+	PredErr  int     `desc:"number of trials with an Outcome prediction error"`
+	MotSSE   float32 `desc:"sum of Motor SSE"`
+	OutSSE   float32 `desc:"sum of Outcome SSE"`
+	MotCDiff float32 `desc:"sum of Motor cosine difference"`
+	OutCDiff float32 `desc:"sum of Outcome cosine difference"`
+}
+
+// ItemGroup returns the Group of given row of given table -- "all" if it
+// has no Group column, or the Group is empty
+func ItemGroup(et *etable.Table, row int) string {
+	gc := et.ColByName("Group")
+	if gc == nil {
+		return "all"
+	}
+	if grp := gc.StringVal1D(row); grp != "" {
+		return grp
+	}
+	return "all"
+}
+
+// GenGroups sets the Group of each row of given table to its valence class
+func GenGroups(et *etable.Table) {
+	gc := et.ColByName("Group")
+	vc := et.ColByName("Valence")
+	if gc == nil || vc == nil {
+		return
+	}
+	for row := 0; row < et.NumRows(); row++ {
+		grp := "appetitive"
+		if vc.FloatVal1D(row) < 0 {
+			grp = "aversive"
+		}
+		gc.SetString1D(row, grp)
+	}
+}
+
+// ConfigTstGrpLog sets up the TstGrpLog table of per-group test stats
+func (ss *Sim) ConfigTstGrpLog() {
+	dt := ss.TstGrpLog
+	dt.SetFromSchema(etable.Schema{
+		{"Group", etensor.STRING, nil, nil},
+		{"N", etensor.INT64, nil, nil},
+		{"OutGoalPctErr", etensor.FLOAT32, nil, nil},
+		{"OutPredPctErr", etensor.FLOAT32, nil, nil},
+		{"MotSSE", etensor.FLOAT32, nil, nil},
+		{"OutSSE", etensor.FLOAT32, nil, nil},
+		{"MotCosDiff", etensor.FLOAT32, nil, nil},
+		{"OutCosDiff", etensor.FLOAT32, nil, nil},
+	}, 0)
+}
+
+// LogTstGrps writes the per-group averages of given sums into the
+// TstGrpLog, one row per group in given order -- called at the end of TestAll
+func (ss *Sim) LogTstGrps(grps []string, sums map[string]*GroupSums) {
+	dt := ss.TstGrpLog
+	dt.SetNumRows(len(grps))
+	for row, grp := range grps {
+		gs := sums[grp]
+		n := float64(gs.N)
+		dt.ColByName("Group").SetString1D(row, grp)
+		dt.ColByName("N").SetFloat1D(row, n)
+		dt.ColByName("OutGoalPctErr").SetFloat1D(row, float64(gs.GoalErr)/n)
+		dt.ColByName("OutPredPctErr").SetFloat1D(row, float64(gs.PredErr)/n)
+		dt.ColByName("MotSSE").SetFloat1D(row, float64(gs.MotSSE)/n)
+		dt.ColByName("OutSSE").SetFloat1D(row, float64(gs.OutSSE)/n)
+		dt.ColByName("MotCosDiff").SetFloat1D(row, float64(gs.MotCDiff)/n)
+		dt.ColByName("OutCosDiff").SetFloat1D(row, float64(gs.OutCDiff)/n)
+	}
+}
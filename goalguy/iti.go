@@ -0,0 +1,112 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// iti.go has the inter-trial interval (ITI): ItiCycs empty alpha cycles
+// between trials, with no input clamped and no learning, during which the
+// activity of the previous trial decays naturally.  The ITI is run at the
+// start of each trial, so the end-of-trial activity is still there for
+// the trial stats.  The activation reset at the start of each alpha cycle
+// (Act.Init.Decay) is turned off for the ITI, so the decay dynamics can be
+// watched in the NetView -- whether the residual activity at the end of
+// the ITI carries into the trial depends on the Act.Init.Decay params.
+// If ItiLog (set before Config), the average over the epoch's training
+// trials of the mean Act of each of the ItiLays at the end of the ITI is
+// logged in the <Lay>ItiAct columns of the EpcLog.
+
+import (
+	"github.com/emer/emergent/emer"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// ItiLays are the layers unclamped during the ITI, whose residual activity
+// is logged if ItiLog
+var ItiLays = []string{"Goal", "Motor", "Outcome"}
+
+// ItiCycles runs the ItiCycs alpha cycles of the inter-trial interval,
+// with no input clamped, no learning, and no activation reset at the start
+// of each alpha cycle, recording the residual activity at the end if train
+func (ss *Sim) ItiCycles(train bool) {
+	if ss.ItiCycs <= 0 {
+		return
+	}
+	ss.Net.InitExt()
+	for _, lnm := range ItiLays {
+		ss.Net.LayerByName(lnm).SetType(emer.Hidden)
+	}
+	decays := make([]float32, len(ss.Net.Layers))
+	for li, l := range ss.Net.Layers {
+		ly := l.(*leabra.Layer)
+		decays[li] = ly.Act.Init.Decay
+		ly.Act.Init.Decay = 0
+	}
+	for ss.ItiCyc = 0; ss.ItiCyc < ss.ItiCycs; ss.ItiCyc++ {
+		ss.AlphaCyc(false)
+	}
+	ss.ItiCyc = 0
+	for li, l := range ss.Net.Layers {
+		l.(*leabra.Layer).Act.Init.Decay = decays[li]
+	}
+	if train {
+		ss.ItiResidAdd()
+	}
+}
+
+// ItiResidAdd accumulates the mean Act of each of the ItiLays, if ItiLog
+func (ss *Sim) ItiResidAdd() {
+	if !ss.ItiLog {
+		return
+	}
+	if len(ss.ItiSums) != len(ItiLays) {
+		ss.ItiSums = make([]float32, len(ItiLays))
+	}
+	for li, lnm := range ItiLays {
+		acts, err := ss.Net.LayerByName(lnm).(*leabra.Layer).UnitVals("Act")
+		if err != nil || len(acts) == 0 {
+			continue
+		}
+		var sum float32
+		for _, a := range acts {
+			sum += a
+		}
+		ss.ItiSums[li] += sum / float32(len(acts))
+	}
+	ss.ItiN++
+}
+
+// ItiSchema returns the EpcLog columns for the ITI residual activity, if ItiLog
+func (ss *Sim) ItiSchema() etable.Schema {
+	var sc etable.Schema
+	if !ss.ItiLog {
+		return sc
+	}
+	for _, lnm := range ItiLays {
+		sc = append(sc, etable.Column{lnm + "ItiAct", etensor.FLOAT32, nil, nil})
+	}
+	return sc
+}
+
+// LogItiResid records the epoch averages of the ITI residual activity into
+// given row of the EpcLog, and resets the sums -- called in LogEpoch
+func (ss *Sim) LogItiResid(row int) {
+	if !ss.ItiLog {
+		return
+	}
+	n := float32(ss.ItiN)
+	if n == 0 {
+		n = 1
+	}
+	for li, lnm := range ItiLays {
+		col := ss.EpcLog.ColByName(lnm + "ItiAct")
+		if col == nil || li >= len(ss.ItiSums) {
+			continue
+		}
+		col.SetFloat1D(row, float64(ss.ItiSums[li]/n))
+		ss.ItiSums[li] = 0
+	}
+	ss.ItiN = 0
+}
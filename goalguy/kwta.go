@@ -0,0 +1,103 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/leabra/leabra"
+	"github.com/goki/ki/kit"
+)
+
+// InhibModes are the different inhibition modes that can be selected per layer
+type InhibModes int32
+
+//go:generate stringer -type=InhibModes
+
+var KiT_InhibModes = kit.Enums.AddEnum(InhibModesN, false, nil)
+
+const (
+	// FFFB uses the standard leabra feedforward-feedback inhibition only,
+	// with the amount of inhibition set by Layer.Inhib.Layer.Gi
+	FFFB InhibModes = iota
+
+	// KWTA enforces an explicit k-winners-take-all constraint after every
+	// cycle, on top of FFFB: only the K most active units keep their activation
+	KWTA
+
+	InhibModesN
+)
+
+// LayInhib specifies the inhibition mode for one layer
+type LayInhib struct {
+	Layer string     `desc:"name of layer"`
+	Mode  InhibModes `desc:"inhibition mode for this layer"`
+	K     int        `desc:"number of winners to allow for KWTA mode"`
+}
+
+// ConfigInhib checks the LayInhibs settings against the network,
+// called by ConfigNet after the network has been built.
+func (ss *Sim) ConfigInhib() {
+	for i := range ss.LayInhibs {
+		li := &ss.LayInhibs[i]
+		if li.Mode != KWTA {
+			continue
+		}
+		ly, ok := ss.Net.LayerByName(li.Layer).(*leabra.Layer)
+		if !ok || ly == nil {
+			fmt.Printf("ConfigInhib: layer %s not found\n", li.Layer)
+			continue
+		}
+		if li.K < 1 || li.K > len(ly.Neurons) {
+			fmt.Printf("ConfigInhib: layer %s K = %d out of range, using 1\n", li.Layer, li.K)
+			li.K = 1
+		}
+	}
+}
+
+// ApplyKWTA applies the KWTA constraint to all layers in KWTA mode,
+// called after each cycle within AlphaCyc.  Layers that are currently
+// clamped (Input, or Target in the plus phase) are not affected.
+func (ss *Sim) ApplyKWTA() {
+	for _, li := range ss.LayInhibs {
+		if li.Mode != KWTA {
+			continue
+		}
+		ly, ok := ss.Net.LayerByName(li.Layer).(*leabra.Layer)
+		if !ok || ly == nil {
+			continue
+		}
+		if ly.Typ == emer.Input || (ly.Typ == emer.Target && ss.Time.PlusPhase) {
+			continue
+		}
+		KWTALayer(ly, li.K)
+	}
+}
+
+// KWTALayer zeros the activation of all but the k most active units in
+// layer -- ties are broken in favor of the lower unit index, so exactly k
+// units are kept.  Does nothing if k <= 0.
+func KWTALayer(ly *leabra.Layer, k int) {
+	nn := len(ly.Neurons)
+	if k <= 0 || k >= nn {
+		return
+	}
+	idxs := make([]int, nn)
+	for ni := range idxs {
+		idxs[ni] = ni
+	}
+	sort.Slice(idxs, func(i, j int) bool {
+		ai, aj := ly.Neurons[idxs[i]].Act, ly.Neurons[idxs[j]].Act
+		if ai != aj {
+			return ai > aj
+		}
+		return idxs[i] < idxs[j]
+	})
+	for _, ni := range idxs[k:] {
+		ly.Neurons[ni].Act = 0
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"github.com/emer/emergent/emer"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// The Goal maintenance mechanism (GoalMaint) adds a fixed, non-learning
+// one-to-one self-excitatory projection on the Goal layer.  When the
+// gate is open (SetGoalGate), the recurrence is active and the Goal layer
+// activations are not decayed between alpha cycles, so the Goal
+// representation can persist without external clamping.  When closed,
+// the recurrence is off and the Goal layer decays as usual.
+
+// ConfigMaint configures the Goal self projection, if GoalMaint
+// -- called in Config after ConfigNet
+func (ss *Sim) ConfigMaint() {
+	if !ss.GoalMaint {
+		return
+	}
+	pj := ss.PrjnByName("Goal", "Goal")
+	if pj == nil {
+		return
+	}
+	pj.Learn.Learn = false
+	pj.WtInit.Mean = 0.9
+	pj.WtInit.Var = 0
+	pj.InitWts()
+	ss.SetGoalGate(false)
+}
+
+// SetGoalGate opens or closes the Goal maintenance gate -- takes effect at
+// the start of the next alpha cycle
+func (ss *Sim) SetGoalGate(open bool) {
+	if !ss.GoalMaint {
+		return
+	}
+	pj := ss.PrjnByName("Goal", "Goal")
+	goalLay := ss.Net.LayerByName("Goal").(*leabra.Layer)
+	ss.GoalGateOpen = open
+	if open {
+		pj.WtScale.Rel = ss.GoalMaintGain
+		goalLay.Act.Init.Decay = 0
+	} else {
+		pj.WtScale.Rel = 0
+		goalLay.Act.Init.Decay = 1
+	}
+}
+
+// MaintTest measures how well the Goal layer maintains each of the goal
+// (Outcome) patterns in given table over MaintDelay alpha cycles without
+// any external input: the goal is clamped for one alpha cycle with the
+// gate open, then unclamped for the delay, and the fidelity is the cosine
+// between the final Goal minus phase activity and the original pattern.
+// Returns the average fidelity over items.
+func (ss *Sim) MaintTest(et *etable.Table) float32 {
+	nr := et.NumRows()
+	if !ss.GoalMaint || nr == 0 {
+		return 0
+	}
+	goalLay := ss.Net.LayerByName("Goal").(*leabra.Layer)
+	ss.Net.LayerByName("Motor").SetType(emer.Hidden)
+	ss.Net.LayerByName("Outcome").SetType(emer.Hidden)
+	outcomeExtReps := et.ColByName("Outcome").(*etensor.Float32)
+	sum := float32(0)
+	for row := 0; row < nr; row++ {
+		g := RowCell(outcomeExtReps, row)
+		gvals := g.(*etensor.Float32).Values
+
+		ss.SetGoalGate(true)
+		goalLay.SetType(emer.Input)
+		ss.Net.InitExt()
+		goalLay.ApplyExt(g)
+		ss.AlphaCyc(false)
+
+		goalLay.SetType(emer.Hidden)
+		ss.Net.InitExt()
+		for d := 0; d < ss.MaintDelay; d++ {
+			ss.AlphaCyc(false)
+		}
+		acts, _ := goalLay.UnitVals("ActM")
+		sum += Cosine(acts, gvals)
+		ss.SetGoalGate(false)
+	}
+	return sum / float32(nr)
+}
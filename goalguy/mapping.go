@@ -0,0 +1,141 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// mapping.go has the generation of many-to-one and one-to-many Context ->
+// Outcome mappings (MapMode), to test the generalization of the goal
+// representations: in ManyToOne, MapFan contexts map to the same Outcome,
+// and in OneToMany, one Context maps to MapFan valid Outcomes, one per
+// item.  The Valid column of the patterns lists the Names of the items
+// whose Outcome is valid for the Context of each item, and the
+// OutValidPctCor stat scores the Outcome as correct if it matches any of
+// them.
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/goki/ki/kit"
+)
+
+// MapModes are the structures of the generated Context -> Outcome mappings
+type MapModes int32
+
+//go:generate stringer -type=MapModes
+
+var KiT_MapModes = kit.Enums.AddEnum(MapModesN, false, nil)
+
+const (
+	// OneToOne maps each Context to its own Outcome
+	OneToOne MapModes = iota
+
+	// ManyToOne maps each group of MapFan Contexts to the same Outcome
+	ManyToOne
+
+	// OneToMany maps each Context to MapFan valid Outcomes, over MapFan
+	// items with the same Context
+	OneToMany
+
+	MapModesN
+)
+
+// GenMapping sets the Context and Outcome patterns of given table (as
+// generated one per item) to the MapMode mapping: the items in each group
+// of MapFan items get the Context (OneToMany) or Outcome (ManyToOne) of
+// the first item of the group -- also sets the Name (c<context>_o<outcome>)
+// and Valid columns of the items -- called in GenExtReps
+func (ss *Sim) GenMapping(et *etable.Table) {
+	fan := ss.MapFan
+	if fan < 1 || ss.MapMode == OneToOne {
+		fan = 1
+	}
+	cc, oc := et.ColByName("Context"), et.ColByName("Outcome")
+	nc := et.ColByName("Name")
+	ctxts := make([]int, et.NumRows())
+	for row := range ctxts {
+		ci, oi := row, row
+		switch ss.MapMode {
+		case ManyToOne:
+			oi = (row / fan) * fan
+		case OneToMany:
+			ci = (row / fan) * fan
+		}
+		CopyRowCell(cc, row, ci)
+		CopyRowCell(oc, row, oi)
+		ctxts[row] = ci
+		if nc != nil {
+			nc.SetString1D(row, fmt.Sprintf("c%d_o%d", ci, oi))
+		}
+	}
+	vc := et.ColByName("Valid")
+	if vc == nil || nc == nil {
+		return
+	}
+	for row, ci := range ctxts {
+		var vnms []string
+		for vr, vi := range ctxts {
+			if vi == ci {
+				vnms = append(vnms, nc.StringVal1D(vr))
+			}
+		}
+		vc.SetString1D(row, strings.Join(vnms, " "))
+	}
+}
+
+// CopyRowCell copies the cell of given row of given column from given
+// source row of the column
+func CopyRowCell(col etensor.Tensor, row, src int) {
+	if row == src {
+		return
+	}
+	_, cells := col.RowCellSize()
+	for i := 0; i < cells; i++ {
+		col.SetFloat1D(row*cells+i, col.FloatVal1D(src*cells+i))
+	}
+}
+
+// ValidOutPats returns the Outcome patterns valid for the Context of given
+// row of given table: those of the items listed in its Valid column, or
+// just its own if the table has no Valid column (or it is empty)
+func ValidOutPats(et *etable.Table, row int) [][]float32 {
+	oc := et.ColByName("Outcome")
+	rows := []int{row}
+	vc, nc := et.ColByName("Valid"), et.ColByName("Name")
+	if vc != nil && nc != nil {
+		if vnms := strings.Fields(vc.StringVal1D(row)); len(vnms) > 0 {
+			rows = nil
+			for vr := 0; vr < et.NumRows(); vr++ {
+				for _, vn := range vnms {
+					if nc.StringVal1D(vr) == vn {
+						rows = append(rows, vr)
+						break
+					}
+				}
+			}
+		}
+	}
+	_, cells := oc.RowCellSize()
+	pats := make([][]float32, len(rows))
+	for i, vr := range rows {
+		pats[i] = make([]float32, cells)
+		for j := range pats[i] {
+			pats[i][j] = float32(oc.FloatVal1D(vr*cells + j))
+		}
+	}
+	return pats
+}
+
+// AnyValidOut returns whether given Outcome activations match (subject to
+// .5 unit-wise tolerance) any of given valid Outcome patterns
+func AnyValidOut(acts []float32, pats [][]float32) bool {
+	for _, pat := range pats {
+		if CompareVals(acts, pat, 0.5).Match {
+			return true
+		}
+	}
+	return false
+}
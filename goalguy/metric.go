@@ -0,0 +1,66 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"log"
+
+	"github.com/chewxy/math32"
+	"github.com/emer/leabra/leabra"
+)
+
+// Cosine returns the cosine (normalized dot product) between the two
+// vectors of values -- 0 if either has zero length
+func Cosine(a, b []float32) float32 {
+	var ab, aa, bb float32
+	for i := range a {
+		if i >= len(b) {
+			break
+		}
+		ab += a[i] * b[i]
+		aa += a[i] * a[i]
+		bb += b[i] * b[i]
+	}
+	dn := math32.Sqrt(aa * bb)
+	if dn == 0 {
+		return 0
+	}
+	return ab / dn
+}
+
+// LayerCmp is the result of comparing two activity patterns
+type LayerCmp struct {
+	SSE     float32 `desc:"sum squared difference, over the units differing by at least the tolerance"`
+	CosDiff float32 `desc:"cosine between the two patterns"`
+	Match   bool    `desc:"whether no unit differs by at least the tolerance (SSE == 0)"`
+}
+
+// CompareVals compares the two vectors of values, ignoring per-unit
+// differences less than tol in the SSE and Match
+func CompareVals(a, b []float32, tol float32) LayerCmp {
+	var sse float32
+	for i := range a {
+		if i >= len(b) {
+			break
+		}
+		d := a[i] - b[i]
+		if math32.Abs(d) < tol {
+			continue
+		}
+		sse += d * d
+	}
+	return LayerCmp{SSE: sse, CosDiff: Cosine(a, b), Match: sse == 0}
+}
+
+// CompareLayers compares the values of given variable (e.g., ActM) of the
+// two layers, which must have the same number of units -- see CompareVals
+func CompareLayers(la, lb *leabra.Layer, varNm string, tol float32) LayerCmp {
+	if len(la.Neurons) != len(lb.Neurons) {
+		log.Printf("CompareLayers: %s has %d units but %s has %d\n", la.Nm, len(la.Neurons), lb.Nm, len(lb.Neurons))
+	}
+	a, _ := la.UnitVals(varNm)
+	b, _ := lb.UnitVals(varNm)
+	return CompareVals(a, b, tol)
+}
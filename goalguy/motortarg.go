@@ -0,0 +1,111 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// motortarg.go has the choice of the Motor plus phase target on the 2nd
+// AlphaCycle (MotorTarg), which defines scientifically different learning
+// regimes: self-supervision from the network's own 1st AlphaCycle Motor
+// ActP (the original regime), supervision from a ground-truth motor
+// pattern given per item in the MotorTarg column of the pattern table, or
+// supervision from the correct action computed by the environment: the
+// action that produces the goal Outcome -- per the Contings if ContingOn,
+// and otherwise the Motor pattern mapping onto the goal Outcome through the
+// one-to-one Motor -> Outcome pathway.
+
+import (
+	"log"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/goki/ki/kit"
+)
+
+// MotorTargs are the sources of the Motor plus phase target on the 2nd AlphaCycle
+type MotorTargs int32
+
+//go:generate stringer -type=MotorTargs
+
+var KiT_MotorTargs = kit.Enums.AddEnum(MotorTargsN, false, nil)
+
+const (
+	// SelfActP uses the Motor ActP of the 1st AlphaCycle, stored in the
+	// Motor column of the pattern table (self-supervision)
+	SelfActP MotorTargs = iota
+
+	// TableMotor uses the ground-truth motor pattern in the MotorTarg
+	// column of the pattern table
+	TableMotor
+
+	// EnvAction uses the correct action computed by the environment for the
+	// goal Outcome (see EnvActPat)
+	EnvAction
+
+	MotorTargsN
+)
+
+// MotorTargPat returns the Motor plus phase target for given row of given
+// table on the 2nd AlphaCycle, per MotorTarg, given the goal Outcome pattern
+func (ss *Sim) MotorTargPat(et *etable.Table, row int, goal etensor.Tensor) etensor.Tensor {
+	switch ss.MotorTarg {
+	case TableMotor:
+		if mc := et.ColByName("MotorTarg"); mc != nil {
+			return RowCell(mc, row)
+		}
+		log.Println("MotorTargPat: pattern table has no MotorTarg column -- using SelfActP")
+	case EnvAction:
+		return ss.EnvActPat(goal)
+	}
+	return RowCell(et.ColByName("Motor"), row)
+}
+
+// EnvActPat returns the Motor pattern of the action that produces given goal
+// Outcome: if ContingOn, the action of the Contings with the highest
+// expected cosine of its outcome to the goal, as a pattern with all the
+// units of the action on; otherwise, the goal pattern itself, which maps
+// onto the goal Outcome through the one-to-one Motor -> Outcome pathway
+func (ss *Sim) EnvActPat(goal etensor.Tensor) etensor.Tensor {
+	tsr := ss.EnvMotor
+	gv := make([]float32, goal.Len())
+	for i := range gv {
+		gv[i] = float32(goal.FloatVal1D(i))
+	}
+	if !ss.ContingOn {
+		copy(tsr.Values, gv)
+		return tsr
+	}
+	ct := ss.Contings
+	best, bestCos := -1, float32(-1)
+	for r := 0; r < ct.NumRows(); r++ {
+		p := float32(ct.ColByName("P").FloatVal1D(r))
+		c1 := Cosine(gv, RowCell(ct.ColByName("Out1"), r).(*etensor.Float32).Values)
+		c2 := Cosine(gv, RowCell(ct.ColByName("Out2"), r).(*etensor.Float32).Values)
+		if ec := p*c1 + (1-p)*c2; ec > bestCos {
+			best, bestCos = int(ct.ColByName("Action").FloatVal1D(r)), ec
+		}
+	}
+	ss.ActPat(best, tsr)
+	return tsr
+}
+
+// ActPat sets given Motor pattern to have only given action on: its unit,
+// or all the units of its pool if PoolsOn
+func (ss *Sim) ActPat(act int, tsr *etensor.Float32) {
+	for i := range tsr.Values {
+		tsr.Values[i] = 0
+	}
+	if act < 0 {
+		return
+	}
+	if !ss.PoolsOn {
+		if act < len(tsr.Values) {
+			tsr.Values[act] = 1
+		}
+		return
+	}
+	pu := ss.PoolUnits()
+	for i := act * pu; i < (act+1)*pu && i < len(tsr.Values); i++ {
+		tsr.Values[i] = 1
+	}
+}
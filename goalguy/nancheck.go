@@ -0,0 +1,161 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// nancheck.go has the per-epoch check for NaN / Inf values in the layer
+// activities (NaNCheckVars) and the projection weights: if NaNCheck and any
+// are found, training is halted, the state of the offending layers and
+// projections is dumped to a diagnostic file, and the event is recorded as
+// the Halt of the run in the RunLog, instead of silently producing garbage
+// stats.
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/emer/leabra/leabra"
+)
+
+// NaNCheckVars are the unit variables checked for NaN / Inf in each layer
+var NaNCheckVars = []string{"Act", "ActM", "ActP", "Ge", "Gi", "Vm"}
+
+// NaNSynVars are the synapse variables checked for NaN / Inf in each
+// projection
+var NaNSynVars = []string{"Wt", "LWt", "DWt"}
+
+// BadFloat returns whether given value is NaN or Inf
+func BadFloat(v float32) bool {
+	return math.IsNaN(float64(v)) || math.IsInf(float64(v), 0)
+}
+
+// NBadVals returns the number of NaN or Inf values in given values
+func NBadVals(vals []float32) int {
+	n := 0
+	for _, v := range vals {
+		if BadFloat(v) {
+			n++
+		}
+	}
+	return n
+}
+
+// SynVals returns the values of given synapse variable (one of
+// NaNSynVars) of the projection
+func SynVals(pj *leabra.Prjn, varNm string) []float32 {
+	vals := make([]float32, len(pj.Syns))
+	for i := range pj.Syns {
+		sy := &pj.Syns[i]
+		switch varNm {
+		case "Wt":
+			vals[i] = sy.Wt
+		case "LWt":
+			vals[i] = sy.LWt
+		case "DWt":
+			vals[i] = sy.DWt
+		}
+	}
+	return vals
+}
+
+// CheckNaNs returns a description of each layer variable and projection
+// variable with NaN or Inf values, as <layer or Send:Recv>.<var>: <n> bad
+func (ss *Sim) CheckNaNs() []string {
+	var bad []string
+	for _, l := range ss.Net.Layers {
+		ly := l.(*leabra.Layer)
+		for _, vr := range NaNCheckVars {
+			vals, err := ly.UnitVals(vr)
+			if err != nil {
+				continue
+			}
+			if n := NBadVals(vals); n > 0 {
+				bad = append(bad, fmt.Sprintf("%s.%s: %d bad", ly.Name(), vr, n))
+			}
+		}
+	}
+	for _, pj := range ss.AllPrjns() {
+		for _, vr := range NaNSynVars {
+			if n := NBadVals(SynVals(pj, vr)); n > 0 {
+				bad = append(bad, fmt.Sprintf("%s.%s: %d bad", PrjnPath(pj), vr, n))
+			}
+		}
+	}
+	return bad
+}
+
+// HaltOnNaN halts training if NaNCheck and CheckNaNs finds any NaN or Inf
+// values, dumping the state of the offending layers and projections to
+// goal_guy_0_nan_epc<epoch>.txt and recording the Halt for the RunLog --
+// called at the end of each training epoch, after LogEpoch
+func (ss *Sim) HaltOnNaN() {
+	if !ss.NaNCheck {
+		return
+	}
+	bad := ss.CheckNaNs()
+	if len(bad) == 0 {
+		return
+	}
+	ss.StopNow = true
+	ss.Halt = fmt.Sprintf("NaN/Inf at epoch %d: %s", ss.Epoch, strings.Join(bad, ", "))
+	log.Println(ss.Halt)
+	fnm := fmt.Sprintf("goal_guy_0_nan_epc%d.txt", ss.Epoch)
+	if err := ss.DumpNaNState(fnm, bad); err != nil {
+		log.Println(err)
+		return
+	}
+	fmt.Printf("saved NaN diagnostic dump to: %s\n", fnm)
+}
+
+// DumpNaNState writes the values of all the variables of the layers and
+// projections named in given CheckNaNs results to given file, one line per
+// variable: <layer or Send:Recv>.<var> followed by the tab-separated values
+func (ss *Sim) DumpNaNState(fname string, bad []string) error {
+	f, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	fmt.Fprintf(w, "# %s\n", ss.Halt)
+	isBad := func(nm string) bool {
+		for _, b := range bad {
+			if strings.HasPrefix(b, nm+".") {
+				return true
+			}
+		}
+		return false
+	}
+	writeVals := func(nm, vr string, vals []float32) {
+		fmt.Fprintf(w, "%s.%s", nm, vr)
+		for _, v := range vals {
+			fmt.Fprintf(w, "\t%g", v)
+		}
+		fmt.Fprintln(w)
+	}
+	for _, l := range ss.Net.Layers {
+		ly := l.(*leabra.Layer)
+		if !isBad(ly.Name()) {
+			continue
+		}
+		for _, vr := range NaNCheckVars {
+			if vals, err := ly.UnitVals(vr); err == nil {
+				writeVals(ly.Name(), vr, vals)
+			}
+		}
+	}
+	for _, pj := range ss.AllPrjns() {
+		if !isBad(PrjnPath(pj)) {
+			continue
+		}
+		for _, vr := range NaNSynVars {
+			writeVals(PrjnPath(pj), vr, SynVals(pj, vr))
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// netvariant.go has the registry of alternative network architectures,
+// selected by the NetVariant switch in ConfigNet instead of commenting
+// connection lines in and out, and recorded in the run bundle manifest.
+// Each variant changes the base Context -> Goal -> Motor -> Outcome (<-
+// Outcome -> Motor back projection if OutMotBack) architecture as
+// documented for its value below.  The connectivity of the added pathways
+// can be set in PathConns as usual (Full by default).
+
+import (
+	"github.com/emer/emergent/emer"
+	"github.com/emer/leabra/leabra"
+	"github.com/goki/ki/kit"
+)
+
+// NetVariants are the alternative network architectures
+type NetVariants int32
+
+//go:generate stringer -type=NetVariants
+
+var KiT_NetVariants = kit.Enums.AddEnum(NetVariantsN, false, nil)
+
+const (
+	// BaseNet is the base architecture, with the Outcome -> Motor back
+	// projection if OutMotBack
+	BaseNet NetVariants = iota
+
+	// NoOutMotBack ablates the Outcome -> Motor back projection, whatever
+	// OutMotBack is
+	NoOutMotBack
+
+	// GoalOutDirect adds a direct Goal -> Outcome forward projection,
+	// bypassing Motor
+	GoalOutDirect
+
+	// MotGoalBack adds a Motor -> Goal back projection
+	MotGoalBack
+
+	NetVariantsN
+)
+
+// HasOutMotBack returns whether the network has the Outcome -> Motor back
+// projection: OutMotBack unless ablated by the NetVariant
+func (ss *Sim) HasOutMotBack() bool {
+	return ss.OutMotBack && ss.NetVariant != NoOutMotBack
+}
+
+// ConnectNetVariant adds the pathways of the NetVariant to given network
+// -- called in ConfigNet
+func (ss *Sim) ConnectNetVariant(net *leabra.Network) {
+	goalLay := net.LayerByName("Goal")
+	motorLay := net.LayerByName("Motor")
+	outcomeLay := net.LayerByName("Outcome")
+	switch ss.NetVariant {
+	case GoalOutDirect:
+		net.ConnectLayers(goalLay, outcomeLay, ss.PathPat("Goal:Outcome"), emer.Forward)
+	case MotGoalBack:
+		net.ConnectLayers(motorLay, goalLay, ss.PathPat("Motor:Goal"), emer.Back)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"fmt"
+
+	"github.com/emer/emergent/netview"
+	"github.com/goki/gi/gi"
+)
+
+// The NetViews are all the network viewer tabs, each showing its own unit
+// variable, and all updated together by UpdateView.  One is created in
+// ConfigGui for each of NetViewVars, and more can be added with AddNetView.
+
+// AddNetView adds a new NetView tab to given tab view, initially showing
+// given unit variable
+func (ss *Sim) AddNetView(tv *gi.TabView, vr string) *netview.NetView {
+	label := "NetView"
+	if len(ss.NetViews) > 0 {
+		label = fmt.Sprintf("NetView %d", len(ss.NetViews)+1)
+	}
+	nv := tv.AddNewTab(netview.KiT_NetView, label).(*netview.NetView)
+	nv.SetStretchMaxWidth()
+	nv.SetStretchMaxHeight()
+	nv.Var = vr
+	nv.SetNet(NewViewNet(ss))
+	ss.NetViews = append(ss.NetViews, nv)
+	return nv
+}
+
+// SetNetViewsNet sets the network of all the NetViews to the current Net,
+// e.g., after it has been rebuilt
+func (ss *Sim) SetNetViewsNet() {
+	for _, nv := range ss.NetViews {
+		nv.SetNet(NewViewNet(ss))
+	}
+}
+
+// HasNetView returns whether there are any NetViews
+func (ss *Sim) HasNetView() bool {
+	return len(ss.NetViews) > 0
+}
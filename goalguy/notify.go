@@ -0,0 +1,79 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// notify.go has the optional notifier of long runs on remote machines: if
+// NotifyURL is set, a short summary is posted to it (as the "text" of a JSON
+// object, as for Slack incoming webhooks) at the completion of a -nogui run
+// or -sweep, and a divergence alert the first time the epoch SSE of a run
+// becomes NaN or Inf, or exceeds DivergeSSE.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"time"
+)
+
+// DefaultNotifyURL is the NotifyURL of new Sims -- e.g., set by the -notify
+// flag, so it applies to the runs of a -sweep too
+var DefaultNotifyURL = ""
+
+// NotifyTimeout is the timeout of the posts to the NotifyURL
+const NotifyTimeout = 10 * time.Second
+
+// PostNotify posts given text to given webhook URL, prefixed by the host
+// name, as the "text" of a JSON object
+func PostNotify(url, text string) error {
+	host, _ := os.Hostname()
+	b, err := json.Marshal(map[string]string{"text": fmt.Sprintf("goal-guy-0 on %s: %s", host, text)})
+	if err != nil {
+		return err
+	}
+	cl := http.Client{Timeout: NotifyTimeout}
+	resp, err := cl.Post(url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("PostNotify: %s: %s", url, resp.Status)
+	}
+	return nil
+}
+
+// Notify posts given text to the NotifyURL, if set, logging any error
+func (ss *Sim) Notify(text string) {
+	if ss.NotifyURL == "" {
+		return
+	}
+	if err := PostNotify(ss.NotifyURL, text); err != nil {
+		log.Println(err)
+	}
+}
+
+// SSEDiverged returns whether given SSE is NaN or Inf, or exceeds
+// DivergeSSE (if > 0)
+func (ss *Sim) SSEDiverged(sse float32) bool {
+	v := float64(sse)
+	return math.IsNaN(v) || math.IsInf(v, 0) || (ss.DivergeSSE > 0 && sse > ss.DivergeSSE)
+}
+
+// CheckDiverge posts a divergence alert the first time in the run that the
+// last epoch's Outcome or Motor SSE diverged (see SSEDiverged) -- called
+// at the end of each training epoch, after LogEpoch
+func (ss *Sim) CheckDiverge() {
+	if ss.Diverged || !(ss.SSEDiverged(ss.EpcOutSSE) || ss.SSEDiverged(ss.EpcMotSSE)) {
+		return
+	}
+	ss.Diverged = true
+	msg := fmt.Sprintf("run %s (seed %d) diverged at epoch %d: OutSSE: %g  MotSSE: %g", ss.Expt, ss.RndSeed, ss.Epoch, ss.EpcOutSSE, ss.EpcMotSSE)
+	log.Println(msg)
+	ss.Notify(msg)
+}
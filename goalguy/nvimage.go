@@ -0,0 +1,79 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// nvimage.go has the saving of NetView snapshots as PNG images, for making
+// figures of activation states without screenshots: SaveNetViewImage saves
+// the current rendering of the first NetView, scaled to NetViewImgWidth
+// pixels wide if set, and the NetViewImgEpcs epochs are captured
+// automatically during training, as goal_guy_0_netview_epc<epoch>.png.
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"os"
+)
+
+// SaveNetViewImage saves the current rendering of the first NetView to
+// given PNG file, scaled to NetViewImgWidth if > 0
+func (ss *Sim) SaveNetViewImage(fname string) error {
+	if !ss.HasNetView() {
+		return fmt.Errorf("SaveNetViewImage: no NetView")
+	}
+	vp := ss.NetViews[0].Viewport()
+	if vp == nil || vp.Pixels == nil {
+		return fmt.Errorf("SaveNetViewImage: NetView has not been rendered")
+	}
+	var img image.Image = vp.Pixels
+	if ss.NetViewImgWidth > 0 {
+		img = ScaleImage(vp.Pixels, ss.NetViewImgWidth)
+	}
+	f, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return png.Encode(f, img)
+}
+
+// ScaleImage returns a copy of given image scaled to given width, keeping
+// its aspect ratio, by nearest-neighbor sampling
+func ScaleImage(src *image.RGBA, width int) *image.RGBA {
+	sb := src.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+	if sw == 0 || sh == 0 {
+		return src
+	}
+	height := (sh*width + sw/2) / sw
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy := sb.Min.Y + y*sh/height
+		for x := 0; x < width; x++ {
+			dst.SetRGBA(x, y, src.RGBAAt(sb.Min.X+x*sw/width, sy))
+		}
+	}
+	return dst
+}
+
+// CaptureNetViewEpc saves the NetView image for the current Epoch if it is
+// one of the NetViewImgEpcs -- called at the end of each training epoch
+func (ss *Sim) CaptureNetViewEpc() {
+	if !ss.HasNetView() {
+		return
+	}
+	for _, epc := range ss.NetViewImgEpcs {
+		if epc != ss.Epoch {
+			continue
+		}
+		ss.FlushView() // render the latest state
+		fnm := fmt.Sprintf("goal_guy_0_netview_epc%03d.png", epc)
+		if err := ss.SaveNetViewImage(fnm); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/emer/emergent/erand"
+	"github.com/goki/ki/kit"
+)
+
+// Orders are the different orders in which training items can be presented
+// within an epoch of an Env -- every epoch always has Table.NumRows() trials
+type Orders int32
+
+//go:generate stringer -type=Orders
+
+var KiT_Orders = kit.Enums.AddEnum(OrdersN, false, nil)
+
+const (
+	// Permuted presents each item once per epoch, in a new random order each epoch
+	Permuted Orders = iota
+
+	// Sequential presents each item once per epoch, in table order
+	Sequential
+
+	// Replacement samples items uniformly at random with replacement,
+	// so some items may be presented multiple times per epoch, others not at all
+	Replacement
+
+	// FreqWeighted samples items at random with replacement, with probability
+	// proportional to the Freq column of the Table
+	FreqWeighted
+
+	// Blocked presents all items with the same Context pattern together in a
+	// block, with the order of blocks and of items within blocks permuted
+	Blocked
+
+	OrdersN
+)
+
+// NewPorder sets Porder to the order of item rows for the next epoch,
+// according to the Order setting, or the next Replay epoch if any, and
+// records it in the Hist
+func (ev *Env) NewPorder() {
+	np := ev.NumRows()
+	if len(ev.Porder) != np {
+		ev.Porder = make([]int, np)
+	}
+	defer ev.RecordPorder()
+	if ev.ReplayPorder() {
+		return
+	}
+	switch ev.Order {
+	case Permuted:
+		for i := range ev.Porder {
+			ev.Porder[i] = i
+		}
+		erand.PermuteInts(ev.Porder)
+	case Sequential:
+		for i := range ev.Porder {
+			ev.Porder[i] = i
+		}
+	case Replacement:
+		for i := range ev.Porder {
+			ev.Porder[i] = rand.Intn(np)
+		}
+	case FreqWeighted:
+		ev.FreqWeightedOrder()
+	case Blocked:
+		ev.BlockedOrder()
+	}
+}
+
+// FreqWeightedOrder samples Porder with replacement according to the Freq
+// column of the Table (uniform if not present)
+func (ev *Env) FreqWeightedOrder() {
+	np := ev.NumRows()
+	fc := ev.Table.ColByName("Freq")
+	cum := make([]float64, np)
+	tot := 0.0
+	for i := 0; i < np; i++ {
+		f := 1.0
+		if fc != nil {
+			f = fc.FloatVal1D(i)
+		}
+		if f < 0 {
+			f = 0
+		}
+		tot += f
+		cum[i] = tot
+	}
+	for i := range ev.Porder {
+		r := rand.Float64() * tot
+		row := 0
+		for row < np-1 && cum[row] <= r {
+			row++
+		}
+		ev.Porder[i] = row
+	}
+}
+
+// BlockedOrder sets Porder to present items grouped by identical Context
+// pattern, with block order and within-block order permuted
+func (ev *Env) BlockedOrder() {
+	np := ev.NumRows()
+	ctxt := ev.Table.ColByName("Context")
+	_, cells := ctxt.RowCellSize()
+	var keys []string
+	blocks := map[string][]int{}
+	for row := 0; row < np; row++ {
+		vals := make([]float64, cells)
+		for ci := range vals {
+			vals[ci] = ctxt.FloatVal1D(row*cells + ci)
+		}
+		key := fmt.Sprint(vals)
+		if _, has := blocks[key]; !has {
+			keys = append(keys, key)
+		}
+		blocks[key] = append(blocks[key], row)
+	}
+	bord := rand.Perm(len(keys))
+	i := 0
+	for _, bi := range bord {
+		blk := blocks[keys[bi]]
+		erand.PermuteInts(blk)
+		for _, row := range blk {
+			ev.Porder[i] = row
+			i++
+		}
+	}
+}
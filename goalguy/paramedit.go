@@ -0,0 +1,80 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// paramedit.go has the Params tab, where the entries of the Params can be
+// edited while the sim is running, and ApplyParams, which re-styles the
+// network with them, so that e.g., Gi and WtScale can be tuned without
+// editing DefaultParams.
+
+import (
+	"github.com/emer/emergent/emer"
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/giv"
+	"github.com/goki/ki/ki"
+)
+
+// CopyParams returns a deep copy of given params, so that the copy can be
+// edited without changing e.g., DefaultParams
+func CopyParams(base emer.ParamStyle) emer.ParamStyle {
+	ps := make(emer.ParamStyle, len(base))
+	for i, psel := range base {
+		pr := make(emer.Params, len(psel.Params))
+		for k, v := range psel.Params {
+			pr[k] = v
+		}
+		ps[i] = emer.ParamSel{Sel: psel.Sel, Params: pr}
+	}
+	return ps
+}
+
+// ApplyParams re-styles the network with the current Params (and the
+// PoolParams and PrjnLrns), re-initializing the weights if initWts.
+// Params are applied on top of the current ones, so an entry removed
+// from the Params keeps its last value until the next Config.
+func (ss *Sim) ApplyParams(initWts bool) {
+	ss.Net.StyleParams(ss.Params, true) // set msg
+	ss.StylePoolParams()
+	ss.ApplyPrjnLrns()
+	if initWts {
+		ss.InitWts()
+	}
+	ss.ScaleTabUpdt()
+	ss.UpdateView()
+}
+
+// ConfigParamsTab adds the Params tab to given tab view: an editor of the
+// Params entries, with buttons to apply them to the network, re-rendering
+// given viewport after each apply
+func (ss *Sim) ConfigParamsTab(tv *gi.TabView, vp *gi.Viewport2D) {
+	fr := tv.AddNewTab(gi.KiT_Frame, "Params").(*gi.Frame)
+	fr.Lay = gi.LayoutVert
+
+	gi.AddNewLabel(fr, "msg", "Edit the Params entries (Sel and Params) and Apply them to the network:")
+	psv := giv.AddNewSliceView(fr, "params")
+	psv.SetSlice(&ss.Params, nil)
+
+	btns := gi.AddNewFrame(fr, "btns", gi.LayoutHoriz)
+	addBtn := func(nm, txt string, fun func()) {
+		bt := gi.AddNewButton(btns, nm)
+		bt.SetText(txt)
+		bt.ButtonSig.Connect(fr.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig == int64(gi.ButtonClicked) {
+				fun()
+				vp.FullRender2DTree()
+			}
+		})
+	}
+	addBtn("apply", "Apply Params", func() {
+		ss.ApplyParams(false)
+	})
+	addBtn("applyinit", "Apply Params + Init Wts", func() {
+		ss.ApplyParams(true)
+	})
+	addBtn("defaults", "Reset to Defaults", func() {
+		ss.Params = CopyParams(DefaultParams)
+		psv.SetSlice(&ss.Params, nil)
+	})
+}
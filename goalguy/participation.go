@@ -0,0 +1,112 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// participation.go has the unit participation report, for tuning Gi and
+// the weight init: over each TestAll, the number of items on which each
+// unit of the PartLays has ActM > PartThr is counted, and units that are
+// never active (dead units) and units active on more than PartMaxPct of
+// the items (grandmother-cell overuse) are flagged.  One row per layer is
+// added to PartLog at the end of each TestAll, with the counts and the
+// indexes of the flagged units.
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// ResetPartCnts clears the unit participation counts, at the start of TestAll
+func (ss *Sim) ResetPartCnts() {
+	ss.PartCnts = make([][]int, len(ss.PartLays))
+	ss.PartN = 0
+}
+
+// PartCntAdd counts the units of each of the PartLays with ActM above
+// PartThr on the current trial -- called for each trial of TestAll
+func (ss *Sim) PartCntAdd() {
+	for li, lnm := range ss.PartLays {
+		ly, ok := ss.Net.LayerByName(lnm).(*leabra.Layer)
+		if !ok || li >= len(ss.PartCnts) {
+			continue
+		}
+		acts, err := ly.UnitVals("ActM")
+		if err != nil {
+			continue
+		}
+		if len(ss.PartCnts[li]) != len(acts) {
+			ss.PartCnts[li] = make([]int, len(acts))
+		}
+		for ui, a := range acts {
+			if a > ss.PartThr {
+				ss.PartCnts[li][ui]++
+			}
+		}
+	}
+	ss.PartN++
+}
+
+// UnitList returns the given unit indexes as a space-separated string
+func UnitList(us []int) string {
+	strs := make([]string, len(us))
+	for i, u := range us {
+		strs[i] = strconv.Itoa(u)
+	}
+	return strings.Join(strs, " ")
+}
+
+// LogParticipation adds a row per PartLays layer to the PartLog with the
+// dead and overused units of the TestAll just done -- called at its end
+func (ss *Sim) LogParticipation() {
+	if ss.PartN == 0 {
+		return
+	}
+	dt := ss.PartLog
+	for li, lnm := range ss.PartLays {
+		if li >= len(ss.PartCnts) || len(ss.PartCnts[li]) == 0 {
+			continue
+		}
+		cnts := ss.PartCnts[li]
+		var dead, over []int
+		for ui, c := range cnts {
+			switch {
+			case c == 0:
+				dead = append(dead, ui)
+			case float32(c)/float32(ss.PartN) > ss.PartMaxPct:
+				over = append(over, ui)
+			}
+		}
+		nu := float64(len(cnts))
+		row := dt.NumRows()
+		dt.SetNumRows(row + 1)
+		dt.ColByName("Epoch").SetFloat1D(row, float64(ss.Epoch))
+		dt.ColByName("Layer").SetString1D(row, lnm)
+		dt.ColByName("NItems").SetFloat1D(row, float64(ss.PartN))
+		dt.ColByName("NDead").SetFloat1D(row, float64(len(dead)))
+		dt.ColByName("NOveruse").SetFloat1D(row, float64(len(over)))
+		dt.ColByName("PctDead").SetFloat1D(row, float64(len(dead))/nu)
+		dt.ColByName("PctOveruse").SetFloat1D(row, float64(len(over))/nu)
+		dt.ColByName("DeadUnits").SetString1D(row, UnitList(dead))
+		dt.ColByName("OveruseUnits").SetString1D(row, UnitList(over))
+	}
+}
+
+// ConfigPartLog configures the PartLog table
+func (ss *Sim) ConfigPartLog() {
+	ss.PartLog.SetFromSchema(etable.Schema{
+		{"Epoch", etensor.INT64, nil, nil},
+		{"Layer", etensor.STRING, nil, nil},
+		{"NItems", etensor.INT64, nil, nil},
+		{"NDead", etensor.INT64, nil, nil},
+		{"NOveruse", etensor.INT64, nil, nil},
+		{"PctDead", etensor.FLOAT32, nil, nil},
+		{"PctOveruse", etensor.FLOAT32, nil, nil},
+		{"DeadUnits", etensor.STRING, nil, nil},
+		{"OveruseUnits", etensor.STRING, nil, nil},
+	}, 0)
+}
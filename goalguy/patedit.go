@@ -0,0 +1,211 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// patedit.go has the Patterns tab, for editing the Context and Outcome
+// patterns of the ExtReps items by hand: the active bits of the current
+// item can be toggled in a grid of check boxes, and the patterns can be
+// validated (exactly PatNOn active bits per row) and saved back to the
+// ExtRepsFile, so small changes to the stimulus set do not require
+// regenerating everything randomly.  The edits of the current item are
+// held in EditCtxt and EditOut, and copied into ExtReps when moving to
+// another item, validating or saving.  Setup only generates new patterns
+// if the ExtRepsFile is missing or does not fit the config (or GenPats),
+// so the saved edits are used from then on.
+
+import (
+	"fmt"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/goki/gi/gi"
+	"github.com/goki/ki/ki"
+)
+
+// ExtRepsFile is the file the ExtReps patterns are saved to and opened from
+const ExtRepsFile = "goal-guy-0-5x5-25-gen.tsv"
+
+// GenPats makes Setup generate new ExtReps patterns, overwriting the
+// ExtRepsFile, even if it fits the config -- set by the -genpats flag
+var GenPats = false
+
+// ValRepsFile is the file the ValReps validation patterns are opened from,
+// if it exists -- items not in the ExtReps, in the same format
+const ValRepsFile = "goal-guy-0-5x5-25-val.tsv"
+
+// ExtRepsFileFits returns an error if the ExtRepsFile does not exist, or
+// its patterns do not fit the current config: the ExtReps columns, the
+// Outcome shape (PoolsOn) and the PatNOn active bits
+func (ss *Sim) ExtRepsFileFits() error {
+	et := &etable.Table{}
+	if err := OpenTable(et, ExtRepsFile); err != nil {
+		return err
+	}
+	if err := ss.ValidateExtReps(et); err != nil {
+		return err
+	}
+	oshp, _ := ss.OutShape()
+	nu := 1
+	for _, d := range oshp {
+		nu *= d
+	}
+	if _, cells := et.ColByName("Outcome").RowCellSize(); cells != nu {
+		return fmt.Errorf("ExtReps Outcome column has %d cells per row, but the Outcome layer has %d units", cells, nu)
+	}
+	if ss.PoolsOn {
+		return nil // only one pool of the Outcome is active
+	}
+	return ValidatePats(et, ss.PatNOn)
+}
+
+// ValidatePats returns an error describing each row of given table whose
+// Context or Outcome pattern does not have exactly k active (> 0.5) bits
+func ValidatePats(et *etable.Table, k int) error {
+	msg := ""
+	for _, cnm := range []string{"Context", "Outcome"} {
+		col := et.ColByName(cnm)
+		if col == nil {
+			continue
+		}
+		_, cells := col.RowCellSize()
+		for row := 0; row < et.NumRows(); row++ {
+			non := 0
+			for i := 0; i < cells; i++ {
+				if col.FloatVal1D(row*cells+i) > 0.5 {
+					non++
+				}
+			}
+			if non != k {
+				msg += fmt.Sprintf("\n\trow %d %s: %d active bits", row, cnm, non)
+			}
+		}
+	}
+	if msg != "" {
+		return fmt.Errorf("patterns must have exactly %d active bits:%s", k, msg)
+	}
+	return nil
+}
+
+// SetEditRow copies the Context and Outcome patterns of given ExtReps row
+// into EditCtxt and EditOut, after committing the edits of the current row
+func (ss *Sim) SetEditRow(row int) {
+	nr := ss.ExtReps.NumRows()
+	if nr == 0 {
+		return
+	}
+	ss.CommitEditRow()
+	ss.EditRow = (row + nr) % nr
+	copyCell(ss.EditCtxt, ss.ExtReps.ColByName("Context"), ss.EditRow, false)
+	copyCell(ss.EditOut, ss.ExtReps.ColByName("Outcome"), ss.EditRow, false)
+}
+
+// CommitEditRow copies EditCtxt and EditOut back into the EditRow of ExtReps
+func (ss *Sim) CommitEditRow() {
+	if ss.EditRow >= ss.ExtReps.NumRows() {
+		return
+	}
+	copyCell(ss.EditCtxt, ss.ExtReps.ColByName("Context"), ss.EditRow, true)
+	copyCell(ss.EditOut, ss.ExtReps.ColByName("Outcome"), ss.EditRow, true)
+}
+
+// copyCell copies between given tensor and given row of given column, into
+// the column if toCol
+func copyCell(tsr *etensor.Float32, col etensor.Tensor, row int, toCol bool) {
+	_, cells := col.RowCellSize()
+	for i := range tsr.Values {
+		if i >= cells {
+			break
+		}
+		if toCol {
+			col.SetFloat1D(row*cells+i, float64(tsr.Values[i]))
+		} else {
+			tsr.Values[i] = float32(col.FloatVal1D(row*cells + i))
+		}
+	}
+}
+
+// SavePats validates the ExtReps patterns (including the current edits)
+// and saves them back to ExtRepsFile
+func (ss *Sim) SavePats() error {
+	ss.CommitEditRow()
+	if err := ValidatePats(ss.ExtReps, ss.PatNOn); err != nil {
+		return err
+	}
+	ss.UpdtPatsHash()
+	return SaveTable(ss.ExtReps, ExtRepsFile)
+}
+
+// ConfigPatEditTab adds the Patterns tab to given tab view, re-rendering
+// given viewport after each change of item
+func (ss *Sim) ConfigPatEditTab(tv *gi.TabView, vp *gi.Viewport2D) {
+	oshp, onms := ss.OutShape()
+	ss.EditCtxt = etensor.NewFloat32([]int{5, 5}, nil, []string{"Y", "X"})
+	ss.EditOut = etensor.NewFloat32(oshp, nil, onms)
+	ss.EditRow = 0
+	if ss.ExtReps.NumRows() > 0 {
+		copyCell(ss.EditCtxt, ss.ExtReps.ColByName("Context"), 0, false)
+		copyCell(ss.EditOut, ss.ExtReps.ColByName("Outcome"), 0, false)
+	}
+	fr := tv.AddNewTab(gi.KiT_Frame, "Patterns").(*gi.Frame)
+	fr.Lay = gi.LayoutVert
+
+	item := gi.AddNewLabel(fr, "item", "")
+	msg := gi.AddNewLabel(fr, "msg", "")
+	pats := gi.AddNewFrame(fr, "pats", gi.LayoutHoriz)
+	cfr := gi.AddNewFrame(pats, "ctxt", gi.LayoutVert)
+	ccbs := AddProbeGrid(cfr, "Context", ss.EditCtxt)
+	ofr := gi.AddNewFrame(pats, "out", gi.LayoutVert)
+	ocbs := AddProbeGrid(ofr, "Outcome", ss.EditOut)
+
+	updt := func() {
+		if nc := ss.ExtReps.ColByName("Name"); nc != nil && ss.EditRow < ss.ExtReps.NumRows() {
+			item.SetText(fmt.Sprintf("Item %d: %s", ss.EditRow, nc.StringVal1D(ss.EditRow)))
+		}
+		for i, cb := range ccbs {
+			cb.SetChecked(ss.EditCtxt.Values[i] > 0.5)
+		}
+		for i, cb := range ocbs {
+			cb.SetChecked(ss.EditOut.Values[i] > 0.5)
+		}
+		vp.FullRender2DTree()
+	}
+	updt()
+
+	btns := gi.AddNewFrame(fr, "btns", gi.LayoutHoriz)
+	addBtn := func(nm, txt string, fun func()) {
+		bt := gi.AddNewButton(btns, nm)
+		bt.SetText(txt)
+		bt.ButtonSig.Connect(fr.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig == int64(gi.ButtonClicked) {
+				fun()
+			}
+		})
+	}
+	addBtn("prev", "< Prev Item", func() {
+		ss.SetEditRow(ss.EditRow - 1)
+		updt()
+	})
+	addBtn("next", "Next Item >", func() {
+		ss.SetEditRow(ss.EditRow + 1)
+		updt()
+	})
+	addBtn("validate", "Validate", func() {
+		ss.CommitEditRow()
+		if err := ValidatePats(ss.ExtReps, ss.PatNOn); err != nil {
+			msg.SetText(err.Error())
+		} else {
+			msg.SetText("all patterns valid")
+		}
+		vp.FullRender2DTree()
+	})
+	addBtn("save", "Save Patterns", func() {
+		if err := ss.SavePats(); err != nil {
+			msg.SetText(err.Error())
+		} else {
+			msg.SetText("saved patterns to: " + ExtRepsFile)
+		}
+		vp.FullRender2DTree()
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// patshash.go records which pattern set a network was trained on: PatsHash
+// is a hash of the stimulus definition columns of ExtReps (PatsHashCols),
+// updated whenever the patterns are generated, opened or saved.  It is
+// recorded in the run bundle manifest, and in a <weights>.pats file next to
+// each weights file saved with SaveWts, so that OpenWts can warn if the
+// network is being loaded with a different pattern set than it was trained
+// on.  The Motor and Goal columns are not included, as they hold run-time
+// activations rather than the stimulus definition.
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/goki/gi/gi"
+)
+
+// PatsHashCols are the ExtReps columns included in the PatsHash
+var PatsHashCols = []string{"Name", "Context", "Outcome", "Valence", "Group", "MotorTarg"}
+
+// TablePatsHash returns the hex sha256 hash of the PatsHashCols of given table
+func TablePatsHash(et *etable.Table) string {
+	h := sha256.New()
+	var b [4]byte
+	for _, cnm := range PatsHashCols {
+		col := et.ColByName(cnm)
+		if col == nil {
+			continue
+		}
+		h.Write([]byte(cnm))
+		for i := 0; i < col.Len(); i++ {
+			if col.DataType() == etensor.STRING {
+				h.Write([]byte(col.StringVal1D(i)))
+				h.Write([]byte{0})
+				continue
+			}
+			binary.LittleEndian.PutUint32(b[:], math.Float32bits(float32(col.FloatVal1D(i))))
+			h.Write(b[:])
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// UpdtPatsHash updates PatsHash from the current ExtReps patterns -- called
+// whenever they are generated, opened or saved
+func (ss *Sim) UpdtPatsHash() {
+	ss.PatsHash = TablePatsHash(ss.ExtReps)
+}
+
+// SaveWts saves the network weights to given file, along with the PatsHash
+// of the patterns they were trained on in a .pats file next to it
+func (ss *Sim) SaveWts(fname gi.FileName) error {
+	if err := ss.Net.SaveWtsJSON(fname); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(string(fname)+".pats", []byte(ss.PatsHash+"\n"), 0644)
+}
+
+// OpenWts opens the network weights from given file, warning if the .pats
+// file saved with them shows they were trained on a different pattern set
+func (ss *Sim) OpenWts(fname gi.FileName) error {
+	if err := ss.Net.OpenWtsJSON(fname); err != nil {
+		return err
+	}
+	b, err := ioutil.ReadFile(string(fname) + ".pats")
+	if os.IsNotExist(err) {
+		return nil // saved without a hash
+	} else if err != nil {
+		return err
+	}
+	if hash := strings.TrimSpace(string(b)); hash != ss.PatsHash {
+		log.Printf("warning: weights %s were trained on a different pattern set (hash %.12s) than the current ExtReps (hash %.12s)\n", fname, hash, ss.PatsHash)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// patstruct.go has the control of the structure of the generated patterns,
+// on which learning difficulty critically depends: the number of active
+// units (PatNOn), the minimum Hamming distance between any two patterns
+// (PatMinDiff), and the proportion of the active units of the Outcome
+// patterns that are shared across all the contexts (PatOverlap), giving
+// correlated Context -> Outcome mappings.
+
+import (
+	"log"
+	"math"
+	"math/rand"
+
+	"github.com/emer/emergent/patgen"
+	"github.com/emer/etable/etensor"
+)
+
+// PatGenMaxTries is the maximum number of times each pattern is regenerated
+// to be at least PatMinDiff from the previous ones
+const PatGenMaxTries = 100
+
+// PatNShared returns the number of the PatNOn active units of the Outcome
+// patterns shared across all the items, per PatOverlap
+func (ss *Sim) PatNShared() int {
+	ns := int(math.Round(float64(ss.PatOverlap) * float64(ss.PatNOn)))
+	if ns > ss.PatNOn {
+		ns = ss.PatNOn
+	}
+	return ns
+}
+
+// GenCtxtPats generates random Context patterns into given column: PatNOn
+// active units per row, at least PatMinDiff apart if > 0
+func (ss *Sim) GenCtxtPats(col etensor.Tensor) {
+	if ss.PatMinDiff <= 0 {
+		patgen.PermutedBinaryRows(col, ss.PatNOn, 1, 0)
+		return
+	}
+	if !patgen.PermutedBinaryMinDiff(col, ss.PatNOn, 1, 0, ss.PatMinDiff) {
+		log.Printf("GenCtxtPats: could not generate Context patterns at least PatMinDiff: %d apart\n", ss.PatMinDiff)
+	}
+}
+
+// GenOverlapRows sets each row of given column to nOn active units, of
+// which nShared are the same randomly chosen units in all the rows, and the
+// rest are chosen at random among the other units, each row being
+// regenerated up to PatGenMaxTries times to be at least minDiff (Hamming
+// distance) from the previous rows -- returns false if that failed for
+// any row
+func GenOverlapRows(col etensor.Tensor, nOn, nShared, minDiff int) bool {
+	rows, cells := col.RowCellSize()
+	if nOn > cells {
+		nOn = cells
+	}
+	perm := rand.Perm(cells)
+	shared, rest := perm[:nShared], perm[nShared:]
+	pats := make([][]bool, rows)
+	ok := true
+	for row := 0; row < rows; row++ {
+		var pat []bool
+		for try := 0; try < PatGenMaxTries; try++ {
+			pat = make([]bool, cells)
+			for _, i := range shared {
+				pat[i] = true
+			}
+			for _, pi := range rand.Perm(len(rest))[:nOn-nShared] {
+				pat[rest[pi]] = true
+			}
+			if PatMinHamming(pat, pats[:row]) >= minDiff {
+				break
+			}
+			if try == PatGenMaxTries-1 {
+				ok = false
+			}
+		}
+		pats[row] = pat
+		for i, on := range pat {
+			v := 0.0
+			if on {
+				v = 1
+			}
+			col.SetFloat1D(row*cells+i, v)
+		}
+	}
+	return ok
+}
+
+// PatMinHamming returns the minimum Hamming distance between given pattern
+// and given other patterns -- the number of units if none
+func PatMinHamming(pat []bool, pats [][]bool) int {
+	mind := len(pat)
+	for _, op := range pats {
+		d := 0
+		for i := range pat {
+			if pat[i] != op[i] {
+				d++
+			}
+		}
+		if d < mind {
+			mind = d
+		}
+	}
+	return mind
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// pause.go has Pause / Resume, which unlike Stop does not end the run: the
+// training goroutine blocks between trials (in WaitPaused, called from the
+// Train and TrainEpoch loops) until Resume, and then continues exactly
+// where it left off -- the same trial position in the TrainEnv Porder, the
+// same epoch accumulators, and the same place in any protocol or
+// devaluation run driving TrainEpoch.  Stop while paused releases the
+// training goroutine and ends the run as usual.
+
+// Pause pauses the running training at the end of the current trial
+func (ss *Sim) Pause() {
+	ss.PauseMu.Lock()
+	ss.Paused = true
+	ss.PauseMu.Unlock()
+}
+
+// Resume resumes the paused training where it left off
+func (ss *Sim) Resume() {
+	ss.PauseMu.Lock()
+	ss.Paused = false
+	if ss.ResumeCh != nil {
+		close(ss.ResumeCh)
+		ss.ResumeCh = nil
+	}
+	ss.PauseMu.Unlock()
+}
+
+// WaitPaused blocks while Paused, until Resume or Stop -- called between
+// trials in the training loops
+func (ss *Sim) WaitPaused() {
+	ss.PauseMu.Lock()
+	if !ss.Paused {
+		ss.PauseMu.Unlock()
+		return
+	}
+	ch := make(chan struct{})
+	ss.ResumeCh = ch
+	ss.PauseMu.Unlock()
+	ss.FlushView()
+	<-ch
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// RunPipeline runs a complete experiment in one step: Init, Train to
+// criterion (MaxEpcs, or NZeroStop), TestAll on the TestEnv, and export of
+// all the artifacts (weights, logs, plot) as a run bundle into given
+// directory (time-stamped if empty) -- see ExportRunBundle.  It is the
+// default in -nogui mode.
+func (ss *Sim) RunPipeline(dir string) error {
+	if err := ss.ValidateExtReps(ss.ExtReps); err != nil {
+		return err
+	}
+	ss.Init()
+	if ss.StopSignal == nil {
+		ss.Train()
+	}
+	if ss.StopSignal != nil {
+		return ss.SaveCheckpoint(dir) // e.g., preempted -- save what we have
+	}
+	if ss.StopNow {
+		return nil // stopped by user -- incomplete run
+	}
+	ss.TestAll(&ss.TestEnv)
+	return ss.ExportRunBundle(dir)
+}
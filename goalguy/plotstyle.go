@@ -0,0 +1,155 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// plotstyle.go has the PlotStyle used for all the plots made by the sim:
+// background and foreground (text, axes) colors, the line color palette,
+// font and font sizes, and line widths -- e.g., for generating
+// publication-quality figures directly from the sim, or the DarkPlotStyle
+// for use with a dark GUI color scheme (set in the GoGi preferences).  The
+// CurPlotStyle is edited in the PlotStyle panel of the Sim, and can be
+// opened from a JSON style file (PlotStyleFile) at Config.
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/goki/gi/gi"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+)
+
+// PlotStyle is the styling of the plots
+type PlotStyle struct {
+	Background string   `desc:"plot background color"`
+	Foreground string   `desc:"color of the text and axes"`
+	Colors     []string `desc:"colors to use (in order) for plotting successive lines -- empty to use the PlotColorNames"`
+	Font       string   `desc:"font of all the text"`
+	TitleSize  float64  `desc:"font size of the title, in points"`
+	LabelSize  float64  `desc:"font size of the axis labels, in points"`
+	TickSize   float64  `desc:"font size of the axis tick labels, in points"`
+	LegendSize float64  `desc:"font size of the legend, in points"`
+	LineWidth  float64  `desc:"width of the plotted lines, in points -- some plots use multiples of it, e.g., for a mean line"`
+}
+
+// DefaultPlotStyle is the default style: black on white
+var DefaultPlotStyle = PlotStyle{
+	Background: "white",
+	Foreground: "black",
+	Font:       "Helvetica",
+	TitleSize:  12,
+	LabelSize:  12,
+	TickSize:   10,
+	LegendSize: 12,
+	LineWidth:  1,
+}
+
+// DarkPlotStyle is light on dark, with a palette of light colors
+var DarkPlotStyle = PlotStyle{
+	Background: "#202020",
+	Foreground: "#e0e0e0",
+	Colors: []string{"white", "salmon", "SkyBlue", "chartreuse", "violet",
+		"orange", "tan", "cyan", "magenta", "yellow", "pink", "LightGreen"},
+	Font:       "Helvetica",
+	TitleSize:  12,
+	LabelSize:  12,
+	TickSize:   10,
+	LegendSize: 12,
+	LineWidth:  1,
+}
+
+// CurPlotStyle is the style used for all plots
+var CurPlotStyle = DefaultPlotStyle
+
+// Color returns the i-th color of the line palette, wrapping around
+func (ps *PlotStyle) Color(i int) gi.Color {
+	cnms := ps.Colors
+	if len(cnms) == 0 {
+		cnms = PlotColorNames
+	}
+	clr, _ := gi.ColorFromString(cnms[i%len(cnms)], nil)
+	return clr
+}
+
+// FgColor returns the Foreground color
+func (ps *PlotStyle) FgColor() gi.Color {
+	fg, _ := gi.ColorFromString(ps.Foreground, nil)
+	return fg
+}
+
+// Apply applies the style to the title, axes and legend of given plot
+func (ps *PlotStyle) Apply(plt *plot.Plot) {
+	bg, _ := gi.ColorFromString(ps.Background, nil)
+	fg := ps.FgColor()
+	plt.BackgroundColor = bg
+	plt.Title.TextStyle.Color = fg
+	plt.Title.TextStyle.Font.Size = vg.Points(ps.TitleSize)
+	for _, ax := range []*plot.Axis{&plt.X, &plt.Y} {
+		ax.LineStyle.Color = fg
+		ax.Label.TextStyle.Color = fg
+		ax.Label.TextStyle.Font.Size = vg.Points(ps.LabelSize)
+		ax.Tick.LineStyle.Color = fg
+		ax.Tick.Label.Color = fg
+		ax.Tick.Label.Font.Size = vg.Points(ps.TickSize)
+	}
+	plt.Legend.TextStyle.Color = fg
+	plt.Legend.TextStyle.Font.Size = vg.Points(ps.LegendSize)
+}
+
+// NewPlot returns a new plot in the CurPlotStyle
+func NewPlot() *plot.Plot {
+	plot.DefaultFont = CurPlotStyle.Font
+	plt, _ := plot.New()
+	CurPlotStyle.Apply(plt)
+	return plt
+}
+
+// OpenPlotStyle opens a PlotStyle from given JSON file -- fields not in
+// the file keep their DefaultPlotStyle values
+func OpenPlotStyle(fname string) (*PlotStyle, error) {
+	b, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	ps := DefaultPlotStyle
+	if err := json.Unmarshal(b, &ps); err != nil {
+		return nil, err
+	}
+	return &ps, nil
+}
+
+// SavePlotStyle saves given PlotStyle to given JSON file
+func SavePlotStyle(ps *PlotStyle, fname string) error {
+	b, err := json.MarshalIndent(ps, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fname, b, 0644)
+}
+
+// OpenPlotStyleFile sets the CurPlotStyle from PlotStyleFile, if set --
+// called in Config
+func (ss *Sim) OpenPlotStyleFile() error {
+	if ss.PlotStyleFile == "" {
+		return nil
+	}
+	ps, err := OpenPlotStyle(ss.PlotStyleFile)
+	if err != nil {
+		return err
+	}
+	CurPlotStyle = *ps
+	return nil
+}
+
+// ToggleDarkPlots switches the CurPlotStyle between the DefaultPlotStyle
+// and the DarkPlotStyle, returning whether it is now dark
+func ToggleDarkPlots() bool {
+	if CurPlotStyle.Background == DarkPlotStyle.Background {
+		CurPlotStyle = DefaultPlotStyle
+		return false
+	}
+	CurPlotStyle = DarkPlotStyle
+	return true
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// policy.go has the extraction of the learned Goal -> Motor mapping as an
+// explicit policy table: for each distinct goal (Outcome pattern) of the
+// ExtReps, a probe alpha cycle is run with the goal clamped on the Goal
+// layer and no Context (ProbeCyc), and the action decoded from the Motor
+// minus phase activity is recorded, with its confidence (its share of the
+// total Motor activity over actions), and the Outcome the network in turn
+// predicts for it, so the table shows at a glance which goals the network
+// has learned to achieve.
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// ConfigPolicy sets up the Policy table
+func (ss *Sim) ConfigPolicy() {
+	ss.Policy.SetFromSchema(etable.Schema{
+		{"Goal", etensor.STRING, nil, nil},
+		{"Action", etensor.INT64, nil, nil},
+		{"ActName", etensor.STRING, nil, nil},
+		{"Confidence", etensor.FLOAT32, nil, nil},
+		{"PredOut", etensor.STRING, nil, nil},
+		{"PredOutCos", etensor.FLOAT32, nil, nil},
+		{"Achieves", etensor.INT64, nil, nil},
+	}, 0)
+}
+
+// ActName returns the name of given decoded action index
+func ActName(act int) string {
+	if act < 0 {
+		return "none"
+	}
+	return fmt.Sprintf("act%d", act)
+}
+
+// ActConfidence returns the share of given decoded action in the total
+// activity of given variable of the Motor layer over all actions (units, or
+// pools if PoolsOn) -- 0 if there is no activity
+func (ss *Sim) ActConfidence(ly *leabra.Layer, varNm string, act int) float32 {
+	vals, err := ly.UnitVals(varNm)
+	if err != nil || act < 0 {
+		return 0
+	}
+	pu := 1
+	if ss.PoolsOn {
+		pu = ss.PoolUnits()
+	}
+	var sum, asum float32
+	for i, v := range vals {
+		sum += v
+		if i/pu == act {
+			asum += v
+		}
+	}
+	if sum == 0 {
+		return 0
+	}
+	return asum / sum
+}
+
+// FirstSamePat returns the index of the first of given patterns that is
+// identical to pattern i
+func FirstSamePat(pats [][]float32, i int) int {
+	for j := 0; j < i; j++ {
+		if len(pats[j]) != len(pats[i]) {
+			continue
+		}
+		same := true
+		for k := range pats[i] {
+			if pats[j][k] != pats[i][k] {
+				same = false
+				break
+			}
+		}
+		if same {
+			return j
+		}
+	}
+	return i
+}
+
+// ExtractPolicy fills the Policy table with the action the network takes
+// for each distinct Outcome pattern of the ExtReps as a goal, and the
+// Outcome it predicts for it
+func (ss *Sim) ExtractPolicy() {
+	et := ss.ExtReps
+	ss.ConfigPolicy()
+	if et == nil || et.NumRows() == 0 {
+		return
+	}
+	var dec Decoder
+	if err := dec.InitFromTable(et, "Outcome"); err != nil {
+		log.Println(err)
+		return
+	}
+	motorLay := ss.Net.LayerByName("Motor").(*leabra.Layer)
+	outcomeLay := ss.Net.LayerByName("Outcome").(*leabra.Layer)
+	ctxt := etensor.NewFloat32([]int{5, 5}, nil, []string{"Y", "X"})
+	oc := et.ColByName("Outcome")
+	dt := ss.Policy
+	for row := 0; row < et.NumRows(); row++ {
+		if FirstSamePat(dec.Pats, row) != row {
+			continue // same goal as an earlier item
+		}
+		ss.ProbeCyc(ctxt, RowCell(oc, row))
+		act := ss.ActIdx(motorLay, "ActM")
+		pnm, pidx, pcos := dec.DecodeLayer(outcomeLay, "ActM")
+		prow := dt.NumRows()
+		dt.SetNumRows(prow + 1)
+		dt.ColByName("Goal").SetString1D(prow, ItemName(et, row))
+		dt.ColByName("Action").SetFloat1D(prow, float64(act))
+		dt.ColByName("ActName").SetString1D(prow, ActName(act))
+		dt.ColByName("Confidence").SetFloat1D(prow, float64(ss.ActConfidence(motorLay, "ActM", act)))
+		dt.ColByName("PredOut").SetString1D(prow, pnm)
+		dt.ColByName("PredOutCos").SetFloat1D(prow, float64(pcos))
+		ach := 0
+		if pidx >= 0 && FirstSamePat(dec.Pats, pidx) == row {
+			ach = 1
+		}
+		dt.ColByName("Achieves").SetFloat1D(prow, float64(ach))
+	}
+	ss.UpdateView()
+}
+
+// SavePolicy runs ExtractPolicy and saves the Policy table to given .tsv file
+func (ss *Sim) SavePolicy(fname string) error {
+	ss.ExtractPolicy()
+	if err := SaveTable(ss.Policy, fname); err != nil {
+		return err
+	}
+	fmt.Printf("saved policy of %d goals to: %s\n", ss.Policy.NumRows(), fname)
+	return nil
+}
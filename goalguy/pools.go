@@ -0,0 +1,144 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// pools.go has the pooled (topographic) option for the Motor and Outcome
+// layers: if PoolsOn, they are built as 4D layers of NPools pools, one per
+// action / outcome category, of OutUnits / NPools units each, with
+// pool-level inhibition in addition to the layer-level.  This allows for
+// distributed representations within each pool, while the categories stay
+// decodable: the decoded action (ActIdx) is the pool with the most active
+// unit, rather than the unit itself.
+//
+// The Motor and Outcome patterns (ExtReps, Contings, DriveOuts) then have
+// the same 4D shape as the layers (OutShape), and Outcome patterns are
+// generated with PatNOn active units within one randomly chosen pool
+// (GenOutPats).  The Goal layer stays 2D, with the same number of units, and
+// receives the Outcome patterns in unit order.
+
+import (
+	"log"
+	"math/rand"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/emergent/patgen"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// OutUnits is the number of units in the Goal, Motor and Outcome layers
+const OutUnits = 25
+
+// PoolParams are the params added for the Motor and Outcome layers if PoolsOn
+var PoolParams = emer.ParamStyle{
+	{"#Motor", emer.Params{
+		"Layer.Inhib.Pool.On": 1,
+		"Layer.Inhib.Pool.Gi": 1.8,
+	}},
+	{"#Outcome", emer.Params{
+		"Layer.Inhib.Pool.On": 1,
+		"Layer.Inhib.Pool.Gi": 1.8,
+	}},
+}
+
+// NPoolsEff returns the effective number of Motor / Outcome pools if
+// PoolsOn: NPools if it evenly divides OutUnits, else 5
+func (ss *Sim) NPoolsEff() int {
+	if ss.NPools <= 0 || OutUnits%ss.NPools != 0 {
+		log.Printf("NPools: %d does not divide the %d Motor / Outcome units -- using 5\n", ss.NPools, OutUnits)
+		ss.NPools = 5
+	}
+	return ss.NPools
+}
+
+// PoolUnits returns the number of units per Motor / Outcome pool if PoolsOn
+func (ss *Sim) PoolUnits() int {
+	return OutUnits / ss.NPoolsEff()
+}
+
+// OutShape returns the shape and dim names of the Motor and Outcome layers,
+// and of all the patterns applied to them
+func (ss *Sim) OutShape() ([]int, []string) {
+	if !ss.PoolsOn {
+		return []int{5, 5}, []string{"Y", "X"}
+	}
+	return []int{1, ss.NPoolsEff(), 1, ss.PoolUnits()}, []string{"PoolY", "PoolX", "NeurY", "NeurX"}
+}
+
+// AddOutLayer adds a Motor or Outcome layer of OutShape to the network
+func (ss *Sim) AddOutLayer(net *leabra.Network, name string, typ emer.LayerType) emer.Layer {
+	shp, _ := ss.OutShape()
+	if len(shp) == 4 {
+		return net.AddLayer4D(name, shp[0], shp[1], shp[2], shp[3], typ)
+	}
+	return net.AddLayer2D(name, shp[0], shp[1], typ)
+}
+
+// StylePoolParams applies the PoolParams to the network, if PoolsOn --
+// called after the Params are styled
+func (ss *Sim) StylePoolParams() {
+	if ss.PoolsOn {
+		ss.Net.StyleParams(PoolParams, false)
+	}
+}
+
+// GenOutPats generates random Outcome patterns into given column: PatNOn
+// of the units of one random pool per row if PoolsOn, else PatNOn of all
+// the units, with PatNShared of them shared by all the rows and at least
+// PatMinDiff apart (see patstruct.go)
+func (ss *Sim) GenOutPats(col etensor.Tensor) {
+	if !ss.PoolsOn {
+		if ss.PatOverlap <= 0 && ss.PatMinDiff <= 0 {
+			patgen.PermutedBinaryRows(col, ss.PatNOn, 1, 0)
+			return
+		}
+		if !GenOverlapRows(col, ss.PatNOn, ss.PatNShared(), ss.PatMinDiff) {
+			log.Printf("GenOutPats: could not generate Outcome patterns at least PatMinDiff: %d apart\n", ss.PatMinDiff)
+		}
+		return
+	}
+	rows, cells := col.RowCellSize()
+	pu := ss.PoolUnits()
+	non := ss.PatNOn
+	if non > pu {
+		non = pu
+	}
+	for row := 0; row < rows; row++ {
+		st := row*cells + rand.Intn(ss.NPoolsEff())*pu
+		for i := 0; i < cells; i++ {
+			col.SetFloat1D(row*cells+i, 0)
+		}
+		for _, i := range rand.Perm(pu)[:non] {
+			col.SetFloat1D(st+i, 1)
+		}
+	}
+}
+
+// NActs returns the number of decodable Motor actions / Outcome categories:
+// the number of pools if PoolsOn, else the number of units
+func (ss *Sim) NActs() int {
+	if ss.PoolsOn {
+		return ss.NPoolsEff()
+	}
+	return OutUnits
+}
+
+// ActIdx returns the decoded action / category of given Motor or Outcome
+// layer from given variable (e.g., ActM): the index of the most active unit,
+// or of the pool containing it if PoolsOn -- -1 if none
+func (ss *Sim) ActIdx(ly *leabra.Layer, varNm string) int {
+	mi := MaxUnitIdx(ly, varNm)
+	if mi < 0 || !ss.PoolsOn {
+		return mi
+	}
+	return mi / ss.PoolUnits()
+}
+
+// RowCell returns the cell at given row of a table column, of whatever
+// dimensionality the cells are (e.g., 2D, or 4D for pooled Outcomes)
+func RowCell(col etensor.Tensor, row int) etensor.Tensor {
+	c, _ := col.SubSpace(col.NumDims()-1, []int{row})
+	return c
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import "log"
+
+// PrjnLrn specifies the mix of error-driven vs. Hebbian learning in the
+// XCal learning rule for one projection, e.g., MLrn = 0, LLrn = 1 for
+// purely Hebbian learning, and MLrn = 1, LLrn = 0 for purely error-driven
+type PrjnLrn struct {
+	Prjn string  `desc:"projection as Send:Recv layer names, e.g., Context:Goal"`
+	MLrn float32 `desc:"amount of error-driven (medium-term average) learning -- Learn.XCal.MLrn"`
+	LLrn float32 `desc:"amount of Hebbian (long-term average) learning -- sets Learn.XCal.SetLLrn so this fixed value is used instead of the automatic layer-level modulation"`
+}
+
+// ApplyPrjnLrns applies the PrjnLrns learning mix settings to the network
+// projections -- called after params are styled in ConfigNet and Init
+func (ss *Sim) ApplyPrjnLrns() {
+	for _, pl := range ss.PrjnLrns {
+		pj, err := ss.PrjnByPath(pl.Prjn)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		pj.Learn.XCal.MLrn = pl.MLrn
+		pj.Learn.XCal.SetLLrn = true
+		pj.Learn.XCal.LLrn = pl.LLrn
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"fmt"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+	"github.com/goki/gi/gi"
+	"github.com/goki/ki/ki"
+)
+
+// The Probe tab lets the user hand-set the Context and Goal inputs by
+// clicking cells in 5x5 grids, run a test alpha cycle with them (RunProbe),
+// and see the resulting Motor and Outcome minus phase activity -- for
+// demoing and debugging what the trained network does for arbitrary inputs.
+
+// ProbeCyc runs one motor-production AlphaCycle of testing with given
+// Context and Goal inputs clamped -- the Goal is left unclamped if goal is
+// nil or all zeros.  Motor and Outcome are free (Hidden).
+func (ss *Sim) ProbeCyc(ctxt, goal etensor.Tensor) {
+	contextLay := ss.Net.LayerByName("Context").(*leabra.Layer)
+	goalLay := ss.Net.LayerByName("Goal").(*leabra.Layer)
+
+	ss.Net.InitExt()
+	goalLay.SetType(emer.Hidden)
+	ss.Net.LayerByName("Motor").SetType(emer.Hidden)
+	ss.Net.LayerByName("Outcome").SetType(emer.Hidden)
+	contextLay.ApplyExt(ctxt)
+	if goal != nil && !TensorZero(goal) {
+		goalLay.SetType(emer.Input)
+		goalLay.ApplyExt(goal)
+	}
+	ss.AlphaCycle = 1
+	ss.AlphaCyc(false)
+	ss.AlphaCycle = 0
+}
+
+// TensorZero returns whether all the values in the tensor are zero
+func TensorZero(tsr etensor.Tensor) bool {
+	for i := 0; i < tsr.Len(); i++ {
+		if tsr.FloatVal1D(i) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// RunProbe runs ProbeCyc on the ProbeCtxt and ProbeGoal inputs set in the
+// Probe tab, and updates the displayed Motor and Outcome activity
+func (ss *Sim) RunProbe() {
+	ss.ProbeCyc(ss.ProbeCtxt, ss.ProbeGoal)
+	ss.UpdtProbeOuts()
+}
+
+// UpdtProbeOuts updates the Motor and Outcome activity labels in the Probe tab
+func (ss *Sim) UpdtProbeOuts() {
+	for lnm, lbls := range ss.ProbeOutLbls {
+		ly := ss.Net.LayerByName(lnm).(*leabra.Layer)
+		acts, err := ly.UnitVals("ActM")
+		if err != nil {
+			continue
+		}
+		for i, lb := range lbls {
+			if i < len(acts) {
+				lb.SetText(fmt.Sprintf("%.2f", acts[i]))
+			}
+		}
+	}
+}
+
+// AddProbeGrid adds a labeled 5x5 grid of check boxes to given parent,
+// that sets the corresponding values of given tensor when toggled --
+// returns the check boxes
+func AddProbeGrid(par *gi.Frame, label string, tsr *etensor.Float32) []*gi.CheckBox {
+	gi.AddNewLabel(par, label+"Lbl", label)
+	grid := gi.AddNewLayout(par, label+"Grid", gi.LayoutGrid)
+	grid.SetProp("columns", 5)
+	cbs := make([]*gi.CheckBox, len(tsr.Values))
+	for i := range tsr.Values {
+		cb := gi.AddNewCheckBox(grid, fmt.Sprintf("%s%d", label, i))
+		cbs[i] = cb
+		cb.SetChecked(tsr.Values[i] > 0)
+		idx := i
+		cb.ButtonSig.Connect(par.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig != int64(gi.ButtonToggled) {
+				return
+			}
+			if send.(*gi.CheckBox).IsChecked() {
+				tsr.Values[idx] = 1
+			} else {
+				tsr.Values[idx] = 0
+			}
+		})
+	}
+	return cbs
+}
+
+// AddProbeOuts adds a labeled 5x5 grid of activity labels for given layer
+// to given parent, returning the labels
+func AddProbeOuts(par *gi.Frame, lnm string) []*gi.Label {
+	gi.AddNewLabel(par, lnm+"Lbl", lnm+" ActM")
+	grid := gi.AddNewLayout(par, lnm+"Grid", gi.LayoutGrid)
+	grid.SetProp("columns", 5)
+	lbls := make([]*gi.Label, 25)
+	for i := range lbls {
+		lbls[i] = gi.AddNewLabel(grid, fmt.Sprintf("%s%d", lnm, i), "0.00")
+	}
+	return lbls
+}
+
+// ConfigProbeTab adds the Probe tab to given tab view, re-rendering given
+// viewport after each probe
+func (ss *Sim) ConfigProbeTab(tv *gi.TabView, vp *gi.Viewport2D) {
+	ss.ProbeCtxt = etensor.NewFloat32([]int{5, 5}, nil, []string{"Y", "X"})
+	ss.ProbeGoal = etensor.NewFloat32([]int{5, 5}, nil, []string{"Y", "X"})
+	fr := tv.AddNewTab(gi.KiT_Frame, "Probe").(*gi.Frame)
+	fr.Lay = gi.LayoutVert
+
+	ins := gi.AddNewFrame(fr, "ins", gi.LayoutHoriz)
+	cfr := gi.AddNewFrame(ins, "ctxt", gi.LayoutVert)
+	AddProbeGrid(cfr, "Context", ss.ProbeCtxt)
+	gfr := gi.AddNewFrame(ins, "goal", gi.LayoutVert)
+	AddProbeGrid(gfr, "Goal", ss.ProbeGoal)
+
+	run := gi.AddNewButton(fr, "run")
+	run.SetText("Run Probe")
+	run.ButtonSig.Connect(fr.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonClicked) {
+			ss.RunProbe()
+			vp.FullRender2DTree()
+		}
+	})
+
+	outs := gi.AddNewFrame(fr, "outs", gi.LayoutHoriz)
+	ss.ProbeOutLbls = make(map[string][]*gi.Label)
+	for _, lnm := range []string{"Motor", "Outcome"} {
+		ofr := gi.AddNewFrame(outs, lnm, gi.LayoutVert)
+		ss.ProbeOutLbls[lnm] = AddProbeOuts(ofr, lnm)
+	}
+}
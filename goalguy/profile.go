@@ -0,0 +1,114 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// profile.go has the run-time performance profiling: if ProfOn, the time
+// spent in each of the ProfSections of the run is accumulated, so it can be
+// seen where the epoch time goes -- e.g., before scaling up to distributed
+// representations.  RunProfile runs Train with ProfOn, under the pprof CPU
+// profiler, writes a heap profile at the end, and prints the ProfReport.
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+
+	"github.com/emer/emergent/timer"
+)
+
+// the sections timed when ProfOn
+const (
+	ProfCycle = "Cycle" // network cycling, including the KWTA and timecourse recording
+	ProfDWt   = "DWt"   // computing and applying the weight changes
+	ProfStats = "Stats" // trial and epoch stats and logging
+	ProfGUI   = "GUI"   // updating the network views and plots
+)
+
+// ProfSections are the sections timed when ProfOn, in report order
+var ProfSections = []string{ProfCycle, ProfDWt, ProfStats, ProfGUI}
+
+// InitProf resets the timers of all the ProfSections
+func (ss *Sim) InitProf() {
+	ss.ProfTimers = make(map[string]*timer.Time, len(ProfSections))
+	for _, sec := range ProfSections {
+		ss.ProfTimers[sec] = &timer.Time{}
+	}
+}
+
+// ProfStart starts timing given section, if ProfOn
+func (ss *Sim) ProfStart(sec string) {
+	if !ss.ProfOn {
+		return
+	}
+	if tm, has := ss.ProfTimers[sec]; has {
+		tm.Start()
+	}
+}
+
+// ProfStop stops timing given section, if ProfOn
+func (ss *Sim) ProfStop(sec string) {
+	if !ss.ProfOn {
+		return
+	}
+	if tm, has := ss.ProfTimers[sec]; has {
+		tm.Stop()
+	}
+}
+
+// ProfReport returns the total time spent in each of the ProfSections, and
+// its percent of given total run time in secs
+func (ss *Sim) ProfReport(totSecs float64) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-8s %10s %8s %10s\n", "Section", "Secs", "Pct", "N")
+	other := totSecs
+	for _, sec := range ProfSections {
+		tm, has := ss.ProfTimers[sec]
+		if !has {
+			continue
+		}
+		secs := tm.TotalSecs()
+		other -= secs
+		fmt.Fprintf(&b, "%-8s %10.3f %7.1f%% %10d\n", sec, secs, 100*secs/totSecs, tm.N)
+	}
+	fmt.Fprintf(&b, "%-8s %10.3f %7.1f%%\n", "Other", other, 100*other/totSecs)
+	fmt.Fprintf(&b, "%-8s %10.3f\n", "Total", totSecs)
+	return b.String()
+}
+
+// RunProfile runs Train with ProfOn under the pprof CPU profiler, writing
+// the CPU profile to <fname>.cpu.prof and a heap profile at the end to
+// <fname>.mem.prof, and prints the ProfReport of per-section timing
+func (ss *Sim) RunProfile(fname string) error {
+	cf, err := os.Create(fname + ".cpu.prof")
+	if err != nil {
+		return err
+	}
+	defer cf.Close()
+	if err := pprof.StartCPUProfile(cf); err != nil {
+		return err
+	}
+	ss.ProfOn = true
+	ss.InitProf()
+	tmr := timer.Time{}
+	tmr.Start()
+	ss.Train()
+	tmr.Stop()
+	pprof.StopCPUProfile()
+	ss.ProfOn = false
+
+	mf, err := os.Create(fname + ".mem.prof")
+	if err != nil {
+		return err
+	}
+	defer mf.Close()
+	runtime.GC() // get up-to-date statistics
+	if err := pprof.WriteHeapProfile(mf); err != nil {
+		return err
+	}
+	fmt.Print(ss.ProfReport(tmr.TotalSecs()))
+	return nil
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// progress.go has the progress lines printed to stdout every ProgressEvery
+// training epochs (e.g., in -nogui mode), so long batch jobs can be
+// monitored from their log files: the epoch, key stats, the elapsed time
+// of the Train, and the estimated time remaining to MaxEpcs, at the
+// average time per epoch so far (an upper bound if NZeroStop stops it
+// earlier).
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReportProgress prints the progress line for the epoch just completed,
+// if it is one of every ProgressEvery -- called at the end of each
+// training epoch, after LogEpoch
+func (ss *Sim) ReportProgress() {
+	if ss.ProgressEvery <= 0 || (ss.Epoch+1)%ss.ProgressEvery != 0 {
+		return
+	}
+	el := time.Since(ss.TrainTmr.St)
+	eta := time.Duration(0)
+	if done := ss.Epoch + 1 - ss.TrainStEpc; done > 0 && ss.MaxEpcs > ss.Epoch+1 {
+		eta = el / time.Duration(done) * time.Duration(ss.MaxEpcs-ss.Epoch-1)
+	}
+	fmt.Printf("epoch %d/%d  OutGoalPctErr: %.4g  OutPredPctErr: %.4g  OutSSE: %.4g  MotSSE: %.4g  NZero: %d  elapsed: %v  eta: %v\n",
+		ss.Epoch+1, ss.MaxEpcs, ss.EpcOutGoalPctErr, ss.EpcOutPredPctErr, ss.EpcOutSSE, ss.EpcMotSSE, ss.NZero,
+		el.Round(time.Second), eta.Round(time.Second))
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Phase is one phase of a multi-phase experimental protocol: a number of
+// training epochs run after Start has set up the environment for the phase
+type Phase struct {
+	Name   string        `desc:"name of the phase, recorded as the current Phase while it runs"`
+	Epochs int           `desc:"number of training epochs in the phase"`
+	Start  func(ss *Sim) `desc:"sets up the environment for the phase -- called before its first epoch"`
+}
+
+// Protocols is the registry of multi-phase experimental protocols -- each
+// returns its phases given the current Sim config, so phase lengths etc.
+// can be set in the gui prior to running
+var Protocols = map[string]func(ss *Sim) []*Phase{
+	"degradation": DegradProtocol,
+	"extinction":  ExtinctProtocol,
+	"reversal":    RevProtocol,
+}
+
+// ProtocolNames returns the sorted names of all registered protocols
+func ProtocolNames() []string {
+	nms := make([]string, 0, len(Protocols))
+	for nm := range Protocols {
+		nms = append(nms, nm)
+	}
+	sort.Strings(nms)
+	return nms
+}
+
+// RunProtocol runs all the phases of the protocol of given name, in order,
+// training from the current state of the network (which is typically
+// initialized and not yet trained).  The current Phase is recorded in the
+// EpcLog, and cleared when the protocol completes.
+func (ss *Sim) RunProtocol(name string) error {
+	pf, ok := Protocols[name]
+	if !ok {
+		return fmt.Errorf("protocol %q not found -- valid names: %v", name, ProtocolNames())
+	}
+	ss.StopNow = false
+	for _, ph := range pf(ss) {
+		ss.Phase = ph.Name
+		if ph.Start != nil {
+			ph.Start(ss)
+		}
+		for epc := 0; epc < ph.Epochs; epc++ {
+			ss.TrainEpoch()
+			if ss.StopNow {
+				return nil
+			}
+		}
+	}
+	ss.Phase = ""
+	return nil
+}
+
+// DegradProtocol is the contingency-degradation protocol: an acquisition
+// phase of AcqEpcs epochs with the closed-loop Contings environment,
+// followed by a degradation phase of DegradEpcs epochs in which the Out1
+// outcome of DegradAction is also delivered after any other action with
+// probability DegradP.  The change in action selection is recorded in the
+// DegradActPct epoch stat.
+func DegradProtocol(ss *Sim) []*Phase {
+	return []*Phase{
+		{Name: "acquisition", Epochs: ss.AcqEpcs, Start: func(ss *Sim) {
+			ss.ContingOn = true
+			ss.Degraded = false
+		}},
+		{Name: "degradation", Epochs: ss.DegradEpcs, Start: func(ss *Sim) {
+			ss.ContingOn = true
+			ss.Degraded = true
+		}},
+	}
+}
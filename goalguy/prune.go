@@ -0,0 +1,113 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// prune.go has the pruning of the PrunePrjns projections during training,
+// to test whether the learned mapping (e.g., Goal -> Motor) is effectively
+// sparse: every PruneInterval epochs, the weights below PruneThr are zeroed
+// and frozen at 0 for the rest of the run (FreezePruned re-zeroes them
+// after every weight update), and the sparsity of each projection -- the
+// proportion of its synapses pruned so far -- is appended to the PruneLog.
+// Its effect on learning shows in the EpcLog stats after each pruning step.
+
+import (
+	"log"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// ConfigPruneLog sets up the PruneLog table of the sparsity per projection
+// at each pruning step
+func (ss *Sim) ConfigPruneLog() {
+	ss.PruneLog.SetFromSchema(etable.Schema{
+		{"Epoch", etensor.INT64, nil, nil},
+		{"Prjn", etensor.STRING, nil, nil},
+		{"NSyns", etensor.INT64, nil, nil},
+		{"NNew", etensor.INT64, nil, nil},
+		{"NPruned", etensor.INT64, nil, nil},
+		{"Sparsity", etensor.FLOAT32, nil, nil},
+	}, 0)
+}
+
+// ResetPrune clears the pruned synapses and the PruneLog -- called in Init
+func (ss *Sim) ResetPrune() {
+	ss.Pruned = map[string][]bool{}
+	ss.ConfigPruneLog()
+}
+
+// CheckPrune prunes the PrunePrjns every PruneInterval epochs -- called at
+// the end of each training epoch
+func (ss *Sim) CheckPrune() {
+	if ss.PruneInterval <= 0 || (ss.Epoch+1)%ss.PruneInterval != 0 {
+		return
+	}
+	ss.Prune()
+}
+
+// Prune zeroes and freezes the weights below PruneThr of each of the
+// PrunePrjns, logging its sparsity in the PruneLog
+func (ss *Sim) Prune() {
+	if ss.Pruned == nil {
+		ss.Pruned = map[string][]bool{}
+	}
+	dt := ss.PruneLog
+	for _, path := range ss.PrunePrjns {
+		pj, err := ss.PrjnByPath(path)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		msk := ss.Pruned[path]
+		if len(msk) != len(pj.Syns) {
+			msk = make([]bool, len(pj.Syns))
+			ss.Pruned[path] = msk
+		}
+		nnew, npr := 0, 0
+		for si := range pj.Syns {
+			if !msk[si] && pj.Syns[si].Wt < ss.PruneThr {
+				msk[si] = true
+				nnew++
+			}
+			if msk[si] {
+				npr++
+			}
+		}
+		row := dt.NumRows()
+		dt.SetNumRows(row + 1)
+		dt.ColByName("Epoch").SetFloat1D(row, float64(ss.Epoch))
+		dt.ColByName("Prjn").SetString1D(row, path)
+		dt.ColByName("NSyns").SetFloat1D(row, float64(len(pj.Syns)))
+		dt.ColByName("NNew").SetFloat1D(row, float64(nnew))
+		dt.ColByName("NPruned").SetFloat1D(row, float64(npr))
+		sp := 0.0
+		if len(pj.Syns) > 0 {
+			sp = float64(npr) / float64(len(pj.Syns))
+		}
+		dt.ColByName("Sparsity").SetFloat1D(row, sp)
+	}
+	ss.FreezePruned()
+}
+
+// FreezePruned zeroes the weights, and their changes, of the pruned
+// synapses -- called after every weight update
+func (ss *Sim) FreezePruned() {
+	for path, msk := range ss.Pruned {
+		pj, err := ss.PrjnByPath(path)
+		if err != nil || len(msk) != len(pj.Syns) {
+			continue
+		}
+		for si, pr := range msk {
+			if !pr {
+				continue
+			}
+			sy := &pj.Syns[si]
+			sy.Wt = 0
+			sy.LWt = 0
+			sy.DWt = 0
+			sy.Moment = 0
+		}
+	}
+}
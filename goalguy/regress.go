@@ -0,0 +1,141 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+)
+
+// The mini-run regression (MiniRegress) trains a tiny deterministic
+// configuration -- fixed seed, MiniNPats generated patterns, MiniEpcs
+// epochs, no gui -- and checks the final epoch stats against golden values
+// saved in a JSON file, so that refactors of the trial loop can be
+// validated automatically (see regress_test.go, and the -minirun flag).  The
+// golden file is only written when updating, from a known-good version of
+// the code -- a missing golden file is an error.
+
+const (
+	// MiniSeed is the random seed for the mini-run
+	MiniSeed = 1
+
+	// MiniNPats is the number of patterns in the mini-run
+	MiniNPats = 5
+
+	// MiniEpcs is the number of training epochs in the mini-run
+	MiniEpcs = 20
+)
+
+// MiniStats are the final epoch stats of a mini-run
+type MiniStats struct {
+	MotSSE        float32
+	OutSSE        float32
+	MotCosDiff    float32
+	OutCosDiff    float32
+	OutGoalPctErr float32
+	OutPredPctErr float32
+}
+
+// MiniRun configures the sim for the mini-run, trains it, and returns the
+// final epoch stats.  The ExtReps are generated from MiniSeed, and the
+// pattern files are neither read nor written (NoPatsFile).
+func (ss *Sim) MiniRun() *MiniStats {
+	ss.New()
+	ss.NoPatsFile = true
+	ss.RndSeed = MiniSeed
+	ss.MaxEpcs = MiniEpcs
+	ss.ViewOn = false
+	ss.Plot = false
+	ss.Config()
+	rand.Seed(ss.RndSeed)
+	ss.GenExtReps(ss.ExtReps, MiniNPats)
+	ss.ValReps.SetNumRows(0)
+	ss.Init()
+	ss.Train()
+	return &MiniStats{
+		MotSSE:        ss.EpcMotSSE,
+		OutSSE:        ss.EpcOutSSE,
+		MotCosDiff:    ss.EpcMotCosDiff,
+		OutCosDiff:    ss.EpcOutCosDiff,
+		OutGoalPctErr: ss.EpcOutGoalPctErr,
+		OutPredPctErr: ss.EpcOutPredPctErr,
+	}
+}
+
+// Compare returns an error listing the stats that differ from the golden
+// values by more than tol, nil if all match -- a NaN value never matches
+func (ms *MiniStats) Compare(gold *MiniStats, tol float32) error {
+	type stat struct {
+		nm        string
+		val, gold float32
+	}
+	sts := []stat{
+		{"MotSSE", ms.MotSSE, gold.MotSSE},
+		{"OutSSE", ms.OutSSE, gold.OutSSE},
+		{"MotCosDiff", ms.MotCosDiff, gold.MotCosDiff},
+		{"OutCosDiff", ms.OutCosDiff, gold.OutCosDiff},
+		{"OutGoalPctErr", ms.OutGoalPctErr, gold.OutGoalPctErr},
+		{"OutPredPctErr", ms.OutPredPctErr, gold.OutPredPctErr},
+	}
+	msg := ""
+	for _, st := range sts {
+		d := math.Abs(float64(st.val) - float64(st.gold))
+		if math.IsNaN(d) || d > float64(tol) {
+			msg += fmt.Sprintf("\n\t%s: %g, golden: %g", st.nm, st.val, st.gold)
+		}
+	}
+	if msg != "" {
+		return fmt.Errorf("mini-run stats differ from golden values:%s", msg)
+	}
+	return nil
+}
+
+// SaveJSON saves the stats to given JSON file
+func (ms *MiniStats) SaveJSON(fname string) error {
+	b, err := json.MarshalIndent(ms, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fname, b, 0644)
+}
+
+// OpenJSON opens the stats from given JSON file
+func (ms *MiniStats) OpenJSON(fname string) error {
+	b, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, ms)
+}
+
+// MiniRegress runs the mini-run and compares its stats against the golden
+// values in given file, within tol.  If update is true, the golden values
+// are written instead -- otherwise a missing golden file is an error.
+func (ss *Sim) MiniRegress(golden string, update bool, tol float32) error {
+	if _, err := os.Stat(golden); !update && os.IsNotExist(err) {
+		return fmt.Errorf("mini-run golden file %s does not exist -- run with update to write it", golden)
+	}
+	ms := ss.MiniRun()
+	if err := ss.Stats.Verify(); err != nil {
+		return err
+	}
+	if update {
+		fmt.Printf("writing mini-run golden values to: %s\n", golden)
+		return ms.SaveJSON(golden)
+	}
+	gold := &MiniStats{}
+	if err := gold.OpenJSON(golden); err != nil {
+		return err
+	}
+	if err := ms.Compare(gold, tol); err != nil {
+		return err
+	}
+	fmt.Println("mini-run stats match golden values")
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"flag"
+	"math"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "write the mini-run golden values instead of comparing against them")
+
+// MiniGolden is the file of the golden mini-run stats
+const MiniGolden = "testdata/mini_golden.json"
+
+// TestMiniRun runs the mini-run and compares its final epoch stats against
+// the golden values -- run with -update to write them, from a known-good
+// version of the code
+func TestMiniRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("mini-run skipped in short mode")
+	}
+	var ss Sim
+	if err := ss.MiniRegress(MiniGolden, *update, 1.0e-4); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMiniStatsCompare(t *testing.T) {
+	gold := &MiniStats{MotSSE: 1, OutSSE: 0.5}
+	ms := *gold
+	if err := ms.Compare(gold, 1.0e-4); err != nil {
+		t.Errorf("identical stats: %v", err)
+	}
+	ms.OutSSE = 0.6
+	if err := ms.Compare(gold, 1.0e-4); err == nil {
+		t.Errorf("OutSSE off by 0.1 not reported")
+	}
+	ms.OutSSE = float32(math.NaN())
+	if err := ms.Compare(gold, 1.0e-4); err == nil {
+		t.Errorf("NaN OutSSE not reported")
+	}
+}
+
+func TestMiniRegressNoGolden(t *testing.T) {
+	var ss Sim
+	if err := ss.MiniRegress("testdata/no_such_golden.json", false, 1.0e-4); err == nil {
+		t.Errorf("missing golden file not reported")
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// replay.go has the recording and replaying of the presentation order of
+// an Env: the Porder of every epoch is recorded in its Hist, which can be
+// saved as a table of Epoch, Trial, Row (Save Order, and order_hist.tsv in
+// the run bundle), and the TrainEnv of a later run can replay it exactly
+// (ReplayFile), e.g., for matched yoked-control comparisons between model
+// variants.  When the replayed epochs run out, or do not fit the table,
+// the Env goes back to its Order.
+
+import (
+	"log"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// RecordPorder records the current Porder in the Hist
+func (ev *Env) RecordPorder() {
+	ev.Hist = append(ev.Hist, append([]int{}, ev.Porder...))
+}
+
+// ReplayPorder sets Porder to the next replayed epoch, returning false if
+// there is none that fits the table
+func (ev *Env) ReplayPorder() bool {
+	if ev.ReplayEpc >= len(ev.Replay) {
+		if ev.Replay != nil && ev.ReplayEpc == len(ev.Replay) {
+			log.Printf("%s: replayed all %d epochs -- continuing with Order: %v\n", ev.Nm, len(ev.Replay), ev.Order)
+			ev.ReplayEpc++
+		}
+		return false
+	}
+	po := ev.Replay[ev.ReplayEpc]
+	np := ev.NumRows()
+	if len(po) != np {
+		log.Printf("%s: replayed epoch %d has %d trials, table has %d rows -- using Order: %v\n", ev.Nm, ev.ReplayEpc, len(po), np, ev.Order)
+		ev.ReplayEpc++
+		return false
+	}
+	for _, row := range po {
+		if row < 0 || row >= np {
+			log.Printf("%s: replayed epoch %d has invalid row %d -- using Order: %v\n", ev.Nm, ev.ReplayEpc, row, ev.Order)
+			ev.ReplayEpc++
+			return false
+		}
+	}
+	copy(ev.Porder, po)
+	ev.ReplayEpc++
+	return true
+}
+
+// HistTable returns the Hist as a table of the Row presented at each Trial
+// of each Epoch
+func (ev *Env) HistTable() *etable.Table {
+	n := 0
+	for _, po := range ev.Hist {
+		n += len(po)
+	}
+	dt := &etable.Table{}
+	dt.SetFromSchema(etable.Schema{
+		{"Epoch", etensor.INT64, nil, nil},
+		{"Trial", etensor.INT64, nil, nil},
+		{"Row", etensor.INT64, nil, nil},
+	}, n)
+	i := 0
+	for epc, po := range ev.Hist {
+		for trl, row := range po {
+			dt.ColByName("Epoch").SetFloat1D(i, float64(epc))
+			dt.ColByName("Trial").SetFloat1D(i, float64(trl))
+			dt.ColByName("Row").SetFloat1D(i, float64(row))
+			i++
+		}
+	}
+	return dt
+}
+
+// SetReplay sets the Replay epochs from given table in HistTable format
+func (ev *Env) SetReplay(dt *etable.Table) {
+	ev.Replay = nil
+	ec, rc := dt.ColByName("Epoch"), dt.ColByName("Row")
+	if ec == nil || rc == nil {
+		log.Printf("%s: replay table needs Epoch and Row columns\n", ev.Nm)
+		return
+	}
+	for i := 0; i < dt.NumRows(); i++ {
+		epc := int(ec.FloatVal1D(i))
+		for len(ev.Replay) <= epc {
+			ev.Replay = append(ev.Replay, nil)
+		}
+		ev.Replay[epc] = append(ev.Replay[epc], int(rc.FloatVal1D(i)))
+	}
+}
+
+// SaveOrderHist saves the presentation order history of the TrainEnv to
+// given (tab-separated) file
+func (ss *Sim) SaveOrderHist(fname string) error {
+	return SaveTable(ss.TrainEnv.HistTable(), fname)
+}
+
+// OpenReplayFile sets the TrainEnv to replay the order history in
+// ReplayFile, if set, else the YokeOrder (nil = not to replay) -- called
+// in ConfigEnvs
+func (ss *Sim) OpenReplayFile() error {
+	ss.TrainEnv.Replay = ss.YokeOrder
+	if ss.ReplayFile == "" {
+		return nil
+	}
+	dt := &etable.Table{}
+	if err := OpenTable(dt, ss.ReplayFile); err != nil {
+		return err
+	}
+	ss.TrainEnv.SetReplay(dt)
+	return nil
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// report.go has the run report written into the run bundle by
+// ExportRunBundle, for sharing the results of a run: report.md (Markdown,
+// referring to the plot files of the bundle) and report.html (a single
+// self-contained page, with the plots inlined as SVG).  It has the summary
+// stats of the run, the learning curves (the epoch plot and BatchPlots), the
+// Motor confusion matrix and Outcome RSA heatmap of the last TestAll, and
+// the table of the Params in use.
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/emer/etable/etensor"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+)
+
+// Report is the content of a run report
+type Report struct {
+	Title   string       `desc:"title of the report"`
+	Summary [][2]string  `desc:"summary stats, as name, value"`
+	Plots   []BundleFile `desc:"the .svg plot files, in the bundle directory, with their captions"`
+	Params  [][3]string  `desc:"the Params, as Sel, Param, Value"`
+	Files   []BundleFile `desc:"the other files of the bundle"`
+}
+
+// RSAPlot returns the heatmap of the Euclidean distances between the
+// OutReps of the last TestAll -- nil if there are none
+func (ss *Sim) RSAPlot() *plot.Plot {
+	n := len(ss.OutReps)
+	if n == 0 {
+		return nil
+	}
+	sm := SimMat(ss.OutReps)
+	tsr := etensor.NewFloat64([]int{n, n}, nil, []string{"Item", "Item"})
+	for i := range sm {
+		for j := range sm[i] {
+			tsr.Set([]int{i, j}, sm[i][j])
+		}
+	}
+	return TensorGridPlot(tsr, "Outcome ActM RSA (distance, last TestAll)", "Item", "Item")
+}
+
+// ReportSummary returns the summary stats of the run for the Report
+func (ss *Sim) ReportSummary() [][2]string {
+	f := func(v float32) string { return fmt.Sprintf("%.4g", v) }
+	sm := [][2]string{
+		{"Experiment", ss.Expt},
+		{"NetVariant", ss.NetVariant.String()},
+		{"RndSeed", fmt.Sprintf("%d", ss.RndSeed)},
+		{"PatsHash", ss.PatsHash},
+		{"Epochs", fmt.Sprintf("%d", ss.Epoch)},
+		{"FirstZero", fmt.Sprintf("%d", ss.FirstZero)},
+		{"LastZero", fmt.Sprintf("%d", ss.LastZero)},
+		{"NZero", fmt.Sprintf("%d", ss.NZero)},
+		{"OutGoalPctErr", f(ss.EpcOutGoalPctErr)},
+		{"OutPredPctErr", f(ss.EpcOutPredPctErr)},
+		{"OutClassPctCor", f(ss.EpcOutClassPctCor)},
+		{"GoalClassPctCor", f(ss.EpcGoalClassPctCor)},
+		{"MotCosDiff", f(ss.EpcMotCosDiff)},
+		{"OutCosDiff", f(ss.EpcOutCosDiff)},
+		{"TstOutGoalPctErr", f(ss.TstOutGoalPctErr)},
+		{"TstOutPredPctErr", f(ss.TstOutPredPctErr)},
+		{"TstOutClassPctCor", f(ss.TstOutClassPctCor)},
+		{"TstGoalClassPctCor", f(ss.TstGoalClassPctCor)},
+	}
+	if ss.Halt != "" {
+		sm = append(sm, [2]string{"Halt", ss.Halt})
+	}
+	if ss.StopSignal != nil {
+		sm = append(sm, [2]string{"Stopped", ss.StopSignal.String()})
+	}
+	return sm
+}
+
+// ReportParams returns the Params in use as Sel, Param, Value rows, with
+// the params of each selector in name order
+func (ss *Sim) ReportParams() [][3]string {
+	var ps [][3]string
+	for _, psel := range ss.Params {
+		nms := make([]string, 0, len(psel.Params))
+		for nm := range psel.Params {
+			nms = append(nms, nm)
+		}
+		sort.Strings(nms)
+		for _, nm := range nms {
+			ps = append(ps, [3]string{psel.Sel, nm, fmt.Sprintf("%g", psel.Params[nm])})
+		}
+	}
+	return ps
+}
+
+// SaveReportPlots saves the confusion matrix and RSA plots of the report in
+// given directory, returning the files saved -- none for those with no data
+func (ss *Sim) SaveReportPlots(dir string) ([]BundleFile, error) {
+	var fs []BundleFile
+	for _, rp := range []struct {
+		fn, desc string
+		plt      *plot.Plot
+	}{
+		{"conf_mat.svg", "Motor confusion matrix of the last TestAll", ss.ConfMatPlot()},
+		{"rsa.svg", "distances between the Outcome ActM of the last TestAll items", ss.RSAPlot()},
+	} {
+		if rp.plt == nil {
+			continue
+		}
+		if err := rp.plt.Save(5*vg.Inch, 5*vg.Inch, filepath.Join(dir, rp.fn)); err != nil {
+			return fs, err
+		}
+		fs = append(fs, BundleFile{rp.fn, rp.desc})
+	}
+	return fs, nil
+}
+
+// Markdown returns the report in Markdown, with the plots as image links
+// relative to the bundle directory
+func (rp *Report) Markdown() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", rp.Title)
+	b.WriteString("## Summary\n\n| Stat | Value |\n|---|---|\n")
+	for _, s := range rp.Summary {
+		fmt.Fprintf(&b, "| %s | %s |\n", s[0], s[1])
+	}
+	b.WriteString("\n## Plots\n\n")
+	for _, p := range rp.Plots {
+		fmt.Fprintf(&b, "![%s](%s)\n\n*%s*\n\n", p.Name, p.Name, p.Desc)
+	}
+	b.WriteString("## Params\n\n| Sel | Param | Value |\n|---|---|---|\n")
+	for _, p := range rp.Params {
+		fmt.Fprintf(&b, "| `%s` | %s | %s |\n", p[0], p[1], p[2])
+	}
+	b.WriteString("\n## Files\n\n")
+	for _, f := range rp.Files {
+		fmt.Fprintf(&b, "- [%s](%s): %s\n", f.Name, f.Name, f.Desc)
+	}
+	return b.String()
+}
+
+// HTML returns the report as a self-contained HTML page, with the plots of
+// given bundle directory inlined as SVG
+func (rp *Report) HTML(dir string) string {
+	var b bytes.Buffer
+	e := html.EscapeString
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", e(rp.Title))
+	b.WriteString("<style>body { font-family: sans-serif; margin: 2em; } table { border-collapse: collapse; } td, th { border: 1px solid #ccc; padding: 2px 8px; text-align: left; } figure { display: inline-block; margin: 1em; } svg { width: 400px; height: 400px; }</style>\n</head>\n<body>\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", e(rp.Title))
+	b.WriteString("<h2>Summary</h2>\n<table>\n<tr><th>Stat</th><th>Value</th></tr>\n")
+	for _, s := range rp.Summary {
+		fmt.Fprintf(&b, "<tr><td>%s</td><td>%s</td></tr>\n", e(s[0]), e(s[1]))
+	}
+	b.WriteString("</table>\n<h2>Plots</h2>\n")
+	for _, p := range rp.Plots {
+		b.WriteString("<figure>\n")
+		sb, err := ioutil.ReadFile(filepath.Join(dir, p.Name))
+		if err != nil {
+			fmt.Fprintf(&b, "<img src=\"%s\" alt=\"%s\">\n", e(p.Name), e(p.Name))
+		} else {
+			sv := string(sb)
+			if i := strings.Index(sv, "<svg"); i > 0 { // drop the xml prolog
+				sv = sv[i:]
+			}
+			b.WriteString(sv)
+		}
+		fmt.Fprintf(&b, "\n<figcaption>%s: %s</figcaption>\n</figure>\n", e(p.Name), e(p.Desc))
+	}
+	b.WriteString("<h2>Params</h2>\n<table>\n<tr><th>Sel</th><th>Param</th><th>Value</th></tr>\n")
+	for _, p := range rp.Params {
+		fmt.Fprintf(&b, "<tr><td><code>%s</code></td><td>%s</td><td>%s</td></tr>\n", e(p[0]), e(p[1]), e(p[2]))
+	}
+	b.WriteString("</table>\n<h2>Files</h2>\n<ul>\n")
+	for _, f := range rp.Files {
+		fmt.Fprintf(&b, "<li><a href=\"%s\">%s</a>: %s</li>\n", e(f.Name), e(f.Name), e(f.Desc))
+	}
+	b.WriteString("</ul>\n</body>\n</html>\n")
+	return b.String()
+}
+
+// SaveReport writes the run report into given run bundle directory, as
+// report.md and report.html, with the report plots, given the files already
+// in the bundle -- returns the files it saved
+func (ss *Sim) SaveReport(dir string, files []BundleFile) ([]BundleFile, error) {
+	pfs, err := ss.SaveReportPlots(dir)
+	if err != nil {
+		return pfs, err
+	}
+	rp := &Report{Summary: ss.ReportSummary(), Params: ss.ReportParams()}
+	rp.Title = fmt.Sprintf("Goal Guy run report: %s", time.Now().Format("2006-01-02 15:04"))
+	if ss.Expt != "" {
+		rp.Title += " (" + ss.Expt + ")"
+	}
+	for _, f := range append(files, pfs...) {
+		if filepath.Ext(f.Name) == ".svg" {
+			rp.Plots = append(rp.Plots, f)
+		} else {
+			rp.Files = append(rp.Files, f)
+		}
+	}
+	fs := pfs
+	if err := ioutil.WriteFile(filepath.Join(dir, "report.md"), []byte(rp.Markdown()), 0644); err != nil {
+		return fs, err
+	}
+	fs = append(fs, BundleFile{"report.md", "run report, in Markdown"})
+	if err := ioutil.WriteFile(filepath.Join(dir, "report.html"), []byte(rp.HTML(dir)), 0644); err != nil {
+		return fs, err
+	}
+	fs = append(fs, BundleFile{"report.html", "run report, as a self-contained HTML page"})
+	return fs, nil
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// Reversal learning (RevProtocol): after an acquisition phase with the
+// closed-loop Contings environment, the action -> outcome mappings of each
+// pair of actions in RevPairs are swapped, and the number of trials until
+// outcome prediction performance recovers is recorded.  Performance is the
+// proportion of correctly predicted outcomes (all Outcome units on the
+// right side of .5 in the minus phase) over the last RevWindow trials, and
+// it has recovered when it reaches RevCrit times its level at the swap.
+// Each run adds a row to RevLog.
+
+// SwapContings swaps the outcomes and probabilities of the Contings for the
+// two given actions
+func (ss *Sim) SwapContings(a, b int) {
+	ra := ss.ContingRow(a)
+	rb := ss.ContingRow(b)
+	if ra < 0 || rb < 0 {
+		return
+	}
+	ct := ss.Contings
+	for _, cnm := range []string{"P", "Out1", "Out2"} {
+		cl := ct.ColByName(cnm)
+		_, cells := cl.RowCellSize()
+		for i := 0; i < cells; i++ {
+			va := cl.FloatVal1D(ra*cells + i)
+			cl.SetFloat1D(ra*cells+i, cl.FloatVal1D(rb*cells+i))
+			cl.SetFloat1D(rb*cells+i, va)
+		}
+	}
+}
+
+// RevSwap swaps the Contings of all RevPairs, and starts tracking the
+// recovery of performance -- adds a new row to RevLog
+func (ss *Sim) RevSwap() {
+	for _, pr := range ss.RevPairs {
+		ss.SwapContings(pr[0], pr[1])
+	}
+	pre := ss.RevPerf()
+	ss.RevTracking = true
+	ss.RevTrials = 0
+	ss.RevTarg = ss.RevCrit * pre
+
+	dt := ss.RevLog
+	row := dt.NumRows()
+	dt.SetNumRows(row + 1)
+	dt.ColByName("Run").SetFloat1D(row, float64(row))
+	dt.ColByName("SwapEpoch").SetFloat1D(row, float64(ss.Epoch))
+	dt.ColByName("PrePerf").SetFloat1D(row, float64(pre))
+	dt.ColByName("TrialsToRecover").SetFloat1D(row, -1)
+}
+
+// RevPerf returns the proportion of correctly predicted outcomes over the
+// last RevWindow trials
+func (ss *Sim) RevPerf() float32 {
+	if len(ss.RevWin) == 0 {
+		return 0
+	}
+	cor := 0
+	for _, c := range ss.RevWin {
+		if c {
+			cor++
+		}
+	}
+	return float32(cor) / float32(len(ss.RevWin))
+}
+
+// RevTrialStats records whether the minus phase Outcome activity correctly
+// predicted the outcome for this trial, and checks for recovery if tracking
+// -- called in ContingPlusPhase if training
+func (ss *Sim) RevTrialStats(out *etensor.Float32) {
+	outcomeLay := ss.Net.LayerByName("Outcome").(*leabra.Layer)
+	acts, _ := outcomeLay.UnitVals("ActM")
+	cor := true
+	for i, a := range acts {
+		if i >= len(out.Values) {
+			break
+		}
+		if (a > 0.5) != (out.Values[i] > 0.5) {
+			cor = false
+			break
+		}
+	}
+	if ss.RevWindow < 1 {
+		ss.RevWindow = 1
+	}
+	ss.RevWin = append(ss.RevWin, cor)
+	if len(ss.RevWin) > ss.RevWindow {
+		ss.RevWin = ss.RevWin[len(ss.RevWin)-ss.RevWindow:]
+	}
+	if !ss.RevTracking {
+		return
+	}
+	ss.RevTrials++
+	if ss.RevTrials >= ss.RevWindow && ss.RevPerf() >= ss.RevTarg {
+		ss.RevTracking = false
+		ss.RevLog.ColByName("TrialsToRecover").SetFloat1D(ss.RevLog.NumRows()-1, float64(ss.RevTrials))
+	}
+}
+
+// RevProtocol is the reversal-learning protocol: an acquisition phase of
+// AcqEpcs epochs with the closed-loop Contings environment, followed by a
+// reversal phase of RevEpcs epochs, at the start of which the Contings of
+// the RevPairs are swapped
+func RevProtocol(ss *Sim) []*Phase {
+	return []*Phase{
+		{Name: "acquisition", Epochs: ss.AcqEpcs, Start: func(ss *Sim) {
+			ss.ContingOn = true
+			ss.Degraded = false
+			ss.Extinct = false
+		}},
+		{Name: "reversal", Epochs: ss.RevEpcs, Start: func(ss *Sim) {
+			ss.RevSwap()
+		}},
+	}
+}
+
+// ConfigRevLog configures the RevLog table
+func (ss *Sim) ConfigRevLog() {
+	ss.RevLog.SetFromSchema(etable.Schema{
+		{"Run", etensor.INT64, nil, nil},
+		{"SwapEpoch", etensor.INT64, nil, nil},
+		{"PrePerf", etensor.FLOAT32, nil, nil},
+		{"TrialsToRecover", etensor.INT64, nil, nil},
+	}, 0)
+}
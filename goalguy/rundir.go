@@ -0,0 +1,77 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// rundir.go has the management of per-run directories for batch jobs of
+// many runs (e.g., -sweep): each run gets its own subdirectory of the
+// RunDirs Root, to which its EpcLog is streamed (see epclog.go), so that
+// only the last RunEpcLogMax epochs of each run are kept in memory, and the
+// summary of the run is kept by the caller.  A run subdirectory left by a
+// previous batch job is rotated to <name>.1, <name>.2, .. up to Keep
+// versions, rather than overwritten.
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RunEpcLogMax is the EpcLogMax of the runs in a RunDirs: the number of
+// epochs kept in memory
+const RunEpcLogMax = 20
+
+// RunDirs manages the per-run subdirectories of a batch job
+type RunDirs struct {
+	Root string `desc:"directory the run subdirectories are created in"`
+	Keep int    `desc:"number of previous versions of each run subdirectory kept when it is re-created, as <name>.1 (the most recent) .. <name>.<Keep> -- older ones are removed"`
+}
+
+// Path returns the path of the run subdirectory of given name
+func (rd *RunDirs) Path(name string) string {
+	return filepath.Join(rd.Root, name)
+}
+
+// Create creates the run subdirectory of given name, rotating any existing
+// one, and returns its path
+func (rd *RunDirs) Create(name string) (string, error) {
+	dir := rd.Path(name)
+	if _, err := os.Stat(dir); err == nil {
+		if err := rd.Rotate(name); err != nil {
+			return dir, err
+		}
+	}
+	return dir, os.MkdirAll(dir, 0755)
+}
+
+// Rotate renames the run subdirectory of given name to <name>.1, after
+// shifting the previous versions up by one, and removing those beyond Keep
+// (or the subdirectory itself if Keep is 0)
+func (rd *RunDirs) Rotate(name string) error {
+	dir := rd.Path(name)
+	if rd.Keep <= 0 {
+		return os.RemoveAll(dir)
+	}
+	if err := os.RemoveAll(fmt.Sprintf("%s.%d", dir, rd.Keep)); err != nil {
+		return err
+	}
+	for i := rd.Keep - 1; i >= 1; i-- {
+		old := fmt.Sprintf("%s.%d", dir, i)
+		if _, err := os.Stat(old); err != nil {
+			continue
+		}
+		if err := os.Rename(old, fmt.Sprintf("%s.%d", dir, i+1)); err != nil {
+			return err
+		}
+	}
+	return os.Rename(dir, dir+".1")
+}
+
+// UseRunDir streams the EpcLog to the epc_log.tsv file of given run
+// directory, keeping only the last RunEpcLogMax epochs in memory -- call
+// before Init
+func (ss *Sim) UseRunDir(dir string) {
+	ss.EpcLogFile = filepath.Join(dir, "epc_log.tsv")
+	ss.EpcLogMax = RunEpcLogMax
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// runlog.go has the standard emergent-style learning speed measures, for
+// comparing param configurations quantitatively:
+//
+//	FirstZero  first epoch where OutGoalPctErr == 0
+//	LastZero   first epoch of the final unbroken stretch of zero-error
+//	           epochs -- i.e., when the task was learned for good
+//	NZero      number of consecutive zero-error epochs up to the last one
+//
+// These are updated in LogEpoch, and a summary row is added to the RunLog
+// at the end of each Train run.  If NZeroStop > 0, training stops once
+// NZero reaches it.
+
+import (
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// ResetZeroStats resets the FirstZero, LastZero and NZero stats -- called in Init
+func (ss *Sim) ResetZeroStats() {
+	ss.FirstZero = -1
+	ss.LastZero = -1
+	ss.NZero = 0
+}
+
+// ZeroStats updates FirstZero, LastZero and NZero from this epoch's
+// OutGoalPctErr -- called in LogEpoch
+func (ss *Sim) ZeroStats() {
+	if ss.EpcOutGoalPctErr != 0 {
+		ss.NZero = 0
+		ss.LastZero = -1
+		return
+	}
+	if ss.FirstZero < 0 {
+		ss.FirstZero = ss.Epoch
+	}
+	if ss.NZero == 0 {
+		ss.LastZero = ss.Epoch
+	}
+	ss.NZero++
+}
+
+// ConfigRunLog sets up the RunLog table
+func (ss *Sim) ConfigRunLog() {
+	dt := ss.RunLog
+	dt.SetFromSchema(etable.Schema{
+		{"Expt", etensor.STRING, nil, nil},
+		{"RndSeed", etensor.INT64, nil, nil},
+		{"Epochs", etensor.INT64, nil, nil},
+		{"FirstZero", etensor.INT64, nil, nil},
+		{"LastZero", etensor.INT64, nil, nil},
+		{"NZero", etensor.INT64, nil, nil},
+		{"OutGoalPctErr", etensor.FLOAT32, nil, nil},
+		{"OutSSE", etensor.FLOAT32, nil, nil},
+		{"MotSSE", etensor.FLOAT32, nil, nil},
+		{"Halt", etensor.STRING, nil, nil},
+	}, 0)
+}
+
+// LogRun adds a summary row for the run so far to the RunLog -- called by
+// TrainFinish at the end of Train or TrainNEpochs
+func (ss *Sim) LogRun() {
+	dt := ss.RunLog
+	row := dt.NumRows()
+	dt.SetNumRows(row + 1)
+	dt.ColByName("Expt").SetString1D(row, ss.Expt)
+	dt.ColByName("RndSeed").SetFloat1D(row, float64(ss.RndSeed))
+	dt.ColByName("Epochs").SetFloat1D(row, float64(ss.Epoch))
+	dt.ColByName("FirstZero").SetFloat1D(row, float64(ss.FirstZero))
+	dt.ColByName("LastZero").SetFloat1D(row, float64(ss.LastZero))
+	dt.ColByName("NZero").SetFloat1D(row, float64(ss.NZero))
+	dt.ColByName("OutGoalPctErr").SetFloat1D(row, float64(ss.EpcOutGoalPctErr))
+	dt.ColByName("OutSSE").SetFloat1D(row, float64(ss.EpcOutSSE))
+	dt.ColByName("MotSSE").SetFloat1D(row, float64(ss.EpcMotSSE))
+	dt.ColByName("Halt").SetString1D(row, ss.Halt)
+	ss.DBLogRun()
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// scaletune.go has the Scales tab, with a spin box for the WtScale.Rel of
+// all the Back projections together, and one for each of the ScaleTunePaths
+// projections.  Changes are set directly on the projections of the current
+// network, without re-initializing the weights, and take effect at the
+// start of the next alpha cycle, so the influence of top-down input can be
+// explored on a trained network.  Init and Apply Params restore the values
+// of the Params (Apply Params also updates the spin boxes) -- Refresh shows
+// the current values of the network.
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/leabra/leabra"
+	"github.com/goki/gi/gi"
+	"github.com/goki/ki/ki"
+)
+
+// ScaleTuneBack is the name of the Scales tab entry for all Back projections
+const ScaleTuneBack = "Back"
+
+// ScaleTuneMax is the maximum WtScale.Rel settable in the Scales tab
+var ScaleTuneMax = float32(5)
+
+// BackPrjns returns all the Back projections in the network
+func (ss *Sim) BackPrjns() []*leabra.Prjn {
+	var pjs []*leabra.Prjn
+	for _, pj := range ss.AllPrjns() {
+		if pj.Type() == emer.Back {
+			pjs = append(pjs, pj)
+		}
+	}
+	return pjs
+}
+
+// ScalePrjns returns the projections set by given Scales tab entry:
+// ScaleTuneBack or a Send:Recv projection path
+func (ss *Sim) ScalePrjns(nm string) ([]*leabra.Prjn, error) {
+	if nm == ScaleTuneBack {
+		return ss.BackPrjns(), nil
+	}
+	pj, err := ss.PrjnByPath(nm)
+	if err != nil {
+		return nil, err
+	}
+	return []*leabra.Prjn{pj}, nil
+}
+
+// ScaleRel returns the current WtScale.Rel of given Scales tab entry (of the
+// first projection for ScaleTuneBack), and false if it has no projections
+func (ss *Sim) ScaleRel(nm string) (float32, bool) {
+	pjs, err := ss.ScalePrjns(nm)
+	if err != nil || len(pjs) == 0 {
+		return 0, false
+	}
+	return pjs[0].WtScale.Rel, true
+}
+
+// SetScaleRel sets the WtScale.Rel of the projections of given Scales tab
+// entry -- takes effect at the start of the next alpha cycle
+func (ss *Sim) SetScaleRel(nm string, rel float32) error {
+	pjs, err := ss.ScalePrjns(nm)
+	if err != nil {
+		return err
+	}
+	if len(pjs) == 0 {
+		return fmt.Errorf("SetScaleRel: no %s projections in the network", nm)
+	}
+	for _, pj := range pjs {
+		pj.WtScale.Rel = rel
+	}
+	return nil
+}
+
+// ScaleTabUpdt sets the Scales tab spin boxes to the current values of the
+// network
+func (ss *Sim) ScaleTabUpdt() {
+	for nm, sb := range ss.ScaleSpins {
+		if rel, ok := ss.ScaleRel(nm); ok {
+			sb.SetValue(rel)
+		}
+	}
+}
+
+// ConfigScaleTab adds the Scales tab to given tab view
+func (ss *Sim) ConfigScaleTab(tv *gi.TabView, vp *gi.Viewport2D) {
+	fr := tv.AddNewTab(gi.KiT_Frame, "Scales").(*gi.Frame)
+	fr.Lay = gi.LayoutVert
+
+	gi.AddNewLabel(fr, "msg", "Projection WtScale.Rel, applied from the next alpha cycle without re-initializing the weights:")
+	ss.ScaleSpins = map[string]*gi.SpinBox{}
+	nms := append([]string{ScaleTuneBack}, ss.ScaleTunePaths...)
+	for _, nm := range nms {
+		rel, ok := ss.ScaleRel(nm)
+		if !ok {
+			continue // e.g., Outcome:Motor without HasOutMotBack
+		}
+		nm := nm
+		row := gi.AddNewFrame(fr, "row-"+nm, gi.LayoutHoriz)
+		gi.AddNewLabel(row, "lbl", nm)
+		sb := gi.AddNewSpinBox(row, "rel")
+		sb.SetMin(0)
+		sb.SetMax(ScaleTuneMax)
+		sb.SetStep(0.05)
+		sb.SetValue(rel)
+		sb.SpinBoxSig.Connect(fr.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			if err := ss.SetScaleRel(nm, sb.Value); err != nil {
+				log.Println(err)
+			}
+		})
+		ss.ScaleSpins[nm] = sb
+	}
+
+	bt := gi.AddNewButton(fr, "refresh")
+	bt.SetText("Refresh")
+	bt.ButtonSig.Connect(fr.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonClicked) {
+			ss.ScaleTabUpdt()
+			vp.FullRender2DTree()
+		}
+	})
+}
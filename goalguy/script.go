@@ -0,0 +1,212 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// script.go runs experiment scripts, so complex protocols (e.g., train ->
+// devalue -> test) can be run reproducibly in batch mode (see -script).
+// A script file has one operation per line, as whitespace-separated:
+//
+//	expt <name>                  select the experiment preset and re-Config
+//	init                         Init: new weights, logs and item order
+//	train <n>                    train n epochs
+//	train                        train to criterion (MaxEpcs / NZeroStop)
+//	set <Field> <value>          set a Sim field, e.g., set DevalItem 3
+//	param <Sel> <Param> <value>  set a param and apply the Params
+//	lesion <Layer>               turn a layer off
+//	unlesion <Layer>             turn it back on
+//	devalue                      run the devaluation protocol (RunDeval)
+//	protocol <name>              run a multi-phase protocol (Protocols)
+//	test                         TestAll on the TestEnv
+//	validate                     Validate
+//	savewts <file>               save the weights
+//	save <dir>                   export the run bundle to dir
+//
+// Blank lines and lines starting with # followed by a space are skipped,
+// as in sweep files.  The operations are run in order by a Runner, which
+// stops at the first error, or if the Sim is stopped.
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/leabra/leabra"
+	"github.com/goki/gi/gi"
+)
+
+// ScriptOp is one operation of an experiment script
+type ScriptOp struct {
+	Op   string   `desc:"name of the operation, e.g., train"`
+	Args []string `desc:"args of the operation"`
+	Line int      `desc:"line of the script file the operation is on"`
+}
+
+// String returns the operation as in the script file
+func (so *ScriptOp) String() string {
+	return strings.Join(append([]string{so.Op}, so.Args...), " ")
+}
+
+// ScriptNArgs has the number of args of each script operation -- -1 for
+// train, which takes 0 or 1
+var ScriptNArgs = map[string]int{
+	"expt":     1,
+	"init":     0,
+	"train":    -1,
+	"set":      2,
+	"param":    3,
+	"lesion":   1,
+	"unlesion": 1,
+	"devalue":  0,
+	"protocol": 1,
+	"test":     0,
+	"validate": 0,
+	"savewts":  1,
+	"save":     1,
+}
+
+// OpenScript reads the experiment script from given file, checking the
+// operations and their number of args
+func OpenScript(fname string) ([]ScriptOp, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var ops []ScriptOp
+	scan := bufio.NewScanner(f)
+	ln := 0
+	for scan.Scan() {
+		ln++
+		line := strings.TrimSpace(scan.Text())
+		if line == "" || strings.HasPrefix(line, "# ") {
+			continue
+		}
+		fs := strings.Fields(line)
+		so := ScriptOp{Op: strings.ToLower(fs[0]), Args: fs[1:], Line: ln}
+		na, ok := ScriptNArgs[so.Op]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("%s:%d: unknown operation: %s", fname, ln, fs[0])
+		case na < 0 && len(so.Args) > 1, na >= 0 && len(so.Args) != na:
+			return nil, fmt.Errorf("%s:%d: wrong number of args for %s: %d", fname, ln, so.Op, len(so.Args))
+		}
+		ops = append(ops, so)
+	}
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("%s: no operations", fname)
+	}
+	return ops, nil
+}
+
+// Runner runs the operations of an experiment script on a Sim, in order
+type Runner struct {
+	Sim *Sim       `desc:"the Sim to run the operations on"`
+	Ops []ScriptOp `desc:"the operations of the script"`
+	Cur int        `desc:"index of the current operation"`
+}
+
+// Run runs all the operations, printing each one as it starts, and
+// returns the error of the first one that fails, if any
+func (rn *Runner) Run() error {
+	ss := rn.Sim
+	for rn.Cur = 0; rn.Cur < len(rn.Ops); rn.Cur++ {
+		so := &rn.Ops[rn.Cur]
+		fmt.Printf("script %d: %s\n", so.Line, so.String())
+		if err := ss.RunScriptOp(so); err != nil {
+			return fmt.Errorf("line %d: %s: %v", so.Line, so.Op, err)
+		}
+		if ss.StopSignal != nil {
+			return nil
+		}
+	}
+	return nil
+}
+
+// RunScriptOp runs given script operation
+func (ss *Sim) RunScriptOp(so *ScriptOp) error {
+	switch so.Op {
+	case "expt":
+		if err := ss.SetExpt(so.Args[0]); err != nil {
+			return err
+		}
+		ss.ReConfig()
+	case "init":
+		ss.Init()
+	case "train":
+		if len(so.Args) == 0 {
+			ss.Train()
+			return nil
+		}
+		n, err := strconv.Atoi(so.Args[0])
+		if err != nil {
+			return err
+		}
+		ss.TrainNEpochs(n)
+	case "set":
+		f := reflect.ValueOf(ss).Elem().FieldByName(so.Args[0])
+		if !f.IsValid() || !f.CanSet() {
+			return fmt.Errorf("no settable Sim field named: %s", so.Args[0])
+		}
+		v, err := ParseVal(so.Args[1], f.Type())
+		if err != nil {
+			return err
+		}
+		f.Set(v)
+	case "param":
+		v, err := strconv.ParseFloat(so.Args[2], 32)
+		if err != nil {
+			return err
+		}
+		ss.Params = SetParamVal(CopyParams(ss.Params), so.Args[0], so.Args[1], float32(v))
+		ss.ApplyParams(false)
+	case "lesion":
+		return ss.Lesion(so.Args[0], true)
+	case "unlesion":
+		return ss.Lesion(so.Args[0], false)
+	case "devalue":
+		ss.RunDeval()
+	case "protocol":
+		return ss.RunProtocol(so.Args[0])
+	case "test":
+		ss.TestAll(&ss.TestEnv)
+	case "validate":
+		ss.Validate()
+	case "savewts":
+		return ss.SaveWts(gi.FileName(so.Args[0]))
+	case "save":
+		return ss.ExportRunBundle(so.Args[0])
+	}
+	return nil
+}
+
+// Lesion turns the layer of given name off (or back on if !off), so it
+// does not participate in processing or learning
+func (ss *Sim) Lesion(lnm string, off bool) error {
+	ly, ok := ss.Net.LayerByName(lnm).(*leabra.Layer)
+	if !ok || ly == nil {
+		return fmt.Errorf("no layer named: %s", lnm)
+	}
+	ly.SetOff(off)
+	return nil
+}
+
+// SetParamVal sets given param of the ParamSel of given Sel in ps to
+// given value, adding a new ParamSel if there is none, and returns ps
+func SetParamVal(ps emer.ParamStyle, sel, param string, val float32) emer.ParamStyle {
+	for _, psel := range ps {
+		if psel.Sel == sel {
+			psel.Params[param] = val
+			return ps
+		}
+	}
+	return append(ps, emer.ParamSel{Sel: sel, Params: emer.Params{param: val}})
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"math/rand"
+
+	"github.com/chewxy/math32"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// Action sequences (SeqSteps > 1): reaching the Outcome of a trial requires
+// a sequence of SeqSteps Motor outputs.  Each step is a full two-AlphaCycle
+// pass (see TrainTrial), with the current environment state clamped on
+// Context and the trial's Outcome as the goal.  After each step the
+// environment moves to a new Context state as a function of the Motor
+// output produced, and the sequence succeeds if the final state matches
+// the goal.  The environment is a simple deterministic world where Motor
+// action a (the most active Motor unit) circularly shifts the Context
+// pattern by a+1 units.
+
+// SeqOn returns whether action sequences are in effect
+func (ss *Sim) SeqOn() bool {
+	return ss.SeqSteps > 1
+}
+
+// NSeqSteps returns the number of Motor steps per trial (at least 1)
+func (ss *Sim) NSeqSteps() int {
+	if ss.SeqSteps < 1 {
+		return 1
+	}
+	return ss.SeqSteps
+}
+
+// SeqEnvNext returns the environment state after taking Motor action act
+// in state ctxt
+func SeqEnvNext(ctxt *etensor.Float32, act int) *etensor.Float32 {
+	nxt := etensor.NewFloat32(ctxt.Shapes(), nil, []string{"Y", "X"})
+	n := len(ctxt.Values)
+	if act < 0 || n == 0 {
+		copy(nxt.Values, ctxt.Values)
+		return nxt
+	}
+	sh := (act + 1) % n
+	for i, v := range ctxt.Values {
+		nxt.Values[(i+sh)%n] = v
+	}
+	return nxt
+}
+
+// ConfigSeq sets the Outcome of each row of given table to the final state
+// reached from its Context by a random sequence of SeqSteps Motor actions,
+// so that every goal is reachable -- called in Config if SeqOn
+func (ss *Sim) ConfigSeq(et *etable.Table) {
+	if !ss.SeqOn() || et.NumRows() == 0 {
+		return
+	}
+	ctxts := et.ColByName("Context").(*etensor.Float32)
+	outs := et.ColByName("Outcome").(*etensor.Float32)
+	_, cells := outs.RowCellSize()
+	for row := 0; row < et.NumRows(); row++ {
+		c, _ := ctxts.SubSpace(2, []int{row})
+		st := c.(*etensor.Float32)
+		for s := 0; s < ss.SeqSteps; s++ {
+			st = SeqEnvNext(st, rand.Intn(cells))
+		}
+		for i, v := range st.Values {
+			outs.SetFloat1D(row*cells+i, float64(v))
+		}
+	}
+}
+
+// SeqInit starts a new sequence at given row of given table, setting the
+// environment state to the row's Context and the goal to its Outcome
+func (ss *Sim) SeqInit(et *etable.Table, row int) {
+	c, _ := et.ColByName("Context").(*etensor.Float32).SubSpace(2, []int{row})
+	o := RowCell(et.ColByName("Outcome"), row)
+	oshp, onms := ss.OutShape()
+	ss.SeqCtxt = etensor.NewFloat32(c.Shapes(), nil, []string{"Y", "X"})
+	ss.SeqGoal = etensor.NewFloat32(oshp, nil, onms)
+	copy(ss.SeqCtxt.Values, c.(*etensor.Float32).Values)
+	copy(ss.SeqGoal.Values, o.(*etensor.Float32).Values)
+}
+
+// SeqAct records the Motor action for the current step, as the most active
+// Motor unit (or pool) in the plus phase -- called after the 1st AlphaCycle of a step
+func (ss *Sim) SeqAct() {
+	motorLay := ss.Net.LayerByName("Motor").(*leabra.Layer)
+	ss.SeqActIdx = ss.ActIdx(motorLay, "ActP")
+}
+
+// SeqEnvStep moves the environment to its next state given the Motor
+// action of the current step -- called at the end of each step
+func (ss *Sim) SeqEnvStep() {
+	if !ss.SeqOn() {
+		return
+	}
+	ss.SeqCtxt = SeqEnvNext(ss.SeqCtxt, ss.SeqActIdx)
+}
+
+// SeqSuccess returns whether the final environment state matches the goal,
+// with a per-unit tolerance of 0.5
+func (ss *Sim) SeqSuccess() bool {
+	for i, v := range ss.SeqCtxt.Values {
+		if i >= len(ss.SeqGoal.Values) {
+			break
+		}
+		if math32.Abs(v-ss.SeqGoal.Values[i]) >= 0.5 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// session.go saves the GUI session layout -- window size, split
+// proportions, selected tab and plot settings -- to a per-user JSON
+// settings file (SessionFile) when the main window is closed, and restores
+// it in ConfigGui, so the workspace does not have to be re-arranged at
+// every launch.  The plot style is only restored if no PlotStyleFile is set.
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goki/gi/gi"
+)
+
+// GuiSession is the saved GUI session layout
+type GuiSession struct {
+	Width     int       `desc:"window width, in actual pixels"`
+	Height    int       `desc:"window height, in actual pixels"`
+	Splits    []float32 `desc:"proportions of the control panel and tab view split"`
+	Tab       string    `desc:"name of the selected tab"`
+	Plot      bool      `desc:"whether the epoch plot is updated while running"`
+	PlotVals  []string  `desc:"values plotted in the epoch plot"`
+	PlotStyle PlotStyle `desc:"style of the plots"`
+}
+
+// DefaultSessionFile returns the per-user GUI session file: session.json
+// in the goal-guy-0 directory of the user config dir -- empty if there is
+// no such dir
+func DefaultSessionFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "goal-guy-0", "session.json")
+}
+
+// OpenSession returns the GuiSession saved in given file
+func OpenSession(fname string) (*GuiSession, error) {
+	b, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	gs := &GuiSession{}
+	if err := json.Unmarshal(b, gs); err != nil {
+		return nil, err
+	}
+	return gs, nil
+}
+
+// SaveSession saves given GuiSession to given file, creating its directory
+func SaveSession(gs *GuiSession, fname string) error {
+	if err := os.MkdirAll(filepath.Dir(fname), 0755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(gs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fname, b, 0644)
+}
+
+// OpenSessionFile returns the GuiSession saved in the SessionFile, and
+// restores its plot settings -- nil if there is none
+func (ss *Sim) OpenSessionFile() *GuiSession {
+	if ss.SessionFile == "" {
+		return nil
+	}
+	if _, err := os.Stat(ss.SessionFile); os.IsNotExist(err) {
+		return nil
+	}
+	gs, err := OpenSession(ss.SessionFile)
+	if err != nil {
+		return nil
+	}
+	ss.Plot = gs.Plot
+	if len(gs.PlotVals) > 0 {
+		ss.PlotVals = gs.PlotVals
+	}
+	if ss.PlotStyleFile == "" && gs.PlotStyle.Font != "" {
+		CurPlotStyle = gs.PlotStyle
+	}
+	return gs
+}
+
+// SaveSessionFile saves the current GUI session layout of given window,
+// split and tab view to the SessionFile, if set
+func (ss *Sim) SaveSessionFile(win *gi.Window, split *gi.SplitView, tv *gi.TabView) error {
+	if ss.SessionFile == "" {
+		return nil
+	}
+	gs := &GuiSession{
+		Splits:    split.Splits,
+		Plot:      ss.Plot,
+		PlotVals:  ss.PlotVals,
+		PlotStyle: CurPlotStyle,
+	}
+	if win.OSWin != nil {
+		sz := win.OSWin.Size()
+		gs.Width, gs.Height = sz.X, sz.Y
+	}
+	if tab, _, ok := tv.CurTab(); ok {
+		gs.Tab = tab.Name()
+	}
+	return SaveSession(gs, ss.SessionFile)
+}
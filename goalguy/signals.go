@@ -0,0 +1,54 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// signals.go has the graceful stop of -nogui runs on SIGINT / SIGTERM (e.g.,
+// cluster preemption): the signal is treated as Stop, so the current trial
+// is finished, and RunPipeline then saves the run bundle as a checkpoint
+// (with the EpcLog file flushed, the weights and the order history), so the
+// run is not lost.  A second signal terminates immediately.
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// CatchStopSignals makes the next SIGINT or SIGTERM Stop the sim, recording
+// it as the StopSignal
+func (ss *Sim) CatchStopSignals() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-sigs
+		signal.Stop(sigs) // a second signal terminates immediately
+		log.Printf("received %v: stopping after the current trial\n", sig)
+		ss.StopSignal = sig
+		ss.Stop()
+	}()
+}
+
+// SaveCheckpoint flushes the EpcLogFile and exports the run bundle in its
+// current state into given directory -- called by RunPipeline when stopped
+// by a StopSignal
+func (ss *Sim) SaveCheckpoint(dir string) error {
+	ss.CloseEpcLogFile()
+	return ss.ExportRunBundle(dir)
+}
+
+// StatusSummary returns a one-line summary of the state of the run
+func (ss *Sim) StatusSummary() string {
+	st := "completed"
+	switch {
+	case ss.StopSignal != nil:
+		st = fmt.Sprintf("stopped by %v", ss.StopSignal)
+	case ss.Halt != "":
+		st = "halted (" + ss.Halt + ")"
+	}
+	return fmt.Sprintf("run %s at epoch %d of %d: FirstZero: %d  NZero: %d  OutGoalPctErr: %g  OutPredPctErr: %g",
+		st, ss.Epoch, ss.MaxEpcs, ss.FirstZero, ss.NZero, ss.EpcOutGoalPctErr, ss.EpcOutPredPctErr)
+}
@@ -0,0 +1,2144 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package goalguy is a simple Phase 0 begin-with-success model to serve as a basis for learning motor and then instrumental actions
+// based on the key idea of striving toward desired arbitrary outcome states. Phase 0 uses all localist reps
+// and standard error driven learning.  Phase 0.5 will convert some reps to full distributed.
+// Phase 1 will move to DeepLeabra implementation.
+package goalguy
+
+import (
+	"database/sql"
+	"fmt"
+	"image"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/emergent/netview"
+	"github.com/emer/emergent/patgen"
+	"github.com/emer/emergent/prjn"
+	"github.com/emer/emergent/relpos"
+	"github.com/emer/emergent/timer"
+
+	"github.com/emer/etable/eplot"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+
+	"github.com/emer/leabra/leabra"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/svg"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// todo:
+//
+// * make etable/eplot2.Plot which encapsulates the SVGEditor and
+// shows its columns -- basically replicating behavior of C++
+// fGraphView for easier dynamic selection of what to plot, how to
+// plot it, etc.
+// Trick is how to make it slos customizable via code..
+//
+// * LogTrainTrial, LogTestTrial, LogTestCycle and assoc. plots
+//
+// * etable/eview.TableView (gridview -- is there a gonum version?)
+// and TableEdit (spreadsheet-like editor)
+// and show these in another tab for the input patterns
+//
+
+// DefaultParams are the initial default parameters for this simulation
+var DefaultParams = emer.ParamStyle{
+	{"Prjn", emer.Params{
+		"Prjn.Learn.Norm.On":     1,
+		"Prjn.Learn.Momentum.On": 1,
+		"Prjn.Learn.WtBal.On":    0,
+	}},
+	// TODO: below appears to be old, bad syntax
+	// "Layer": {
+	// 	//"Layer.Inhib.Layer.Gi": 1.8, // this is the default
+	// 	"Layer.Inhib.Layer.Gi": 2.8, // going for k = 1 so may need to be quite high...
+	// },
+
+	// should not need this guy - no formal Output layer
+	// {"#Output", emer.Params{
+	// 	"Layer.Inhib.Layer.Gi": 1.4, // this turns out to be critical for small output layer
+	// }},
+
+	{"#Motor", emer.Params{
+		"Layer.Inhib.Layer.Gi": 2.2, // aiming for k = 1 initially
+	}},
+	{"#Outcome", emer.Params{
+		"Layer.Inhib.Layer.Gi": 2.2, // aiming for k = 1 initially
+	}},
+	{".Back", emer.Params{
+		"Prjn.WtScale.Rel": 0.2, // this is generally quite important
+	}},
+	// // TODO: wrong way to do this; needs concrete projection-specific version; i.e., a "named" prjn?
+	// {".Lateral", emer.Params{
+	// 	"Prjn.WtScale.Rel": 0.5, // this is generally quite important
+	// }},
+}
+
+// PlotColorNames are the colors to use (in order) for plotting
+// successive lines -- user to customize!
+var PlotColorNames = []string{"black", "red", "blue",
+	"ForestGreen", "purple", "orange", "brown", "chartreuse",
+	"navy", "cyan", "magenta", "tan", "salmon", "yellow4",
+	"SkyBlue", "pink"}
+
+// Sim encapsulates the entire simulation model, and we define all the
+// functionality as methods on this struct.  This keep all relevant
+// state information organized and available without having to pass
+// everything around as arguments to methods, and provides the core GUI
+// interface (note the view tages for the fields which provide hints to
+// how things should be displayed)
+// This can be edited directly by the user to access any elements of the simulation.
+type Sim struct {
+	Net          *leabra.Network `view:"no-inline"`
+	ExtReps      *etable.Table   `view:"no-inline"`
+	ValReps      *etable.Table   `view:"no-inline" desc:"validation patterns, opened from the ValRepsFile if it exists, tested every ValInterval epochs during training"`
+	TrainEnv     Env             `desc:"training environment: ExtReps items, in Order"`
+	TestEnv      Env             `desc:"testing environment: ValReps items (ExtReps if ValReps is empty), or the test split of the ExtReps if split as for the ValEnv, sequentially by default"`
+	ValEnv       Env             `desc:"validation environment, for Validate every ValInterval epochs: ValReps items, or the held-out validation split of the ExtReps if SplitOn or ValReps is empty"`
+	EpcLog       *etable.Table   `view:"no-inline"`
+	WtDiffs      *etable.Table   `view:"no-inline" desc:"per-projection weight change between CmpWtsA and CmpWtsB, computed by CompareWts"`
+	DelayStats   *etable.Table   `view:"no-inline" desc:"last epoch's training stats for each delay value in DelayVals"`
+	DWtLog       *etable.Table   `view:"no-inline" desc:"max and mean |DWt| and percent clipped per projection per epoch, before clipping to DWtClip"`
+	Contings     *etable.Table   `view:"no-inline" desc:"action-outcome contingencies: Motor Action yields Out1 with probability P, else Out2, if ContingOn"`
+	ContingStats *etable.Table   `view:"no-inline" desc:"last epoch's predicted vs. true probability of Out1 for each action in Contings"`
+	DevalLog     *etable.Table   `view:"no-inline" desc:"results of each run of the devaluation protocol (RunDeval)"`
+	RevLog       *etable.Table   `view:"no-inline" desc:"results of each contingency swap in the reversal protocol: performance before the swap and trials to recover it"`
+	RunLog       *etable.Table   `view:"no-inline" desc:"summary of each Train run: FirstZero, LastZero, NZero and final epoch stats"`
+	ActRecLog    *etable.Table   `view:"-" desc:"activation recordings of the run, if ActRecOn: one row per trial, with a column per ActRecLays"`
+	PartLog      *etable.Table   `view:"no-inline" desc:"unit participation per layer at each TestAll: dead units, never active, and overused units, active on more than PartMaxPct of the items"`
+	DriftLog     *etable.Table   `view:"no-inline" desc:"representational drift of the DriftLays at each DriftInterval checkpoint: 1 - cosine of the item patterns vs. the previous and first checkpoints"`
+	PruneLog     *etable.Table   `view:"no-inline" desc:"sparsity of each of the PrunePrjns at each PruneInterval pruning step"`
+	Policy       *etable.Table   `view:"no-inline" desc:"learned Goal -> Motor policy from the last Extract Policy: the action taken for each goal, with its confidence and predicted Outcome"`
+	TstTrlLog    *etable.Table   `view:"no-inline" desc:"last TestAll's per-trial results, with the predicted Outcome and the Goal acted on decoded by name"`
+	TstGrpLog    *etable.Table   `view:"no-inline" desc:"last TestAll's stats per item Group"`
+	DriveOuts    *etable.Table   `view:"no-inline" desc:"desired Outcome for each item in each drive state, if DriveOn: rows are item * number of drives + drive"`
+	DriveStats   *etable.Table   `view:"no-inline" desc:"last epoch's training stats for each drive state, if DriveOn"`
+	Params       emer.ParamStyle `view:"no-inline"`
+
+	RunCtrl   `desc:"run control: how long to train, when to stop, and what to display while running"`
+	SimConfig `desc:"main configuration of the network and of the trial timing"`
+	Epoch     int
+
+	AlphaCycle int `desc:"0, 1: 0 == 1st, 1 == 2nd alpha-trial of each two-trial sequence"`
+
+	Time leabra.Time
+
+	ViewLast    time.Time `view:"-" desc:"time of the last display update, for ViewMaxHz"`
+	ViewPending bool      `view:"-" desc:"whether a display update was skipped per ViewMaxHz since the last one"`
+
+	WtGridPrjn string            `desc:"projection to show in the Wt Grid tab, as Send:Recv layer names, e.g., Goal:Motor"`
+	WtGridUpdt leabra.TimeScales `desc:"at what time scale to update the Wt Grid tab during training: Trial or Epoch"`
+
+	CmpWtsA gi.FileName `desc:"first (earlier) weights file for Compare Wts"`
+	CmpWtsB gi.FileName `desc:"second (later) weights file for Compare Wts"`
+
+	PlotStyleFile string       `desc:"if set, the CurPlotStyle is opened from this JSON file at Config"`
+	SessionFile   string       `desc:"per-user file the GUI session layout (window size, splits, selected tab and plot settings) is saved to when the window is closed, and restored from at startup -- empty = none"`
+	PlotStyle     *PlotStyle   `view:"no-inline" desc:"the style of all the plots -- the CurPlotStyle"`
+	SmoothVals    []string     `desc:"epoch stats to also log smoothed, as <stat>Roll (rolling average) and <stat>Ewma (exponential) columns in EpcLog -- set before Config"`
+	BatchPlots    []BatchPlot  `desc:"epoch plots saved in the run bundle (see Export Bundle and -nogui), rendered without the gui"`
+	BatchPlotFmts []string     `desc:"file formats of the BatchPlots, by extension: svg, png, pdf, eps"`
+	BatchPlotSize float32      `desc:"width and height of the BatchPlots, in inches"`
+	SmoothWin     int          `desc:"number of epochs to smooth SmoothVals over"`
+	TraceOn       bool         `desc:"hold the DWt's of the TracePrjnPath projection in an eligibility trace, only committed to the weights when the Outcome matches the Goal (reward)"`
+	TracePrjnPath string       `desc:"projection to use the eligibility trace on, as Send:Recv layer names"`
+	PrjnLrns      []PrjnLrn    `desc:"per-projection mix of error-driven vs. Hebbian learning (e.g., Context:Goal purely Hebbian) -- projections not listed use the default XCal settings"`
+	PrjnWtInits   []PrjnWtInit `desc:"per-projection initial random weight mean, variance and symmetry -- projections not listed use the library defaults and Params"`
+	DWtClip       float32      `desc:"if > 0, clip the DWt of each synapse to +/- DWtClip after each DWt -- see DWtLog for the pre-clip stats"`
+	PrjnDecays    []PrjnDecay  `desc:"per-projection weight decay or synaptic scaling, applied after each weight update -- projections not listed have none"`
+	InitWtsFile   string       `desc:"if set, weights are loaded from this file after random initialization at Init -- for deliberately structured initial weights"`
+	TrialSpecFile string       `desc:"if set, the TrialSpec of the steps of each trial is opened from this JSON file at Init -- else the DefaultTrialSpec is used"`
+	TrialSpec     *TrialSpec   `view:"-" desc:"the TrialSpec opened from TrialSpecFile -- nil for the DefaultTrialSpec"`
+	YokeOrder     [][]int      `view:"-" desc:"if set, the training item order of each epoch, replayed by the TrainEnv (unless ReplayFile is set) -- for yoked runs (RunYoked)"`
+	ReplayFile    string       `desc:"if set, the training items are presented in the order recorded in this file (by Save Order, or order_hist.tsv in a run bundle) at Init, instead of the TrainEnv Order -- for yoked comparisons between model variants"`
+	NoPatsFile    bool         `view:"-" desc:"if true, Config does not open the ExtReps and ValReps pattern files -- the patterns are set up by the caller (e.g., MiniRun)"`
+
+	EpcLogFile  string `desc:"if set, each EpcLog row is appended to this (tab-separated) file as training proceeds -- the file is recreated at Init"`
+	EpcLogMax   int    `desc:"if > 0 and EpcLogFile is set, only keep (at least) the last EpcLogMax epochs in the in-memory EpcLog -- the full log is in EpcLogFile"`
+	DBFile      string `desc:"if set, the EpcLog, test trials and run summaries are also logged to tables of this SQLite .db file, with run metadata, for querying across runs (see sqlitelog.go, needs a build with -tags sqlite) -- opened at Init"`
+	EpcLogEvery int    `desc:"if > 1, only keep every EpcLogEvery'th epoch in the EpcLog and EpcLogFile, for very long runs -- the latest epoch is always shown"`
+
+	NetViewImgWidth int    `desc:"if > 0, width in pixels of the NetView images saved by Save NetView and for NetViewImgEpcs -- else the size as displayed"`
+	NetViewImgEpcs  []int  `desc:"training epochs at the end of which the NetView image is saved automatically, as goal_guy_0_netview_epc<epoch>.png"`
+	AnimRow         int    `desc:"row of the TestEnv item whose trial is recorded by Record Anim"`
+	AnimCycles      int    `desc:"number of cycles between the NetView frames recorded by Record Anim"`
+	AnimDelay       int    `desc:"delay between frames of the animated GIF saved by Record Anim, in 100ths of a second"`
+	AnimFile        string `desc:"file the Record Anim frames are saved to: an animated GIF if it ends in .gif, else a sequence of numbered PNG images"`
+
+	GiTuneTargs    []GiTuneTarg `desc:"target number of active units per layer for the TuneGi calibration of Layer.Inhib.Layer.Gi"`
+	GiTuneTrials   int          `desc:"number of settling trials (no learning) per TuneGi iteration"`
+	GiTuneMaxItrs  int          `desc:"maximum number of Gi adjustment iterations for TuneGi"`
+	GiTuneTol      float32      `desc:"tolerance on average number of active units for TuneGi to be done"`
+	ScaleTunePaths []string     `desc:"Send:Recv projections with a WtScale.Rel spin box in the Scales tab, in addition to all the Back projections"`
+
+	LayInhibs []LayInhib `desc:"inhibition mode (FFFB or explicit KWTA with k) per layer -- layers not listed use FFFB"`
+	PathConns []PathConn `desc:"connectivity pattern (OneToOne, Full or random sparse) of the Context:Goal, Goal:Motor, Motor:Outcome and Outcome:Motor pathways -- pathways not listed are Full"`
+
+	CriticOn     bool    `desc:"add a Critic layer that learns to predict goal attainment from Context and Goal, whose TD error modulates learning into the Motor layer (actor-critic)"`
+	CriticDAGain float32 `desc:"gain on the TD error modulation of Motor learning: DWt's are scaled by (1 + CriticDAGain * TD), floored at 0"`
+
+	GoalMaint     bool    `desc:"add a gated self-excitatory recurrent projection on the Goal layer, so the Goal can be maintained across alpha cycles without external clamping"`
+	GoalMaintGain float32 `desc:"relative strength (WtScale.Rel) of the Goal self projection when the maintenance gate is open"`
+	MaintDelay    int     `desc:"number of alpha cycles without input over which Goal maintenance fidelity is measured in TestAll, if GoalMaint"`
+
+	DelayVals   []int `desc:"possible delays, in alpha cycles, between the outcome / goal-setting AlphaCycle and the motor-production AlphaCycle -- one is chosen at random each trial, and no input is clamped during the delay -- empty = no delay"`
+	DelayNoGoal bool  `desc:"if true, the Goal is not clamped on the motor-production AlphaCycle after a delay, so it must be bridged by maintenance (see GoalMaint)"`
+
+	ItiCycs int  `desc:"number of empty alpha cycles (no input clamped, no learning) at the end of each trial -- the inter-trial interval, during which activity decays naturally (see iti.go) -- 0 = none"`
+	ItiLog  bool `desc:"if true, log the residual activity of the Goal, Motor and Outcome layers at the end of the ITI, averaged over each epoch, in the <Lay>ItiAct columns of the EpcLog -- set before Config"`
+
+	SeqSteps int `desc:"number of Motor steps needed to reach the Outcome on each trial -- 1 = original single-step Motor->Outcome mapping, 2-3 = action sequences, where each Motor output moves the environment to a new Context state (see seq.go)"`
+
+	ContingOn   bool        `desc:"if true, the Outcome on the 1st AlphaCycle is sampled from the Contings table given the Motor action, instead of being fixed per item"`
+	ContingP    float32     `desc:"probability of Out1 for each action, for generated Contings"`
+	ContingFile gi.FileName `ext:".tsv" desc:"if set, Contings are opened from this file instead of being generated"`
+
+	DevalItem      int `desc:"row of ExtReps whose Outcome is devalued (its Goal is never set) in the devaluation protocol (RunDeval)"`
+	DevalTrainEpcs int `desc:"number of training epochs with the Outcome devalued, prior to the devaluation test"`
+
+	Protocol     string   `desc:"name of the multi-phase protocol to run with Run Protocol (see Protocols)"`
+	AcqEpcs      int      `desc:"number of epochs in the acquisition phase of protocols"`
+	DegradEpcs   int      `desc:"number of epochs in the degradation phase of the degradation protocol"`
+	DegradAction int      `desc:"Motor action whose contingency with its Out1 outcome is degraded in the degradation protocol"`
+	DegradP      float32  `desc:"probability that the Out1 outcome of DegradAction is delivered after any other action, when degraded"`
+	ExtinctEpcs  int      `desc:"number of epochs in the extinction phase of the extinction protocol"`
+	ExtinctActs  []int    `desc:"Motor actions whose outcomes stop occurring in the extinction phase of the extinction protocol"`
+	RevEpcs      int      `desc:"number of epochs in the reversal phase of the reversal protocol"`
+	RevPairs     [][2]int `desc:"pairs of Motor actions whose outcomes are swapped at the start of the reversal phase"`
+	RevWindow    int      `desc:"number of trials over which outcome prediction performance is computed for reversal recovery"`
+	RevCrit      float32  `desc:"proportion of the pre-swap performance that counts as recovered"`
+
+	ValenceOn   bool    `desc:"if true, outcomes have a Valence (+1 appetitive, -1 aversive) given in the Valence column of the patterns, presented on a USValence input layer (see valence.go)"`
+	AversivePct float32 `desc:"proportion of items with aversive outcomes, when generating patterns"`
+
+	DriveOn    bool     `desc:"if true, a Drive input layer with one unit per drive state combines with Context to determine the desired Outcome, with the drive sampled each trial (see drive.go)"`
+	DriveNames []string `desc:"names of the drive states"`
+
+	CarryOn    bool    `desc:"if true, the Goal activity of each trial is carried over into the Context input of the next one as a soft input, decaying over trials, modeling persistent intentions (see carry.go)"`
+	CarryDecay float32 `min:"0" max:"1" desc:"proportion of the carried-over Goal activity lost on each trial, if CarryOn"`
+	CarryGain  float32 `desc:"scaling of the carried-over Goal activity added to the Context input, if CarryOn"`
+
+	ActRecOn   bool     `desc:"if true, record the ActRecVar unit vectors of the ActRecLays at the end of every training and test trial in the ActRecLog, for export to Arrow (Save ActRec, and actrec.arrow in run bundles) -- see actrec.go"`
+	ActRecLays []string `desc:"layers whose activations are recorded if ActRecOn -- set before Init"`
+	ActRecVar  string   `desc:"unit variable recorded if ActRecOn, e.g., ActM"`
+
+	PartLays   []string `desc:"layers whose unit participation is reported in PartLog at the end of each TestAll"`
+	PartThr    float32  `desc:"ActM above which a unit counts as active on an item, for the unit participation report"`
+	PartMaxPct float32  `desc:"proportion of the test items above which a unit active on them is flagged as overused (grandmother cell) in PartLog"`
+
+	// statistics
+	SimStats `desc:"last epoch's and last TestAll's stats"`
+
+	MotCtxtRF ActRF `view:"no-inline" desc:"activation-based receptive fields of Motor units for Context inputs, accumulated over the run"`
+	MotGoalRF ActRF `view:"no-inline" desc:"activation-based receptive fields of Motor units for Goal inputs, accumulated over the run"`
+
+	WtGrid *etensor.Float32 `view:"no-inline" desc:"weights of the WtGridPrjn projection, as last shown in the Wt Grid tab"`
+
+	OutReps     [][]float32   `view:"-" desc:"Outcome ActM of each trial of the last TestAll, for the cluster plots"`
+	OutRepRows  []int         `view:"-" desc:"row of the OutRepTable of each of the OutReps"`
+	OutRepTable *etable.Table `view:"-" desc:"pattern table of the last TestAll"`
+
+	DriftRef     [][][]float32 `view:"-" desc:"DriftLays ActM per item at the first drift checkpoint, indexed by layer then row"`
+	DriftRefEpc  int           `view:"-" desc:"epoch of the first drift checkpoint"`
+	DriftPrev    [][][]float32 `view:"-" desc:"DriftLays ActM per item at the previous drift checkpoint, indexed by layer then row"`
+	DriftPrevEpc int           `view:"-" desc:"epoch of the previous drift checkpoint"`
+
+	Pruned map[string][]bool `view:"-" desc:"pruned synapses of each of the PrunePrjns, by Send:Recv path, indexed as the projection Syns"`
+
+	Smooth map[string]*SmoothState `view:"-" desc:"running smoothing state of each of the SmoothVals, updated once per epoch"`
+
+	MotConfMat *etensor.Float32 `view:"no-inline" desc:"confusion matrix for last TestAll: rows are the true action (Motor ActP that produced the Outcome), columns the action decoded from Motor ActM when driven by that Goal"`
+
+	// internal state - view:"-"
+	GoalSumSSE     float32 `view:"-" inactive:"+" desc:"sum to increment as we go through epoch"`
+	GoalSumAvgSSE  float32 `view:"-" inactive:"+" desc:"sum to increment as we go through epoch"`
+	GoalSumCosDiff float32 `view:"-" inactive:"+" desc:"sum to increment as we go through epoch"`
+
+	Stats        StatsRecorder    `view:"-" desc:"records the Motor and Outcome trial stats once per trial and computes their epoch averages"`
+	OutPat       *etensor.Float32 `view:"-" desc:"the Outcome pattern of the current item, as applied in the 1st AlphaCycle"`
+	OutGoalCmp   LayerCmp         `view:"-" desc:"comparison of the Outcome vs. Goal minus phase activations on the current trial"`
+	OutClassCor  bool             `view:"-" desc:"whether the Outcome minus phase activation on the current trial is closest (by cosine) to the correct one (OutPat) of all the Outcome patterns of the CurEnv"`
+	GoalClassCor bool             `view:"-" desc:"whether the Goal minus phase activation of the 1st AlphaCycle on the current trial is closest (by cosine) to the correct one (OutPat) of all the Outcome patterns of the CurEnv -- i.e., whether Context predicts the upcoming outcome in Goal"`
+	CurEnv       *Env             `view:"-" desc:"the environment of the current trial -- TrainEnv or TestEnv"`
+	EnvMotor     *etensor.Float32 `view:"-" desc:"the Motor target computed by EnvActPat for the current trial"`
+	ValidPats    [][]float32      `view:"-" desc:"the Outcome patterns valid for the Context of the current item (see MapMode), as of the 1st AlphaCycle"`
+	OutValidCor  bool             `view:"-" desc:"whether the Outcome minus phase activation on the current trial matches any of the ValidPats"`
+	OutPatCmp    LayerCmp         `view:"-" desc:"comparison of the Outcome minus phase activation vs. the item's Outcome pattern (OutPat) on the current trial"`
+	OutDecoder   Decoder          `view:"-" desc:"decodes Outcome and Goal activity to the Name of the nearest test item Outcome -- initialized in TestAll"`
+
+	EpcLogW        *os.File  `view:"-" desc:"open EpcLogFile, if streaming the EpcLog"`
+	DB             *sql.DB   `view:"-" desc:"open DBFile, if logging to SQLite"`
+	DBRunID        int64     `view:"-" inactive:"+" desc:"run_id of the current run in the DBFile runs table -- 0 if not logging to it"`
+	EpcLogOff      int       `view:"-" inactive:"+" desc:"number of rows dropped from the start of the in-memory EpcLog per EpcLogMax"`
+	EpcLogTmp      bool      `view:"-" inactive:"+" desc:"whether the last EpcLog row is an epoch not kept per EpcLogEvery, to be overwritten by the next one"`
+	WtUpdtCnt      int       `view:"-" inactive:"+" desc:"number of weight updates so far in this epoch"`
+	DecayAmt       float32   `view:"-" inactive:"+" desc:"total absolute linear weight change made by the PrjnDecays so far in this epoch"`
+	DWtSums        []DWtSum  `view:"-" desc:"per-projection DWt stats accumulated over the current epoch"`
+	Trace          TracePrjn `view:"-" desc:"eligibility trace projection, if TraceOn"`
+	TraceCommitCnt int       `view:"-" inactive:"+" desc:"number of eligibility trace commits so far in this epoch"`
+
+	TrialRew    float32          `view:"-" inactive:"+" desc:"reward for current trial: 1 if outcome matched goal, if CriticOn"`
+	TrialV      float32          `view:"-" inactive:"+" desc:"Critic value prediction for current trial, if CriticOn"`
+	TrialTD     float32          `view:"-" inactive:"+" desc:"TD error for current trial, if CriticOn"`
+	CriticSumV  float32          `view:"-" inactive:"+" desc:"sum to increment as we go through epoch"`
+	CriticSumTD float32          `view:"-" inactive:"+" desc:"sum to increment as we go through epoch"`
+	CriticTarg  *etensor.Float32 `view:"-" desc:"Critic target (reward) pattern"`
+
+	GoalGateOpen bool `view:"-" inactive:"+" desc:"whether the Goal maintenance gate is currently open"`
+
+	TrialDelay int        `view:"-" inactive:"+" desc:"delay, in alpha cycles, for the current trial"`
+	DelayCyc   int        `view:"-" inactive:"+" desc:"current alpha cycle within the delay period"`
+	DelaySums  []DelaySum `view:"-" desc:"per-delay stats sums to increment as we go through epoch"`
+
+	SeqStep   int              `inactive:"+" desc:"current step within the action sequence"`
+	SeqActIdx int              `view:"-" inactive:"+" desc:"Motor action taken on the current step of the sequence"`
+	SeqCtxt   *etensor.Float32 `view:"-" desc:"current environment state, clamped on Context during action sequences"`
+	SeqGoal   *etensor.Float32 `view:"-" desc:"goal state of the current action sequence"`
+	SeqCorCnt int              `view:"-" inactive:"+" desc:"number of sequences that reached their goal this epoch"`
+
+	ContingOut  *etensor.Float32 `view:"-" desc:"sampled Outcome for the current trial, if ContingOn"`
+	ContingSums []ContingSum     `view:"-" desc:"per-action contingency stats sums to increment as we go through epoch"`
+
+	Devalued bool `view:"-" inactive:"+" desc:"whether DevalItem is currently devalued"`
+
+	Phase        string `inactive:"+" desc:"name of the current protocol phase, if running a protocol"`
+	Degraded     bool   `view:"-" inactive:"+" desc:"whether the contingency of DegradAction is currently degraded"`
+	DegradActCnt int    `view:"-" inactive:"+" desc:"number of trials this epoch on which DegradAction was selected"`
+
+	Extinct          bool    `view:"-" inactive:"+" desc:"whether the outcomes of ExtinctActs are currently extinguished"`
+	ExtinctActCnt    int     `view:"-" inactive:"+" desc:"number of trials this epoch on which one of ExtinctActs was selected"`
+	ExtinctSumMotAct float32 `view:"-" inactive:"+" desc:"sum over trials this epoch of the average Motor activity of the ExtinctActs units"`
+
+	PartCnts [][]int `view:"-" desc:"number of items of the current TestAll on which each unit of each PartLays was active, indexed by layer then unit"`
+	PartN    int     `view:"-" desc:"number of items of the current TestAll counted in PartCnts"`
+
+	CarryBuf       []float32        `view:"-" desc:"carry-over buffer of the Goal activity of the previous trials, if CarryOn"`
+	CarryC         *etensor.Float32 `view:"-" desc:"Context pattern with the carry-over buffer added, if CarryOn"`
+	PrevAct        int              `view:"-" desc:"action decoded from Motor on the previous trial, for the perseveration stats -- -1 if none"`
+	PerseverCnt    int              `view:"-" inactive:"+" desc:"number of trials this epoch with a perseveration error"`
+	TstPerseverCnt int              `view:"-" inactive:"+" desc:"number of trials of the current TestAll with a perseveration error"`
+	TstActCnts     []float32        `view:"-" desc:"counts of the actions decoded from Motor over the trials of the current TestAll or Validate, for the MotEntropy"`
+	ValDone        bool             `view:"-" desc:"whether Validate ran during the current epoch, so LogEpoch logs the Val* stats -- else NaN"`
+
+	ItiCyc  int       `view:"-" inactive:"+" desc:"current alpha cycle of the inter-trial interval"`
+	ItiSums []float32 `view:"-" desc:"sums over this epoch's trials of the mean Act of each of the ItiLays at the end of the ITI"`
+	ItiN    int       `view:"-" desc:"number of ITIs summed in ItiSums"`
+
+	MotActCnts []float32 `view:"-" desc:"number of trials this epoch on which each action was decoded from Motor, for EpcMotEntropy"`
+
+	RevWin      []bool  `view:"-" desc:"outcome prediction correctness over the last RevWindow trials"`
+	RevTracking bool    `view:"-" inactive:"+" desc:"whether recovery from the last reversal is being tracked"`
+	RevTrials   int     `view:"-" inactive:"+" desc:"number of trials since the last reversal"`
+	RevTarg     float32 `view:"-" inactive:"+" desc:"performance level that counts as recovered from the last reversal"`
+
+	TrialValence float32          `inactive:"+" desc:"valence of the outcome of the current trial"`
+	ValenceInput *etensor.Float32 `view:"-" desc:"USValence input pattern"`
+	ApprCnt      int              `view:"-" inactive:"+" desc:"number of appetitive trials this epoch on which the action was approached"`
+	ApprTrlCnt   int              `view:"-" inactive:"+" desc:"number of appetitive trials this epoch"`
+	AvoidCnt     int              `view:"-" inactive:"+" desc:"number of aversive trials this epoch on which the action was avoided"`
+	AvoidTrlCnt  int              `view:"-" inactive:"+" desc:"number of aversive trials this epoch"`
+
+	TrialDrive  int              `inactive:"+" desc:"drive state for the current trial"`
+	DriveInput  *etensor.Float32 `view:"-" desc:"Drive input pattern for the current trial"`
+	DriveOut    *etensor.Float32 `view:"-" desc:"desired Outcome for the current trial given its drive state"`
+	DriveSums   []DriveSum       `view:"-" desc:"per-drive stats sums to increment as we go through epoch"`
+	BatchTrials int              `view:"-" inactive:"+" desc:"number of trials accumulated so far in current batch"`
+	BatchDWts   [][]float32      `view:"-" desc:"accumulated DWt's per projection, per synapse, for current batch"`
+
+	EpcPlotSvg  *svg.Editor            `view:"-" desc:"the epoch plot svg editor"`
+	ConfMatSvg  *svg.Editor            `view:"-" desc:"the confusion matrix svg editor"`
+	CtxtRFSvg   *svg.Editor            `view:"-" desc:"the Motor:Context receptive field svg editor"`
+	GoalRFSvg   *svg.Editor            `view:"-" desc:"the Motor:Goal receptive field svg editor"`
+	WtGridSvg   *svg.Editor            `view:"-" desc:"the projection weight grid svg editor"`
+	WtDiffSvg   *svg.Editor            `view:"-" desc:"the weight change comparison svg editor"`
+	OutClustSvg *svg.Editor            `view:"-" desc:"the Outcome representation cluster plot svg editor"`
+	PatClustSvg *svg.Editor            `view:"-" desc:"the Outcome pattern cluster plot svg editor"`
+	CmpSvg      *svg.Editor            `view:"-" desc:"the Compare Runs plot svg editor"`
+	ScaleSpins  map[string]*gi.SpinBox `view:"-" desc:"the Scales tab WtScale.Rel spin boxes, by projection entry"`
+	AnimFrames  []*image.RGBA          `view:"-" desc:"the NetView frames recorded by the last RecordTrialAnim"`
+	CmpRuns     []CmpRun               `view:"-" desc:"saved epoch logs compared in the Compare Runs tab"`
+	CmpCols     []string               `view:"-" desc:"epoch log columns compared in the Compare Runs tab"`
+
+	ConsoleLbl   *gi.Label              `view:"-" desc:"the Console tab output label"`
+	ConsoleLines []string               `view:"-" desc:"the Console output, most recent last"`
+	ProbeCtxt    *etensor.Float32       `view:"-" desc:"Context input set in the Probe tab"`
+	ProbeGoal    *etensor.Float32       `view:"-" desc:"Goal input set in the Probe tab"`
+	ProbeOutLbls map[string][]*gi.Label `view:"-" desc:"Motor and Outcome activity labels in the Probe tab"`
+	EditRow      int                    `view:"-" desc:"ExtReps row being edited in the Patterns tab"`
+	EditCtxt     *etensor.Float32       `view:"-" desc:"Context pattern being edited in the Patterns tab"`
+	EditOut      *etensor.Float32       `view:"-" desc:"Outcome pattern being edited in the Patterns tab"`
+	DWtMags      map[string][]float32   `view:"-" desc:"per-layer mean |DWt| of each unit's receiving synapses as of the last weight change, for the DWtMag view variable"`
+
+	TcOn    bool        `desc:"if true, record the activity of the TcUnits of the TcLayer every cycle across each trial, and plot it in the Timecourse tab"`
+	TcLayer string      `desc:"layer whose units are recorded in the timecourse"`
+	TcUnits []int       `desc:"indexes of the units to record in the timecourse -- empty = all units in the layer"`
+	TcActs  [][]float32 `view:"-" desc:"recorded timecourse for the current trial: activities of the units for each cycle"`
+	TcSvg   *svg.Editor `view:"-" desc:"the unit timecourse svg editor"`
+
+	Panel       ControlPanel          `view:"-" desc:"the grouped Control Panel shown in place of the full Sim -- see Advanced"`
+	NetViews    []*netview.NetView    `view:"-" desc:"the network viewers, all updated together"`
+	Detached    map[string]*gi.Window `view:"-" desc:"windows of the tabs popped out of the tab view, by tab label -- closing them re-docks the tabs"`
+	NetViewVars []string              `desc:"unit variables shown in the NetView tabs created at startup -- one tab per variable"`
+
+	StopNow    bool          `view:"-" desc:"flag to stop running"`
+	StopSignal os.Signal     `view:"-" desc:"the SIGINT or SIGTERM that stopped the run, if CatchStopSignals"`
+	Paused     bool          `inactive:"+" desc:"whether training is paused between trials -- see Pause, Resume"`
+	PauseMu    sync.Mutex    `view:"-" desc:"protects Paused and ResumeCh"`
+	ResumeCh   chan struct{} `view:"-" desc:"closed by Resume to release the training blocked in WaitPaused"`
+	RndSeed    int64         `view:"-" desc:"the current random seed"`
+
+	Halt       string     `view:"-" inactive:"+" desc:"reason training was halted automatically in this run (e.g., by HaltOnNaN), recorded in the RunLog -- empty if none"`
+	Diverged   bool       `view:"-" inactive:"+" desc:"whether the divergence alert of CheckDiverge was already given in this run"`
+	TrainTmr   timer.Time `view:"-" desc:"timer of the current (or last) Train, for ReportProgress"`
+	TrainStEpc int        `view:"-" desc:"epoch at which the current (or last) Train started"`
+
+	ProfOn     bool                   `view:"-" desc:"if true, accumulate the time spent in each of the ProfSections (see RunProfile)"`
+	ProfTimers map[string]*timer.Time `view:"-" desc:"timers for each of the ProfSections"`
+	Clamps     ClampState             `view:"-" desc:"table cells written during the current trial (StoreActP), restored at its end"`
+
+	// callbacks -- user-registerable hooks called from within AlphaCyc
+	OnCycleEnd       func(ss *Sim, cyc int) `view:"-" desc:"if non-nil, called at the end of every cycle within AlphaCyc, with the cycle index within the current quarter"`
+	OnQuarterEnd     func(ss *Sim, qtr int) `view:"-" desc:"if non-nil, called at the end of every quarter within AlphaCyc, after QuarterFinal, with the quarter index just completed"`
+	OnPlusPhaseStart func(ss *Sim)          `view:"-" desc:"if non-nil, called at the start of the plus phase (final quarter) within AlphaCyc, before any of its cycles are run -- e.g., for delivering reward or changing clamped inputs"`
+
+	// callbacks -- user-registerable hooks called from TrainTrial and TestTrial
+	OnTrialStart func(ss *Sim, env *Env, row int) `view:"-" desc:"if non-nil, called at the start of every TrainTrial and TestTrial, with the env and its table row for the trial, before any inputs are applied -- e.g., for changing the environment"`
+	OnTrialEnd   func(ss *Sim, env *Env, row int) `view:"-" desc:"if non-nil, called at the end of every TrainTrial and TestTrial, after the trial stats are computed and before the env moves to the next trial -- e.g., for custom logging"`
+	OnEpochEnd   func(ss *Sim, epc int)           `view:"-" desc:"if non-nil, called at the end of every training epoch, after LogEpoch, with the epoch just completed -- e.g., for custom stopping logic, by setting StopNow"`
+}
+
+// New creates new blank elements
+func (ss *Sim) New() {
+	ss.Net = &leabra.Network{}
+	ss.NotifyURL = DefaultNotifyURL
+	ss.DBFile = DefaultDBFile
+	ss.NaNCheck = true
+	ss.ExtReps = &etable.Table{}
+	ss.ValReps = &etable.Table{}
+	ss.EpcLog = &etable.Table{}
+	ss.WtDiffs = &etable.Table{}
+	ss.DelayStats = &etable.Table{}
+	ss.DWtLog = &etable.Table{}
+	ss.Contings = &etable.Table{}
+	ss.ContingStats = &etable.Table{}
+	ss.DevalLog = &etable.Table{}
+	ss.RevLog = &etable.Table{}
+	ss.PlotStyle = &CurPlotStyle
+	ss.SessionFile = DefaultSessionFile()
+	ss.RunLog = &etable.Table{}
+	ss.DriftLog = &etable.Table{}
+	ss.PruneLog = &etable.Table{}
+	ss.Policy = &etable.Table{}
+	ss.PartLog = &etable.Table{}
+	ss.ActRecLog = &etable.Table{}
+	ss.TstTrlLog = &etable.Table{}
+	ss.CmpCols = []string{"OutGoalPctErr"}
+	ss.TstGrpLog = &etable.Table{}
+	ss.DriveOuts = &etable.Table{}
+	ss.DriveStats = &etable.Table{}
+	ss.Params = CopyParams(DefaultParams)
+	ss.RndSeed = 1
+
+	ss.ViewOn = true
+	ss.NetViewVars = []string{"Act"}
+	ss.TcLayer = "Motor"
+	ss.TrainUpdt = leabra.Cycle
+	ss.TestUpdt = leabra.Cycle
+	ss.ViewMaxHz = 30
+	ss.MoreEpcs = 10
+	ss.WtGridPrjn = "Goal:Motor"
+	ss.WtGridUpdt = leabra.Epoch
+
+	ss.GiTuneTargs = []GiTuneTarg{{"Motor", 1}, {"Outcome", 1}}
+	ss.GiTuneTrials = 10
+	ss.GiTuneMaxItrs = 20
+	ss.GiTuneTol = 0.2
+	ss.ScaleTunePaths = []string{"Outcome:Motor", "Goal:Motor", "Context:Goal"}
+	ss.AnimCycles = 5
+	ss.AnimDelay = 10
+	ss.AnimFile = "goal_guy_0_anim.gif"
+
+	ss.LayInhibs = []LayInhib{{"Motor", FFFB, 1}, {"Outcome", FFFB, 1}}
+	ss.PathConns = append([]PathConn{}, DefaultPathConns...)
+
+	ss.PatNOn = 3
+	ss.MapFan = 2
+	ss.NPools = 5
+	ss.OutMotBack = true
+	ss.TracePrjnPath = "Goal:Motor"
+	ss.CriticDAGain = 1
+	ss.GoalMaintGain = 1
+	ss.MaintDelay = 2
+	ss.SeqSteps = 1
+	ss.ContingP = 0.8
+	ss.DevalTrainEpcs = 5
+	ss.Protocol = "degradation"
+	ss.AcqEpcs = 50
+	ss.DegradEpcs = 50
+	ss.DegradP = 0.5
+	ss.ExtinctEpcs = 50
+	ss.ExtinctActs = []int{0}
+	ss.RevEpcs = 50
+	ss.RevPairs = [][2]int{{0, 1}}
+	ss.RevWindow = 25
+	ss.RevCrit = 0.9
+	ss.AversivePct = 0.5
+	ss.DriveNames = []string{"hunger", "thirst"}
+	ss.SmoothVals = []string{"OutGoalPctErr", "OutSSE", "MotSSE"}
+	ss.SmoothWin = 10
+	ss.DriftLays = []string{"Goal", "Motor", "Outcome"}
+	ss.PrunePrjns = []string{"Goal:Motor"}
+	ss.PruneThr = 0.1
+	ss.PartLays = []string{"Goal", "Motor", "Outcome"}
+	ss.ActRecLays = []string{"Goal", "Motor", "Outcome"}
+	ss.ActRecVar = "ActM"
+	ss.PartThr = 0.5
+	ss.PartMaxPct = 0.5
+	ss.CarryDecay = 0.5
+	ss.CarryGain = 0.5
+	ss.BatchPlots = DefaultBatchPlots
+	ss.BatchPlotFmts = []string{"svg", "png"}
+	ss.BatchPlotSize = 5
+	ss.TestEnv.Order = Sequential
+	ss.ValEnv.Order = Sequential
+	ss.SplitValPct = 0.2
+	ss.SplitTestPct = 0.2
+	ss.SplitFile = "goal-guy-0-split.tsv"
+	ss.CycPerQtr = 25
+	ss.NQuarters = 4
+	ss.NPlusQtrs = 1
+	ss.AlphaTimings = []AlphaTiming{{}, {}}
+}
+
+// Config configures all the elements using the standard functions
+func (ss *Sim) Config() {
+	ss.ConfigNet()
+	oshp, onms := ss.OutShape()
+	ss.OutPat = etensor.NewFloat32(oshp, nil, onms)
+	ss.EnvMotor = etensor.NewFloat32(oshp, nil, onms)
+	ss.ConfigConfMat()
+	ss.ConfigActRFs()
+	ss.ConfigTrace()
+	ss.ConfigCritic()
+	ss.ConfigMaint()
+	ss.ConfigContings()
+	if !ss.NoPatsFile {
+		ss.OpenExtReps()
+		ss.OpenValReps()
+	}
+	ss.ConfigSeq(ss.ExtReps)
+	ss.ConfigSeq(ss.ValReps)
+	if ss.DriveOn {
+		ss.ConfigDrives()
+	}
+	ss.ConfigEpcLog()
+	ss.ConfigRunLog()
+	ss.ConfigTstTrlLog()
+	ss.ConfigTstGrpLog()
+	ss.ConfigDelayStats()
+	ss.ConfigDWtLog()
+	ss.ConfigDevalLog()
+	ss.ConfigRevLog()
+	ss.ConfigPartLog()
+	if err := ss.OpenPlotStyleFile(); err != nil {
+		log.Println(err)
+	}
+}
+
+// Init restarts the run, and initializes everything, including
+// network weights and resets the epoch log table
+func (ss *Sim) Init() {
+	rand.Seed(ss.RndSeed)
+	if ss.MaxEpcs == 0 { // allow user override
+		ss.MaxEpcs = 500
+	}
+	ss.Epoch = 0
+	ss.StopNow = false
+	ss.Diverged = false
+	ss.Halt = ""
+	ss.ValDone = false
+	ss.Time.Reset()
+	ss.ConfigEnvs() // always start with new order so random order is identical
+	if err := ss.OpenTrialSpecFile(); err != nil {
+		log.Println(err)
+	}
+	ss.Net.StyleParams(ss.Params, false) // true) // set msg
+	ss.StylePoolParams()
+	ss.ApplyPrjnLrns()
+	ss.InitWts()
+	ss.PreallocEpcLog()
+	ss.EpcLogOff = 0
+	ss.EpcLogTmp = false
+	if err := ss.OpenEpcLogFile(); err != nil {
+		log.Println(err)
+	}
+	if err := ss.OpenDB(); err != nil {
+		log.Println(err)
+	}
+	ss.ResetBatch()
+	ss.ResetEpcStats()
+	ss.DWtLog.SetNumRows(0)
+	ss.ResetZeroStats()
+	ss.RevWin = nil
+	ss.RevTracking = false
+	ss.ResetActRFs()
+	ss.ResetDrift()
+	ss.ResetPrune()
+	ss.ResetSmooth()
+	ss.CarryReset()
+	ss.ConfigActRecLog()
+	ss.UpdateView()
+	ss.UpdtWtGrid()
+}
+
+// Setup creates and configures the sim, using the named experiment preset
+// (see Expts) if expt is not empty, and initializes it -- this is all that
+// is needed to run the sim programmatically, without the gui
+func (ss *Sim) Setup(expt string) error {
+	ss.New()
+	if expt != "" {
+		if err := ss.SetExpt(expt); err != nil {
+			return err
+		}
+	}
+
+	// generate the ExtReps table to hold externally clamped representations
+	// only if needed, so edits saved to the ExtRepsFile are kept
+	if GenPats {
+		ss.ConfigExtReps()
+	} else if err := ss.ExtRepsFileFits(); err != nil {
+		log.Printf("Setup: generating new patterns into %s: %v\n", ExtRepsFile, err)
+		ss.ConfigExtReps()
+	}
+
+	ss.Config()
+	if err := ss.ValidateExtReps(ss.ExtReps); err != nil {
+		return err
+	}
+	ss.Init()
+	return nil
+}
+
+// NewRndSeed gets a new random seed based on current time -- otherwise uses
+// the same random seed for every run
+func (ss *Sim) NewRndSeed() {
+	ss.RndSeed = time.Now().UnixNano()
+}
+
+// UpdateView updates the NetView tabs visualizing the runnng network
+func (ss *Sim) UpdateView() {
+	if !ss.ViewDue() {
+		return
+	}
+	ss.ViewPending = false
+	ss.ProfStart(ProfGUI)
+	defer ss.ProfStop(ProfGUI)
+	for _, nv := range ss.NetViews {
+		nv.Update("Counters:")
+	}
+}
+
+///////////////////////////////////////////////////////////////
+//      Running the Network, starting bottom-up...
+
+// AlphaCyc runs one alpha-trial (100 msec, 4 quarters) of processing
+// and corresponds roughly to the original LeabraTrial.
+// ApplyInputs() must have already been called prior (e.g., see TrainTrial).
+// If learn == true, then DWt and/or WtFmDWt calls are made to update
+// weights for learning.
+// Handles all NetView updating that is within scope of AlphaCycle.
+// Calls the OnPlusPhaseStart, OnCycleEnd and OnQuarterEnd hooks if set.
+// But, does NOT handle trial stats nor counter incrementing --
+// TrainTrial does that now.
+func (ss *Sim) AlphaCyc(train bool) {
+	viewUpdt := ss.TrainUpdt
+	if !train {
+		viewUpdt = ss.TestUpdt
+	}
+	ss.Net.AlphaCycInit()
+	ss.Time.AlphaCycStart()
+	cpq, nq, np := ss.AlphaTimes()
+	ss.Time.CycPerQtr = cpq
+	for qtr := 0; qtr < nq; qtr++ {
+		lq := LeabraQtr(qtr, nq, np)
+		ss.Time.Quarter = lq
+		ss.Time.PlusPhase = lq == 3
+		if qtr == nq-np { // start of plus phase
+			ss.CriticPlusPhase(train)
+			ss.ContingPlusPhase(train)
+			if ss.OnPlusPhaseStart != nil {
+				ss.OnPlusPhaseStart(ss)
+			}
+		}
+		for cyc := 0; cyc < ss.Time.CycPerQtr; cyc++ {
+			// TODO: figure this guy out!!!
+			ss.ProfStart(ProfCycle)
+			ss.Net.Cycle(&ss.Time)
+			ss.ApplyKWTA()
+			ss.RecTimecourse()
+			ss.ProfStop(ProfCycle)
+			ss.Time.CycleInc()
+			if ss.OnCycleEnd != nil {
+				ss.OnCycleEnd(ss, cyc)
+			}
+			if ss.ViewOn {
+				switch viewUpdt {
+				case leabra.Cycle:
+					ss.UpdateView()
+				case leabra.FastSpike:
+					if (cyc+1)%10 == 0 {
+						ss.UpdateView()
+					}
+				}
+			}
+		}
+		if lq != 3 || qtr == nq-1 { // plus phase ends only at the last quarter
+			ss.Net.QuarterFinal(&ss.Time)
+		}
+		ss.Time.QuarterInc()
+		if ss.OnQuarterEnd != nil {
+			ss.OnQuarterEnd(ss, qtr)
+		}
+		if ss.ViewOn {
+			switch viewUpdt {
+			case leabra.Quarter:
+				ss.UpdateView()
+			case leabra.Phase:
+				if lq >= 2 {
+					ss.UpdateView()
+				}
+			}
+		}
+	}
+
+	if train {
+		ss.ProfStart(ProfDWt)
+		ss.Net.DWt()
+		ss.CriticModDWt()
+		ss.ClipDWts()
+		ss.RecDWtMags()
+		ss.HoldTrace()
+		ss.WtFmDWt()
+		ss.ProfStop(ProfDWt)
+		//fmt.Println("Wts should be getting updated.")
+	}
+	if ss.ViewOn && viewUpdt == leabra.AlphaCycle {
+		ss.UpdateView()
+	}
+}
+
+// ApplyInputs applies input patterns from given row of given table, per
+// the step of the TrialSpec for the current AlphaCycle.
+// It is good practice to have this be a separate method with
+// appropriate args so that it can be used for various different
+// contexts (e.g., training, testing, etc.).
+// ApplyInputs() must be called BEFORE AlphaCyc()
+func (ss *Sim) ApplyInputs(env *Env, row int) {
+	ss.Net.InitExt() // clear any existing inputs; good practice, cheap
+
+	steps := ss.CurTrialSpec().Steps
+	if ss.AlphaCycle >= len(steps) {
+		fmt.Println("AlphaCycle appears to be out-of-range")
+		return
+	}
+	if ss.AlphaCycle == 0 {
+		o := RowCell(env.Table.ColByName("Outcome"), row) // 2D, or 4D if PoolsOn
+		if ss.DriveOn {
+			o = ss.DriveOut // desired outcome depends on drive state
+		}
+		for i := range ss.OutPat.Values { // o is overwritten by StoreActP
+			ss.OutPat.Values[i] = float32(o.FloatVal1D(i))
+		}
+		if ss.DriveOn {
+			ss.ValidPats = [][]float32{ss.OutPat.Values}
+		} else {
+			ss.ValidPats = ValidOutPats(env.Table, row)
+		}
+	}
+	ss.ApplyTrialStep(env, row, &steps[ss.AlphaCycle])
+}
+
+// TrainTrial runs one trial of training (Trial is now an
+// environmentally-defined term -- see leabra.TimeScales
+// for new, different terminology)
+func (ss *Sim) TrainTrial() {
+	env := &ss.TrainEnv
+	ss.CurEnv = env
+	row := env.Row() // REMEMBER: two alpha cycles per trial
+	if row < 0 {
+		log.Println("TrainTrial: TrainEnv table has no rows")
+		ss.StopNow = true
+		return
+	}
+	if ss.OnTrialStart != nil {
+		ss.OnTrialStart(ss, env, row)
+	}
+	defer ss.RestoreClamps()
+	et := env.Table
+
+	//contextLay := ss.Net.LayerByName("Context").(*leabra.Layer)
+	//goalLay := ss.Net.LayerByName("Goal").(*leabra.Layer)
+	//motorLay := ss.Net.LayerByName("Motor").(*leabra.Layer)
+	//outcomeLay := ss.Net.LayerByName("Outcome").(*leabra.Layer)
+
+	rew := false // outcome matched goal on 1st AlphaCycle
+	var msse, mcd float32
+	goalerr := false
+	ss.TrialValence = ss.ItemValence(et, row)
+	ss.NewTrialDrive(row)
+	ss.ResetTimecourse()
+	ss.NewTrialDelay()
+	ss.SeqInit(et, row)
+	ss.ItiCycles(true)
+	ts := ss.CurTrialSpec()
+	for ss.SeqStep = 0; ss.SeqStep < ss.NSeqSteps(); ss.SeqStep++ {
+		last := ss.SeqStep == ss.NSeqSteps()-1 // only accumulate stats on final step
+		for ss.AlphaCycle = 0; ss.AlphaCycle < ss.NTrialSteps(); ss.AlphaCycle++ {
+			ss.ApplyInputs(env, row)
+			ss.AlphaCyc(true) // train
+			ss.UpdtActRFs()
+
+			// After the ActPStep copy Motor and Outcome activation
+			// vectors and write to corresponding columns of ExtReps table.
+			// (To be used by ApplyInputs() to clamp Goal (emer.Input) and
+			// Motor (emer.Target) in the later steps -- restored by
+			// RestoreClamps at the end of the trial.
+			if ss.AlphaCycle == ts.ActPStep() {
+				ss.StoreActP(et, row)
+			}
+			_, ms, _, _, _, _, mc, _, ge := ss.TrialStats(last) // accumulate
+			if ss.AlphaCycle == ts.OutStep() {
+				goalerr = ge
+				if !ss.SeqOn() {
+					rew = !ge && ss.TrialValence > 0
+				}
+				ss.SeqAct()
+				if ss.AlphaCycle < ts.MotStep() {
+					ss.DelayCycs()
+				}
+			}
+			if ss.AlphaCycle == ts.MotStep() {
+				msse, mcd = ms, mc
+			}
+		}
+		ss.AlphaCycle = 0
+
+		if last {
+			ss.MotActAdd()
+			motorLay := ss.Net.LayerByName("Motor").(*leabra.Layer)
+			if ss.PerseverCheck(ss.ActIdx(motorLay, "ActP"), ss.ActIdx(motorLay, "ActM")) {
+				ss.PerseverCnt++
+			}
+		}
+		ss.SetGoalGate(false)
+		ss.SeqEnvStep()
+	}
+	ss.SeqStep = 0
+	ss.CarryStore()
+	ss.RecActs(env, row, true)
+	ss.ValenceTrialStats(ss.SeqActIdx)
+	ss.DriveStatsAdd(msse, goalerr)
+	if ss.SeqOn() {
+		rew = ss.SeqSuccess() && ss.TrialValence > 0 // reward is for reaching the goal at end of sequence
+		if rew {
+			ss.SeqCorCnt++
+		}
+	}
+	ss.DelayStatsAdd(msse, mcd, !rew)
+
+	// To allow for interactive single-step running, all of the
+	// higher temporal scales must be incorporated into the trial
+	// level run method.
+	// This is a good general principle for even more complex
+	// environments:
+	// there should be a single method call that gets the next "step"
+	// of the environment, and all the higher levels of temporal
+	// structure sould all be properl updated thourgh this one lowest-
+	// level method call.
+
+	ss.Stats.TrialDone()
+	ss.CommitTrace(rew)
+	ss.BatchTrialDone()
+	ss.PlotTimecourse()
+	if ss.WtGridUpdt == leabra.Trial {
+		ss.UpdtWtGrid()
+	}
+	if ss.OnTrialEnd != nil {
+		ss.OnTrialEnd(ss, env, row)
+	}
+
+	env.Trial++
+	if env.Trial >= env.NumRows() {
+		if ss.ValInterval > 0 && (ss.Epoch+1)%ss.ValInterval == 0 {
+			ss.Validate()
+		}
+		ss.LogEpoch()
+		if ss.Plot {
+			ss.PlotEpcLog()
+			ss.PlotActRFs()
+		}
+		if ss.WtGridUpdt > leabra.Trial {
+			ss.UpdtWtGrid()
+		}
+		ss.CaptureNetViewEpc()
+		ss.ReportProgress()
+		ss.HaltOnNaN()
+		ss.CheckDiverge()
+		ss.CheckDrift()
+		ss.CheckPrune()
+		if ss.OnEpochEnd != nil {
+			ss.OnEpochEnd(ss, ss.Epoch)
+		}
+		ss.Epoch++
+		env.Init(nil)
+		if ss.ViewOn && ss.TrainUpdt > leabra.AlphaCycle {
+			ss.UpdateView()
+		}
+	}
+}
+
+// TrialStats computes the trial-level statistics and adds them to
+// the epoch accumulators if accum is true: the Outcome stats after the
+// OutStep of the TrialSpec, and the Motor stats after its MotStep.
+// Note that we're accumulating stats here on the Sim side so the
+// core algorithmic side remains as simple as possible, and doesn't
+// need to worry about different time-scales over which stats could
+// be accumulated, etc.
+func (ss *Sim) TrialStats(accum bool) (gsse, msse, osse, gavgsse, mavgsse, oavgsse, motcosdiff, outcosdiff float32, outgoalerr bool) {
+	ss.ProfStart(ProfStats)
+	defer ss.ProfStop(ProfStats)
+	goalLay := ss.Net.LayerByName("Goal").(*leabra.Layer)
+	motorLay := ss.Net.LayerByName("Motor").(*leabra.Layer)
+	outcomeLay := ss.Net.LayerByName("Outcome").(*leabra.Layer)
+
+	ts := ss.CurTrialSpec()
+	if ss.AlphaCycle >= ss.NTrialSteps() {
+		fmt.Println("TrialStats says AlphaCycle appears to be out-of-range")
+		return
+	}
+	// steps other than the OutStep and MotStep have no stats
+	if ss.AlphaCycle == ts.OutStep() {
+		gsse, gavgsse = goalLay.MSE(0.5) // 0.5 = per-unit tolerance -- right side of .5
+		//msse, mavgsse = motorLay.MSE(0.5)   // 0.5 = per-unit tolerance -- right side of .5
+		osse, oavgsse = outcomeLay.MSE(0.5) // 0.5 = per-unit tolerance -- right side of .5
+
+		//goalcosdiff = goalLay.CosDiff.Cos
+		outcosdiff = outcomeLay.CosDiff.Cos
+
+		// sseg, errg := goalLay.UnitVals("ActM")
+		// sseo, erro := outcomeLay.UnitVals("ActM")
+		// if errg != nil && erro != nil {
+		// 	// take difference of sseg - sseo and calculate GoalCntErr
+		// }
+		ss.OutGoalCmp = CompareLayers(goalLay, outcomeLay, "ActM", 0.5)
+		outgoalerr = !ss.OutGoalCmp.Match
+		oacts, _ := outcomeLay.UnitVals("ActM")
+		ss.OutPatCmp = CompareVals(oacts, ss.OutPat.Values, 0.5)
+		ss.OutValidCor = AnyValidOut(oacts, ss.ValidPats)
+		gacts, _ := goalLay.UnitVals("ActM")
+		if ss.CurEnv != nil {
+			ss.OutClassCor = ss.CurEnv.OutDec.Classify(oacts, ss.OutPat.Values)
+			ss.GoalClassCor = ss.CurEnv.OutDec.Classify(gacts, ss.OutPat.Values)
+		}
+		if accum {
+			ss.Stats.Rec("OutSSE", osse)
+			ss.Stats.Rec("OutAvgSSE", oavgsse)
+			ss.Stats.Rec("OutCosDiff", outcosdiff)
+			ss.Stats.RecBool("OutPredErr", osse != 0)
+			ss.Stats.RecBool("OutGoalErr", outgoalerr)
+			ss.Stats.Rec("OutGoalCos", ss.OutGoalCmp.CosDiff)
+			ss.Stats.RecBool("OutPatErr", !ss.OutPatCmp.Match)
+			ss.Stats.RecBool("OutValidCor", ss.OutValidCor)
+			ss.Stats.Rec("OutPatCos", ss.OutPatCmp.CosDiff)
+			ss.Stats.RecBool("OutClassCor", ss.OutClassCor)
+			ss.Stats.RecBool("GoalClassCor", ss.GoalClassCor)
+		}
+	}
+	if ss.AlphaCycle == ts.MotStep() {
+		msse, mavgsse = motorLay.MSE(0.5) // 0.5 = per-unit tolerance -- right side of .5
+		motcosdiff = motorLay.CosDiff.Cos
+		if accum {
+			ss.Stats.Rec("MotSSE", msse)
+			ss.Stats.Rec("MotAvgSSE", mavgsse)
+			ss.Stats.Rec("MotCosDiff", motcosdiff)
+		}
+	}
+	return
+}
+
+// EpochInc increments counters after one epoch of processing and updates a new
+// order of inputs for the next epoch
+func (ss *Sim) EpochInc() {
+	ss.Epoch++
+	ss.TrainEnv.Init(nil)
+}
+
+// LogEpoch adds data from current epoch to the EpochLog table
+// -- computes epoch averages prior to logging.
+// Epoch counter is assumed to not have yet been incremented.
+func (ss *Sim) LogEpoch() {
+	ss.ProfStart(ProfStats)
+	defer ss.ProfStop(ProfStats)
+	epc := ss.EpcLogRow() // row in EpcLog for this epoch
+	contextLay := ss.Net.LayerByName("Context").(*leabra.Layer)
+	goalLay := ss.Net.LayerByName("Goal").(*leabra.Layer)
+	motorLay := ss.Net.LayerByName("Motor").(*leabra.Layer)
+	outcomeLay := ss.Net.LayerByName("Outcome").(*leabra.Layer)
+
+	// normalize by the trials actually run this epoch, which is fewer than
+	// the number of ExtReps rows if it was interrupted (or ExtReps changed)
+	np := float32(ss.Stats.NTrials)
+	if np == 0 {
+		np = 1 // all sums are 0 too
+	}
+	ss.Stats.EpochDone()
+	ss.EpcMotSSE = ss.Stats.EpcAvg("MotSSE")
+	ss.EpcOutSSE = ss.Stats.EpcAvg("OutSSE")
+	ss.EpcMotAvgSSE = ss.Stats.EpcAvg("MotAvgSSE")
+	ss.EpcOutAvgSSE = ss.Stats.EpcAvg("OutAvgSSE")
+
+	ss.EpcOutGoalPctErr = ss.Stats.EpcAvg("OutGoalErr")
+	ss.EpcOutPredPctErr = ss.Stats.EpcAvg("OutPredErr")
+	ss.EpcOutGoalPctCor = 1 - ss.EpcOutGoalPctErr
+	ss.EpcOutPredPctCor = 1 - ss.EpcOutPredPctErr
+	ss.ZeroStats()
+
+	ss.EpcMotCosDiff = ss.Stats.EpcAvg("MotCosDiff")
+	ss.EpcOutCosDiff = ss.Stats.EpcAvg("OutCosDiff")
+	ss.EpcOutGoalCos = ss.Stats.EpcAvg("OutGoalCos")
+	ss.EpcOutPatPctErr = ss.Stats.EpcAvg("OutPatErr")
+	ss.EpcOutValidPctCor = ss.Stats.EpcAvg("OutValidCor")
+	ss.EpcOutPatCos = ss.Stats.EpcAvg("OutPatCos")
+	ss.EpcOutClassPctCor = ss.Stats.EpcAvg("OutClassCor")
+	ss.EpcGoalClassPctCor = ss.Stats.EpcAvg("GoalClassCor")
+
+	ss.EpcWtUpdts = ss.WtUpdtCnt
+	ss.WtUpdtCnt = 0
+	ss.EpcWtDecay = ss.DecayAmt
+	ss.DecayAmt = 0
+	ss.LogDelayStats()
+	ss.LogDWtStats()
+	ss.EpcTracePctCommit = float32(ss.TraceCommitCnt) / np
+	ss.TraceCommitCnt = 0
+	ss.EpcCriticV = ss.CriticSumV / np
+	ss.EpcTDErr = ss.CriticSumTD / np
+	ss.EpcSeqPctCor = float32(ss.SeqCorCnt) / np
+	ss.SeqCorCnt = 0
+	ss.EpcContingErr = ss.LogContingStats()
+	ss.EpcDegradActPct = float32(ss.DegradActCnt) / np
+	ss.DegradActCnt = 0
+	ss.EpcExtinctActPct = float32(ss.ExtinctActCnt) / np
+	ss.EpcExtinctMotAct = ss.ExtinctSumMotAct / np
+	ss.ExtinctActCnt = 0
+	ss.ExtinctSumMotAct = 0
+	ss.LogValenceStats()
+	ss.LogDriveStats()
+	ss.LogMotEntropy()
+	ss.EpcPerseverPct = float32(ss.PerseverCnt) / np
+	ss.PerseverCnt = 0
+	ss.CriticSumV = 0
+	ss.CriticSumTD = 0
+
+	ss.EpcLog.ColByName("Epoch").SetFloat1D(epc, float64(ss.Epoch))
+	ss.EpcLog.ColByName("Phase").SetString1D(epc, ss.Phase)
+	ss.EpcLog.ColByName("MotSSE").SetFloat1D(epc, float64(ss.EpcMotSSE))
+	ss.EpcLog.ColByName("OutSSE").SetFloat1D(epc, float64(ss.EpcOutSSE))
+
+	ss.EpcLog.ColByName("MotAvgSSE").SetFloat1D(epc, float64(ss.EpcMotAvgSSE))
+	ss.EpcLog.ColByName("OutAvgSSE").SetFloat1D(epc, float64(ss.EpcOutAvgSSE))
+
+	ss.EpcLog.ColByName("OutGoalPctErr").SetFloat1D(epc, float64(ss.EpcOutGoalPctErr))
+	ss.EpcLog.ColByName("OutPredPctErr").SetFloat1D(epc, float64(ss.EpcOutPredPctErr))
+
+	ss.EpcLog.ColByName("OutGoalPctCor").SetFloat1D(epc, float64(ss.EpcOutGoalPctCor))
+	ss.EpcLog.ColByName("OutPredPctCor").SetFloat1D(epc, float64(ss.EpcOutPredPctCor))
+
+	ss.EpcLog.ColByName("MotCosDiff").SetFloat1D(epc, float64(ss.EpcMotCosDiff))
+	ss.EpcLog.ColByName("OutCosDiff").SetFloat1D(epc, float64(ss.EpcOutCosDiff))
+	ss.EpcLog.ColByName("OutGoalCos").SetFloat1D(epc, float64(ss.EpcOutGoalCos))
+	ss.EpcLog.ColByName("OutPatPctErr").SetFloat1D(epc, float64(ss.EpcOutPatPctErr))
+	ss.EpcLog.ColByName("OutValidPctCor").SetFloat1D(epc, float64(ss.EpcOutValidPctCor))
+	ss.EpcLog.ColByName("OutPatCos").SetFloat1D(epc, float64(ss.EpcOutPatCos))
+	ss.EpcLog.ColByName("OutClassPctCor").SetFloat1D(epc, float64(ss.EpcOutClassPctCor))
+	ss.EpcLog.ColByName("GoalClassPctCor").SetFloat1D(epc, float64(ss.EpcGoalClassPctCor))
+	ss.EpcLog.ColByName("WtUpdts").SetFloat1D(epc, float64(ss.EpcWtUpdts))
+	ss.EpcLog.ColByName("WtDecay").SetFloat1D(epc, float64(ss.EpcWtDecay))
+	ss.EpcLog.ColByName("MaxDWt").SetFloat1D(epc, float64(ss.EpcMaxDWt))
+	ss.EpcLog.ColByName("MeanDWt").SetFloat1D(epc, float64(ss.EpcMeanDWt))
+	ss.EpcLog.ColByName("TracePctCommit").SetFloat1D(epc, float64(ss.EpcTracePctCommit))
+	ss.EpcLog.ColByName("CriticV").SetFloat1D(epc, float64(ss.EpcCriticV))
+	ss.EpcLog.ColByName("TDErr").SetFloat1D(epc, float64(ss.EpcTDErr))
+	ss.EpcLog.ColByName("SeqPctCor").SetFloat1D(epc, float64(ss.EpcSeqPctCor))
+	ss.EpcLog.ColByName("ContingErr").SetFloat1D(epc, float64(ss.EpcContingErr))
+	ss.EpcLog.ColByName("DegradActPct").SetFloat1D(epc, float64(ss.EpcDegradActPct))
+	ss.EpcLog.ColByName("ExtinctActPct").SetFloat1D(epc, float64(ss.EpcExtinctActPct))
+	ss.EpcLog.ColByName("ExtinctMotAct").SetFloat1D(epc, float64(ss.EpcExtinctMotAct))
+	ss.EpcLog.ColByName("ApproachPct").SetFloat1D(epc, float64(ss.EpcApproachPct))
+	ss.EpcLog.ColByName("AvoidPct").SetFloat1D(epc, float64(ss.EpcAvoidPct))
+	ss.EpcLog.ColByName("MotEntropy").SetFloat1D(epc, float64(ss.EpcMotEntropy))
+	ss.EpcLog.ColByName("PerseverPct").SetFloat1D(epc, float64(ss.EpcPerseverPct))
+
+	//ss.EpcLog.ColByName("ContextActAvg").SetFloat1D(epc, float64(contextLay.Pools[0].ActAvg.ActPAvgEff))
+	//ss.EpcLog.ColByName("GoalActAvg").SetFloat1D(epc, float64(goalLay.Pools[0].ActAvg.ActPAvgEff))
+	//ss.EpcLog.ColByName("MotorActAvg").SetFloat1D(epc, float64(motorLay.Pools[0].ActAvg.ActPAvgEff))
+	//ss.EpcLog.ColByName("OutActAvg").SetFloat1D(epc, float64(outcomeLay.Pools[0].ActAvg.ActPAvgEff))
+	ss.EpcLog.ColByName("ContextActAvg").SetFloat1D(epc, float64(contextLay.Pools[0].ActAvg.ActMAvg))
+	ss.EpcLog.ColByName("GoalActAvg").SetFloat1D(epc, float64(goalLay.Pools[0].ActAvg.ActMAvg))
+	ss.EpcLog.ColByName("MotorActAvg").SetFloat1D(epc, float64(motorLay.Pools[0].ActAvg.ActMAvg))
+	ss.EpcLog.ColByName("OutActAvg").SetFloat1D(epc, float64(outcomeLay.Pools[0].ActAvg.ActMAvg))
+
+	ss.EpcLog.ColByName("OutGoalCntErr").SetFloat1D(epc, float64(ss.Stats.EpcSum("OutGoalErr")))
+	ss.EpcLog.ColByName("OutPredCntErr").SetFloat1D(epc, float64(ss.Stats.EpcSum("OutPredErr")))
+
+	// validation stats are from the Validate of this epoch -- NaN if none
+	vals := map[string]float32{
+		"ValMotSSE":          ss.ValMotSSE,
+		"ValOutSSE":          ss.ValOutSSE,
+		"ValMotCosDiff":      ss.ValMotCosDiff,
+		"ValOutCosDiff":      ss.ValOutCosDiff,
+		"ValOutGoalPctErr":   ss.ValOutGoalPctErr,
+		"ValOutPredPctErr":   ss.ValOutPredPctErr,
+		"ValOutClassPctCor":  ss.ValOutClassPctCor,
+		"ValGoalClassPctCor": ss.ValGoalClassPctCor,
+		"ValMaintCos":        ss.ValMaintCos,
+		"ValSeqPctCor":       ss.ValSeqPctCor,
+		"ValMotEntropy":      ss.ValMotEntropy,
+		"ValPerseverPct":     ss.ValPerseverPct,
+	}
+	for nm, v := range vals {
+		fv := float64(v)
+		if !ss.ValDone {
+			fv = math.NaN()
+		}
+		ss.EpcLog.ColByName(nm).SetFloat1D(epc, fv)
+	}
+	ss.ValDone = false
+
+	ss.LogItiResid(epc)
+	ss.LogSmooth(epc)
+	ss.DBLogEpoch(epc)
+	if ss.EpcLogKeep() {
+		ss.WriteEpcLogRow(epc)
+	} else {
+		ss.EpcLogTmp = true
+	}
+}
+
+// TrainEpoch runs one full epoch at a time; when stopped mid-epoch finishes current epoch
+func (ss *Sim) TrainEpoch() {
+	if !ss.CheckExtReps() {
+		return
+	}
+	curEpc := ss.Epoch
+	for {
+		ss.TrainTrial()
+		//ss.TrialStats(!ss.Test) // accumulate if not doing testing
+		//ss.TrialInc()           // does LogEpoch, EpochInc automatically
+		ss.WaitPaused()
+		if ss.StopNow || ss.Epoch > curEpc {
+			break
+		}
+	}
+	ss.FlushView()
+}
+
+// Train runs the full training from this point onward
+func (ss *Sim) Train() {
+	if !ss.CheckExtReps() {
+		return
+	}
+	ss.TrainStart()
+	for {
+		ss.TrainTrial()
+		ss.WaitPaused()
+		if ss.StopNow || ss.Epoch >= ss.MaxEpcs {
+			break
+		}
+		if ss.NZeroStop > 0 && ss.NZero >= ss.NZeroStop && ss.TrainEnv.Trial == 0 {
+			break
+		}
+	}
+	ss.TrainFinish()
+}
+
+// TrainStart does the bookkeeping at the start of a training run (Train or
+// TrainNEpochs): clears StopNow and starts the TrainTmr from this epoch
+func (ss *Sim) TrainStart() {
+	ss.StopNow = false
+	ss.TrainStEpc = ss.Epoch
+	ss.TrainTmr = timer.Time{}
+	ss.TrainTmr.Start()
+}
+
+// TrainFinish does the bookkeeping at the end of a training run (Train or
+// TrainNEpochs): stops the TrainTmr, records the run in the RunLog and
+// reports the time taken
+func (ss *Sim) TrainFinish() {
+	ss.TrainTmr.Stop()
+	ss.FlushView()
+	ss.LogRun()
+	epcs := ss.Epoch - ss.TrainStEpc
+	if epcs == 0 {
+		return
+	}
+	fmt.Printf("Took %6g secs for %v epochs, avg per epc: %6g\n", ss.TrainTmr.TotalSecs(), epcs, ss.TrainTmr.TotalSecs()/float64(epcs))
+}
+
+// TrainNEpochs trains for n more epochs from the current state, regardless
+// of MaxEpcs and NZeroStop -- for seeing whether more training helps,
+// without editing MaxEpcs and re-initializing
+func (ss *Sim) TrainNEpochs(n int) {
+	if !ss.CheckExtReps() {
+		return
+	}
+	ss.TrainStart()
+	for i := 0; i < n; i++ {
+		ss.TrainEpoch()
+		if ss.StopNow {
+			break
+		}
+	}
+	ss.TrainFinish()
+}
+
+// Stop tells the sim to stop running -- releasing it if paused
+func (ss *Sim) Stop() {
+	ss.StopNow = true
+	ss.Resume()
+}
+
+///////////////////////////////////////////////////////////
+// Testing
+
+// TestTrial runs one trial of testing on the current item of the given
+// environment, with learning off -- if logs, it is recorded in the test
+// logs (TstTrlLog, confusion matrix, activation recordings, timecourse).
+// Returns the stats for the trial (from the final step if SeqOn, with
+// outgoalerr reflecting whether the sequence failed to reach its goal),
+// and steps the environment to its next trial.
+func (ss *Sim) TestTrial(env *Env, logs bool) (msse, osse, motcosdiff, outcosdiff float32, outgoalerr bool) {
+	ss.CurEnv = env
+	row := env.Row()
+	if row < 0 {
+		return
+	}
+	if ss.OnTrialStart != nil {
+		ss.OnTrialStart(ss, env, row)
+	}
+	defer ss.RestoreClamps()
+	et := env.Table
+	motorLay := ss.Net.LayerByName("Motor").(*leabra.Layer)
+	tact := -1
+	ss.TrialValence = ss.ItemValence(et, row)
+	ss.NewTrialDrive(row)
+	ss.ResetTimecourse()
+	ss.NewTrialDelay()
+	ss.SeqInit(et, row)
+	ss.ItiCycles(false)
+	ts := ss.CurTrialSpec()
+	for ss.SeqStep = 0; ss.SeqStep < ss.NSeqSteps(); ss.SeqStep++ {
+		for ss.AlphaCycle = 0; ss.AlphaCycle < ss.NTrialSteps(); ss.AlphaCycle++ {
+			ss.ApplyInputs(env, row)
+			ss.AlphaCyc(false) // !train
+			if ss.AlphaCycle == ts.ActPStep() {
+				ss.StoreActP(et, row)
+			}
+			if ss.AlphaCycle == ts.OutStep() {
+				tact = ss.ActIdx(motorLay, "ActP")
+				if logs {
+					ss.LogTstTrlPred(et, row)
+				}
+			}
+			if ss.AlphaCycle == ts.MotStep() {
+				dact := ss.ActIdx(motorLay, "ActM")
+				if tact >= 0 && dact >= 0 && dact < len(ss.TstActCnts) {
+					ss.TstActCnts[dact]++
+				}
+				if logs {
+					ss.ConfMatAdd(tact, dact)
+				}
+				if ss.PerseverCheck(tact, dact) {
+					ss.TstPerseverCnt++
+				}
+			}
+			_, ms, ou, _, _, _, mc, oc, ge := ss.TrialStats(false)
+			if ss.AlphaCycle == ts.OutStep() {
+				osse, outcosdiff, outgoalerr = ou, oc, ge
+				ss.SeqAct()
+				if ss.AlphaCycle < ts.MotStep() {
+					ss.DelayCycs()
+				}
+			}
+			if ss.AlphaCycle == ts.MotStep() {
+				msse, motcosdiff = ms, mc
+			}
+		}
+		ss.SetGoalGate(false)
+		ss.SeqEnvStep()
+	}
+	if ss.SeqOn() {
+		outgoalerr = !ss.SeqSuccess()
+	}
+	ss.SeqStep = 0
+	ss.CarryStore()
+	ss.AlphaCycle = 0
+	if logs {
+		ss.LogTstTrlAct(row, tact, outgoalerr)
+		ss.RecActs(env, row, false)
+		ss.PlotTimecourse()
+	}
+	if ss.OnTrialEnd != nil {
+		ss.OnTrialEnd(ss, env, row)
+	}
+	env.Step()
+	return
+}
+
+// TestStats are the average stats of one pass through the items of an
+// environment with learning off, computed by TestItems
+type TestStats struct {
+	MotSSE          float32 `desc:"average sum squared error - motor layer"`
+	OutSSE          float32 `desc:"average sum squared error - outcome layer"`
+	MotCosDiff      float32 `desc:"average cosine difference - motor layer"`
+	OutCosDiff      float32 `desc:"average cosine difference - outcome layer"`
+	OutGoalPctErr   float32 `desc:"proportion of trials where Outcome did not match Goal"`
+	OutPredPctErr   float32 `desc:"proportion of trials that had Outcome SSE > 0"`
+	OutClassPctCor  float32 `desc:"forced-choice accuracy of the Outcome minus phase activation"`
+	GoalClassPctCor float32 `desc:"Goal readout accuracy of the Goal minus phase activation"`
+	MaintCos        float32 `desc:"Goal maintenance fidelity, if GoalMaint"`
+	SeqPctCor       float32 `desc:"proportion of action sequences that reached their goal, if SeqOn"`
+	PerseverPct     float32 `desc:"proportion of trials with a perseveration error"`
+	MotEntropy      float32 `desc:"entropy (bits) of the distribution of actions decoded from Motor"`
+}
+
+// TestAll runs through one epoch of the testing items of given environment,
+// recording the average stats in the Tst* fields, and the test logs and plots
+func (ss *Sim) TestAll(env *Env) {
+	ts := ss.TestItems(env, true)
+	if ts == nil {
+		return
+	}
+	ss.TstMotSSE = ts.MotSSE
+	ss.TstOutSSE = ts.OutSSE
+	ss.TstMotCosDiff = ts.MotCosDiff
+	ss.TstOutCosDiff = ts.OutCosDiff
+	ss.TstOutGoalPctErr = ts.OutGoalPctErr
+	ss.TstOutPredPctErr = ts.OutPredPctErr
+	ss.TstOutClassPctCor = ts.OutClassPctCor
+	ss.TstGoalClassPctCor = ts.GoalClassPctCor
+	ss.TstMaintCos = ts.MaintCos
+	if ss.SeqOn() {
+		ss.TstSeqPctCor = ts.SeqPctCor
+	}
+	ss.TstPerseverPct = ts.PerseverPct
+	ss.TstMotEntropy = ts.MotEntropy
+	ss.FlushView()
+	ss.DBLogTstTrls()
+	ss.PlotConfMat()
+	ss.PlotClust()
+}
+
+// TestItems runs through one epoch of the items of given environment with
+// learning off, and returns their average stats -- nil if there are none.
+// If logs, the trials are recorded in the test logs: TstTrlLog, TstGrpLog,
+// the confusion matrix, OutReps, participation and activation recordings
+// -- else (Validate) none of these are touched.
+func (ss *Sim) TestItems(env *Env, logs bool) *TestStats {
+	et := env.Table
+	nr := env.NumRows()
+	if nr == 0 {
+		return nil
+	}
+	var msse, osse, mcd, ocd float32
+	var gerr, perr, ccor, gccor int
+	if logs {
+		ss.ConfMatReset()
+		ss.OutDecoder.InitFromTable(et, "Outcome")
+		ss.TstTrlLog.SetNumRows(0)
+		ss.ResetOutReps()
+		ss.ResetPartCnts()
+	}
+	ss.TstActCnts = make([]float32, ss.NActs())
+	cbuf, pact := ss.CarryBuf, ss.PrevAct
+	ss.CarryReset()
+	defer func() { ss.CarryBuf, ss.PrevAct = cbuf, pact }()
+	ss.TstPerseverCnt = 0
+	var grps []string
+	gsums := map[string]*GroupSums{}
+	env.Init(nil)
+	for trl := 0; trl < nr; trl++ {
+		grp := ItemGroup(et, env.Row())
+		gs, has := gsums[grp]
+		if !has {
+			gs = &GroupSums{}
+			gsums[grp] = gs
+			grps = append(grps, grp)
+		}
+		row := env.Row()
+		ms, ou, mc, oc, ge := ss.TestTrial(env, logs)
+		if logs {
+			ss.RecOutRep(et, row)
+			ss.PartCntAdd()
+		}
+		if ss.OutClassCor {
+			ccor++
+		}
+		if ss.GoalClassCor {
+			gccor++
+		}
+		msse += ms
+		osse += ou
+		mcd += mc
+		ocd += oc
+		gs.N++
+		gs.MotSSE += ms
+		gs.OutSSE += ou
+		gs.MotCDiff += mc
+		gs.OutCDiff += oc
+		if ge {
+			gerr++
+			gs.GoalErr++
+		}
+		if ou != 0 {
+			perr++
+			gs.PredErr++
+		}
+	}
+	np := float32(nr)
+	ts := &TestStats{}
+	ts.MotSSE = msse / np
+	ts.OutSSE = osse / np
+	ts.MotCosDiff = mcd / np
+	ts.OutCosDiff = ocd / np
+	ts.OutGoalPctErr = float32(gerr) / np
+	if ss.SeqOn() {
+		ts.SeqPctCor = 1 - ts.OutGoalPctErr
+	}
+	ts.OutPredPctErr = float32(perr) / np
+	ts.OutClassPctCor = float32(ccor) / np
+	ts.GoalClassPctCor = float32(gccor) / np
+	ts.PerseverPct = float32(ss.TstPerseverCnt) / np
+	ts.MotEntropy = Entropy(ss.TstActCnts)
+	if logs {
+		ss.LogTstGrps(grps, gsums)
+		ss.LogParticipation()
+	}
+	ts.MaintCos = ss.MaintTest(et)
+	return ts
+}
+
+// Validate runs TestItems on the ValEnv -- the ValReps validation patterns,
+// or the held-out validation split of the ExtReps -- without the test logs
+// and plots, and sets the Val* stats, logged by the next LogEpoch
+func (ss *Sim) Validate() {
+	if ss.ValEnv.NumRows() == 0 {
+		log.Println("Validate: no validation items -- need a ValRepsFile or SplitOn")
+		return
+	}
+	ts := ss.TestItems(&ss.ValEnv, false)
+	ss.ValMotSSE = ts.MotSSE
+	ss.ValOutSSE = ts.OutSSE
+	ss.ValMotCosDiff = ts.MotCosDiff
+	ss.ValOutCosDiff = ts.OutCosDiff
+	ss.ValOutGoalPctErr = ts.OutGoalPctErr
+	ss.ValOutPredPctErr = ts.OutPredPctErr
+	ss.ValOutClassPctCor = ts.OutClassPctCor
+	ss.ValGoalClassPctCor = ts.GoalClassPctCor
+	ss.ValMaintCos = ts.MaintCos
+	ss.ValSeqPctCor = ts.SeqPctCor
+	ss.ValPerseverPct = ts.PerseverPct
+	ss.ValMotEntropy = ts.MotEntropy
+	ss.ValDone = true
+}
+
+//////////////////////////////////////////////////////////
+// Config methods
+
+// ConfigNet sets up the network prior to running
+func (ss *Sim) ConfigNet() {
+	net := ss.Net
+	net.InitName(net, "GoalGuyNet")
+	contextLay := net.AddLayer2D("Context", 5, 5, emer.Input)
+	goalLay := net.AddLayer2D("Goal", 5, 5, emer.Hidden)
+	motorLay := ss.AddOutLayer(net, "Motor", emer.Hidden)
+	outcomeLay := ss.AddOutLayer(net, "Outcome", emer.Target)
+
+	// BELOW for reference only:
+	//hid2Lay := net.AddLayer4D("Hidden2", 2, 4, 3, 2, emer.Hidden) // outerY, X, innerY, X
+	// AND: use this to position layers relative to each other
+	// default is Above, YAlign = Front, XAligh = Center
+	//hid2Lay.SetRelPos(relpos.Rel{Rel: relpos.RightOf, Other: "Hidden1", YAlign: relpos.Front, Space: 2})
+
+	contextLay.SetRelPos(relpos.Rel{Rel: relpos.Above, Other: "Motor", YAlign: relpos.Front, Space: 2})
+	outcomeLay.SetRelPos(relpos.Rel{Rel: relpos.RightOf, Other: "Motor", YAlign: relpos.Front, Space: 2})
+	goalLay.SetRelPos(relpos.Rel{Rel: relpos.RightOf, Other: "Context", YAlign: relpos.Front, Space: 2})
+
+	net.ConnectLayers(contextLay, goalLay, ss.PathPat("Context:Goal"), emer.Forward)
+	net.ConnectLayers(goalLay, motorLay, ss.PathPat("Goal:Motor"), emer.Forward)
+	net.ConnectLayers(motorLay, outcomeLay, ss.PathPat("Motor:Outcome"), emer.Forward)
+	// Trying weaker inputs to Outcome layer - did NOT seem to help...
+	//net.ConnectLayers(motorLay, outcomeLay, prjn.NewFull(), emer.Lateral)
+
+	if ss.HasOutMotBack() {
+		net.ConnectLayers(outcomeLay, motorLay, ss.PathPat("Outcome:Motor"), emer.Back)
+	}
+	ss.ConnectNetVariant(net)
+
+	if ss.GoalMaint {
+		net.ConnectLayers(goalLay, goalLay, prjn.NewOneToOne(), emer.Lateral)
+	}
+
+	if ss.DriveOn {
+		driveLay := net.AddLayer2D("Drive", 1, len(ss.DriveNames), emer.Input)
+		driveLay.SetRelPos(relpos.Rel{Rel: relpos.Above, Other: "Context", YAlign: relpos.Front, Space: 2})
+		net.ConnectLayers(driveLay, goalLay, prjn.NewFull(), emer.Forward)
+	}
+	if ss.ValenceOn {
+		usvLay := net.AddLayer2D("USValence", 1, 2, emer.Input)
+		usvLay.SetRelPos(relpos.Rel{Rel: relpos.RightOf, Other: "Outcome", YAlign: relpos.Front, Space: 2})
+		net.ConnectLayers(usvLay, motorLay, prjn.NewFull(), emer.Forward)
+	}
+	if ss.CriticOn {
+		criticLay := net.AddLayer2D("Critic", 1, 1, emer.Target)
+		criticLay.SetRelPos(relpos.Rel{Rel: relpos.RightOf, Other: "Goal", YAlign: relpos.Front, Space: 2})
+		net.ConnectLayers(contextLay, criticLay, prjn.NewFull(), emer.Forward)
+		net.ConnectLayers(goalLay, criticLay, prjn.NewFull(), emer.Forward)
+	}
+	// if Thread {
+	// 	motorLay.SetThread(1)
+	// 	outcomeLay.SetThread(1)
+	// }
+
+	net.Defaults()
+	net.StyleParams(ss.Params, true) // set msg
+	ss.StylePoolParams()
+	net.Build()
+	ss.ApplyPrjnLrns()
+	ss.InitWts()
+	ss.ConfigInhib()
+}
+
+// ConfigExtReps creates a new version of the ExtReps table and writes it to
+// permanent storage as the ExtRepsFile in the local directory
+func (ss *Sim) ConfigExtReps() {
+	ss.GenExtReps(ss.ExtReps, 25) // 250
+	if err := SaveTable(ss.ExtReps, ExtRepsFile); err != nil {
+		log.Println(err)
+	}
+}
+
+// GenExtReps generates n random items into given table, with the ExtReps schema
+func (ss *Sim) GenExtReps(et *etable.Table, n int) {
+	oshp, onms := ss.OutShape()
+	et.SetFromSchema(etable.Schema{
+		{"Name", etensor.STRING, nil, nil},
+		{"Context", etensor.FLOAT32, []int{5, 5}, []string{"Y", "X"}},
+		{"Goal", etensor.FLOAT32, []int{5, 5}, []string{"Y", "X"}},
+		{"Motor", etensor.FLOAT32, oshp, onms},
+		{"Outcome", etensor.FLOAT32, oshp, onms},
+		{"Freq", etensor.FLOAT32, nil, nil},
+		{"Valence", etensor.FLOAT32, nil, nil},
+		{"Group", etensor.STRING, nil, nil},
+		{"MotorTarg", etensor.FLOAT32, oshp, onms},
+		{"Valid", etensor.STRING, nil, nil},
+	}, n)
+
+	ss.GenCtxtPats(et.Cols[1])
+	patgen.PermutedBinaryRows(et.Cols[2], 0, 0, 0)
+	patgen.PermutedBinaryRows(et.Cols[3], 0, 0, 0)
+	ss.GenOutPats(et.Cols[4])
+	ss.GenMapping(et)
+	for i := 0; i < et.NumRows(); i++ {
+		et.ColByName("Freq").SetFloat1D(i, 1) // relative frequency for FreqWeighted order
+	}
+	ss.GenValence(et)
+	GenGroups(et)
+	mt, oc := et.ColByName("MotorTarg"), et.ColByName("Outcome")
+	for i := 0; i < mt.Len(); i++ {
+		mt.SetFloat1D(i, oc.FloatVal1D(i)) // maps onto the Outcome through one-to-one Motor -> Outcome
+	}
+	if et == ss.ExtReps {
+		ss.UpdtPatsHash()
+	}
+}
+
+// OpenExtReps opens an existing (permanent) .tsv version of the ExtReps file
+func (ss *Sim) OpenExtReps() {
+	et := ss.ExtReps
+	err := OpenTable(et, ExtRepsFile)
+	if err != nil {
+		log.Println(err)
+	}
+	ss.UpdtPatsHash()
+}
+
+// OpenValReps opens the ValReps validation patterns from the ValRepsFile,
+// if it exists -- else ValReps is left empty, and validation uses the
+// held-out split of the ExtReps (see ConfigEnvs)
+func (ss *Sim) OpenValReps() {
+	et := ss.ValReps
+	if _, err := os.Stat(ValRepsFile); os.IsNotExist(err) {
+		et.SetNumRows(0)
+		return
+	}
+	err := OpenTable(et, ValRepsFile)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// ConfigEpcLog sets up the EpcLog table
+func (ss *Sim) ConfigEpcLog() {
+	et := ss.EpcLog
+	sc := etable.Schema{
+		{"Epoch", etensor.INT64, nil, nil},
+		{"Phase", etensor.STRING, nil, nil},
+		{"MotSSE", etensor.FLOAT32, nil, nil},
+		{"OutSSE", etensor.FLOAT32, nil, nil},
+
+		{"MotAvgSSE", etensor.FLOAT32, nil, nil},
+		{"OutAvgSSE", etensor.FLOAT32, nil, nil},
+
+		{"OutGoalPctErr", etensor.FLOAT32, nil, nil},
+		{"OutPredPctErr", etensor.FLOAT32, nil, nil},
+
+		{"OutGoalPctCor", etensor.FLOAT32, nil, nil},
+		{"OutPredPctCor", etensor.FLOAT32, nil, nil},
+
+		{"MotCosDiff", etensor.FLOAT32, nil, nil},
+		{"OutCosDiff", etensor.FLOAT32, nil, nil},
+		{"OutGoalCos", etensor.FLOAT32, nil, nil},
+		{"OutPatPctErr", etensor.FLOAT32, nil, nil},
+		{"OutValidPctCor", etensor.FLOAT32, nil, nil},
+		{"OutPatCos", etensor.FLOAT32, nil, nil},
+		{"OutClassPctCor", etensor.FLOAT32, nil, nil},
+		{"GoalClassPctCor", etensor.FLOAT32, nil, nil},
+		{"WtUpdts", etensor.INT64, nil, nil},
+		{"WtDecay", etensor.FLOAT32, nil, nil},
+		{"MaxDWt", etensor.FLOAT32, nil, nil},
+		{"MeanDWt", etensor.FLOAT32, nil, nil},
+		{"TracePctCommit", etensor.FLOAT32, nil, nil},
+		{"CriticV", etensor.FLOAT32, nil, nil},
+		{"TDErr", etensor.FLOAT32, nil, nil},
+		{"SeqPctCor", etensor.FLOAT32, nil, nil},
+		{"ContingErr", etensor.FLOAT32, nil, nil},
+		{"DegradActPct", etensor.FLOAT32, nil, nil},
+		{"ExtinctActPct", etensor.FLOAT32, nil, nil},
+		{"ExtinctMotAct", etensor.FLOAT32, nil, nil},
+		{"ApproachPct", etensor.FLOAT32, nil, nil},
+		{"AvoidPct", etensor.FLOAT32, nil, nil},
+		{"MotEntropy", etensor.FLOAT32, nil, nil},
+		{"PerseverPct", etensor.FLOAT32, nil, nil},
+
+		{"ContextActAvg", etensor.FLOAT32, nil, nil},
+		{"GoalActAvg", etensor.FLOAT32, nil, nil},
+		{"MotorActAvg", etensor.FLOAT32, nil, nil},
+		{"OutActAvg", etensor.FLOAT32, nil, nil},
+
+		{"OutPredCntErr", etensor.FLOAT32, nil, nil},
+		{"OutGoalCntErr", etensor.FLOAT32, nil, nil},
+
+		{"ValMotSSE", etensor.FLOAT32, nil, nil},
+		{"ValOutSSE", etensor.FLOAT32, nil, nil},
+		{"ValMotCosDiff", etensor.FLOAT32, nil, nil},
+		{"ValOutCosDiff", etensor.FLOAT32, nil, nil},
+		{"ValOutGoalPctErr", etensor.FLOAT32, nil, nil},
+		{"ValOutPredPctErr", etensor.FLOAT32, nil, nil},
+		{"ValOutClassPctCor", etensor.FLOAT32, nil, nil},
+		{"ValGoalClassPctCor", etensor.FLOAT32, nil, nil},
+		{"ValMaintCos", etensor.FLOAT32, nil, nil},
+		{"ValSeqPctCor", etensor.FLOAT32, nil, nil},
+		{"ValMotEntropy", etensor.FLOAT32, nil, nil},
+		{"ValPerseverPct", etensor.FLOAT32, nil, nil},
+	}
+	sc = append(sc, ss.ItiSchema()...)
+	sc = append(sc, ss.SmoothSchema()...)
+	et.SetFromSchema(sc, 0)
+	//ss.PlotVals = []string{"OutSSE", "Out Goal Pct Err"}
+	ss.PlotVals = []string{"OutCosDiff", "MotCosDiff", "OutGoalPctErr"}
+	ss.Plot = true
+}
+
+// PlotEpcLog plots given epoch log using PlotVals Y axis
+// columns into EpcPlotSvg
+func (ss *Sim) PlotEpcLog() *plot.Plot {
+	ss.ProfStart(ProfGUI)
+	defer ss.ProfStop(ProfGUI)
+	if ss.EpcPlotSvg == nil || !ss.EpcPlotSvg.IsVisible() {
+		return nil
+	}
+	plt := ss.EpcPlot()
+	//eplot.PlotViewSVG(plt, ss.EpcPlotSvg, 5, 5, 2)
+	eplot.PlotViewSVG(plt, ss.EpcPlotSvg, 5)
+	return plt
+}
+
+// EpcPlot returns the plot of the PlotVals columns of the EpcLog
+func (ss *Sim) EpcPlot() *plot.Plot {
+	return ss.EpcColsPlot("Goal Guy Epoch Log", ss.PlotVals)
+}
+
+// EpcColsPlot returns the plot of given columns of the EpcLog, with given title
+func (ss *Sim) EpcColsPlot(title string, cols []string) *plot.Plot {
+	et := ss.EpcLog
+	plt := NewPlot() // todo: keep around?
+	plt.Title.Text = title
+	plt.X.Label.Text = "Epoch"
+	plt.Y.Label.Text = "Y"
+
+	for i, cl := range cols {
+		xy, _ := eplot.NewTableXYNames(et, "Epoch", cl)
+		l, _ := plotter.NewLine(xy)
+		l.LineStyle.Width = vg.Points(CurPlotStyle.LineWidth)
+		l.LineStyle.Color = CurPlotStyle.Color(i)
+		plt.Add(l)
+		plt.Legend.Add(cl, l)
+	}
+	ss.PlotPhaseMarks(plt)
+	plt.Legend.Top = true
+	return plt
+}
+
+// SaveEpcPlot plots given epoch log using PlotVals Y axis columns and saves to .svg file
+func (ss *Sim) SaveEpcPlot(fname string) error {
+	return ss.EpcPlot().Save(5, 5, fname)
+}
+
+// AddPlotTab adds a new svg.Editor tab with given label to the tab view,
+// for displaying plots, sized relative to the window width, height
+func AddPlotTab(tv *gi.TabView, label string, width, height int) *svg.Editor {
+	svge := tv.AddNewTab(svg.KiT_Editor, label).(*svg.Editor)
+	svge.InitScale()
+	svge.Fill = true
+	svge.SetProp("background-color", CurPlotStyle.Background)
+	svge.SetProp("width", units.NewValue(float32(width/2), units.Px))
+	svge.SetProp("height", units.NewValue(float32(height-100), units.Px))
+	svge.SetStretchMaxWidth()
+	svge.SetStretchMaxHeight()
+	return svge
+}
+
+// ConfigGui configures the GoGi gui interface for this simulation,
+func (ss *Sim) ConfigGui() *gi.Window {
+	width := 1600
+	height := 1200
+
+	gi.SetAppName("goal-guy-0")
+	gi.SetAppAbout(`This demonstrates learning of basic goal-directed behavior. See <a href="https://github.com/emer/emergent">emergent on GitHub</a>.</p>`)
+
+	sess := ss.OpenSessionFile()
+	stdPix := true
+	if sess != nil && sess.Width > 0 && sess.Height > 0 {
+		width, height = sess.Width, sess.Height
+		stdPix = false // saved in actual pixels
+	}
+	win := gi.NewWindow2D("goal-guy-0", "Goal Guy Phase 0", width, height, stdPix)
+
+	vp := win.WinViewport2D()
+	updt := vp.UpdateStart()
+
+	mfr := win.SetMainFrame()
+
+	tbar := gi.AddNewToolBar(mfr, "tbar")
+	tbar.SetStretchMaxWidth()
+
+	split := gi.AddNewSplitView(mfr, "split")
+	split.Dim = gi.X
+	// split.SetProp("horizontal-align", "center")
+	// split.SetProp("margin", 2.0) // raw numbers = px = 96 dpi pixels
+	split.SetStretchMaxWidth()
+	split.SetStretchMaxHeight()
+
+	ss.ConfigCtrlPanel(split, vp)
+
+	tv := gi.AddNewTabView(split, "tv")
+
+	ss.NetViews = nil
+	for _, vr := range ss.NetViewVars {
+		ss.AddNetView(tv, vr)
+	}
+
+	ss.EpcPlotSvg = AddPlotTab(tv, "Epc Plot", width, height)
+	ss.ConfMatSvg = AddPlotTab(tv, "Conf Mat", width, height)
+	ss.CtxtRFSvg = AddPlotTab(tv, "Ctxt RFs", width, height)
+	ss.GoalRFSvg = AddPlotTab(tv, "Goal RFs", width, height)
+	ss.WtGridSvg = AddPlotTab(tv, "Wt Grid", width, height)
+	ss.WtDiffSvg = AddPlotTab(tv, "Wt Diffs", width, height)
+	ss.TcSvg = AddPlotTab(tv, "Timecourse", width, height)
+	ss.OutClustSvg = AddPlotTab(tv, "Out Clust", width, height)
+	ss.PatClustSvg = AddPlotTab(tv, "Pat Clust", width, height)
+	ss.ConfigProbeTab(tv, vp)
+	ss.ConfigPatEditTab(tv, vp)
+	ss.ConfigParamsTab(tv, vp)
+	ss.ConfigConsoleTab(tv, vp)
+	ss.ConfigCmpTab(tv, vp, width, height)
+	ss.ConfigScaleTab(tv, vp)
+
+	split.SetSplits(.3, .7)
+	if sess != nil {
+		if len(sess.Splits) == 2 {
+			split.SetSplits(sess.Splits...)
+		}
+		if sess.Tab != "" {
+			tv.SelectTabByName(sess.Tab)
+		}
+	}
+
+	tbar.AddAction(gi.ActOpts{Label: "Init", Icon: "update"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			ss.Init()
+			vp.FullRender2DTree()
+		})
+
+	var pauseAct *gi.Action
+	tbar.AddAction(gi.ActOpts{Label: "Train", Icon: "run"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			if ss.Paused {
+				ss.Resume()
+				pauseAct.SetText("Pause")
+				return
+			}
+			go ss.Train()
+		})
+
+	pauseAct = tbar.AddAction(gi.ActOpts{Label: "Pause", Icon: "stop"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			if ss.Paused {
+				ss.Resume()
+				pauseAct.SetText("Pause")
+			} else {
+				ss.Pause()
+				pauseAct.SetText("Resume (Paused)")
+			}
+			ss.UpdateCtrlPanel()
+			vp.FullRender2DTree()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Stop", Icon: "stop"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			ss.Stop()
+			pauseAct.SetText("Pause")
+			vp.FullRender2DTree()
+		})
+
+	tbar.AddSeparator("text")
+	tbar.AddSeparator("text")
+
+	tbar.AddAction(gi.ActOpts{Label: "Step Trial", Icon: "step-fwd"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			ss.TrainTrial()
+			ss.FlushView()
+			vp.FullRender2DTree()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Step Epoch", Icon: "fast-fwd"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			ss.TrainEpoch()
+			vp.FullRender2DTree()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Train More", Icon: "fast-fwd"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			go ss.TrainNEpochs(ss.MoreEpcs)
+		})
+
+	moreSb := gi.AddNewSpinBox(tbar, "more-epcs")
+	moreSb.SetMin(1)
+	moreSb.SetStep(1)
+	moreSb.SetValue(float32(ss.MoreEpcs))
+	moreSb.SpinBoxSig.Connect(win.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		ss.MoreEpcs = int(moreSb.Value)
+		ss.UpdateCtrlPanel()
+	})
+
+	// tbar.AddSep("file")
+	tbar.AddSeparator("text")
+	tbar.AddSeparator("text")
+
+	tbar.AddAction(gi.ActOpts{Label: "Test Trial", Icon: "step-fwd"}, win.This(),
+		func(rev, send ki.Ki, sig int64, data interface{}) {
+			ss.TestTrial(&ss.TestEnv, true)
+			ss.FlushView()
+			vp.FullRender2DTree()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Test All", Icon: "step-fwd"}, win.This(),
+		func(rev, send ki.Ki, sig int64, data interface{}) {
+			ss.TestAll(&ss.TestEnv)
+			vp.FullRender2DTree()
+		})
+
+	tbar.AddSeparator("text")
+	tbar.AddSeparator("text")
+
+	tbar.AddAction(gi.ActOpts{Label: "Tune Gi", Icon: "update"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			if err := ss.TuneGi(); err != nil {
+				log.Println(err)
+			}
+			vp.FullRender2DTree()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Devalue", Icon: "run"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			go ss.RunDeval()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Run Pipeline", Icon: "run"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			go func() {
+				if err := ss.RunPipeline(""); err != nil {
+					log.Println(err)
+				}
+			}()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Run Protocol", Icon: "run"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			go func() {
+				if err := ss.RunProtocol(ss.Protocol); err != nil {
+					log.Println(err)
+				}
+			}()
+		})
+
+	tbar.AddSeparator("text")
+	tbar.AddSeparator("text")
+
+	tbar.AddAction(gi.ActOpts{Label: "Add NetView", Icon: "new"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			ss.AddNetView(tv, "PhaseDif")
+			vp.FullRender2DTree()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Pop Out Tab", Icon: "new"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			if err := ss.DetachTab(tv, width, height); err != nil {
+				log.Println(err)
+			}
+			vp.FullRender2DTree()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Dock All", Icon: "reset"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			ss.DockAll()
+			vp.FullRender2DTree()
+		})
+
+	var updtAct *gi.Action
+	updtAct = tbar.AddAction(gi.ActOpts{Label: fmt.Sprintf("View Updt: %v", ss.TrainUpdt), Icon: "update"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			updtAct.SetText(fmt.Sprintf("View Updt: %v", ss.NextViewUpdt()))
+			ss.UpdateCtrlPanel()
+			vp.FullRender2DTree()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Epoch Plot", Icon: "update"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			ss.PlotEpcLog()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Dark Plots", Icon: "update"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			ToggleDarkPlots()
+			ss.PlotEpcLog()
+			ss.UpdateCtrlPanel()
+			vp.FullRender2DTree()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Act RFs", Icon: "update"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			ss.PlotActRFs()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Reset RFs", Icon: "reset"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			ss.ResetActRFs()
+			ss.PlotActRFs()
+		})
+
+	tbar.AddSeparator("text")
+	tbar.AddSeparator("text")
+
+	tbar.AddAction(gi.ActOpts{Label: "Save Wts", Icon: "file-save"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			ss.SaveWts("goal_guy_0_net_trained.wts") // todo: call method to prompt
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Compare Wts", Icon: "file-open"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			if err := ss.CompareWts(ss.CmpWtsA, ss.CmpWtsB); err != nil {
+				log.Println(err)
+				return
+			}
+			ss.ReportWtDiffs(ss.CmpWtsA, ss.CmpWtsB)
+			ss.PlotWtDiffs()
+			vp.FullRender2DTree()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Save NetView", Icon: "file-save"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			if err := ss.SaveNetViewImage("goal_guy_0_netview.png"); err != nil {
+				log.Println(err)
+			}
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Record Anim", Icon: "file-save"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			go func() {
+				if err := ss.RecordTrialAnim(ss.AnimRow); err != nil {
+					log.Println(err)
+					return
+				}
+				if err := ss.SaveAnim(ss.AnimFile); err != nil {
+					log.Println(err)
+				}
+			}()
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Save Clust", Icon: "file-save"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			if _, err := ss.SaveClustPlots("."); err != nil {
+				log.Println(err)
+			}
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Extract Policy", Icon: "file-save"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			if err := ss.SavePolicy("goal_guy_0_policy.tsv"); err != nil {
+				log.Println(err)
+			}
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Save ActRec", Icon: "file-save"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			if err := ss.SaveActRec("goal_guy_0_actrec.arrow"); err != nil {
+				log.Println(err)
+			}
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Save Order", Icon: "file-save"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			if err := ss.SaveOrderHist("goal_guy_0_order_hist.tsv"); err != nil {
+				log.Println(err)
+			}
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Save Log", Icon: "file-save"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			if err := SaveTable(ss.EpcLog, "goal_guy_0_epc.tsv"); err != nil {
+				log.Println(err)
+			}
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Save Plot", Icon: "file-save"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			if err := ss.SaveEpcPlot("goal_guy_0_cur_epc_plot.svg"); err != nil {
+				log.Println(err)
+			}
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Save Plot Style", Icon: "file-save"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			fnm := ss.PlotStyleFile
+			if fnm == "" {
+				fnm = "goal_guy_0_plot_style.json"
+			}
+			if err := SavePlotStyle(&CurPlotStyle, fnm); err != nil {
+				log.Println(err)
+			}
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Save Params", Icon: "file-save"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			// todo: need save / load methods for these
+			// SaveTable(ss.EpcLog, "goal_guy_0_params.tsv")
+		})
+
+	tbar.AddAction(gi.ActOpts{Label: "Export Bundle", Icon: "file-save"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			if err := ss.ExportRunBundle(""); err != nil {
+				log.Println(err)
+			}
+		})
+
+	tbar.AddSeparator("text")
+	tbar.AddSeparator("text")
+
+	tbar.AddAction(gi.ActOpts{Label: "New Seed", Icon: "new"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			ss.NewRndSeed()
+		})
+
+	tbar.AddSeparator("text")
+	tbar.AddSeparator("text")
+
+	gi.AddNewLabel(tbar, "expt-lbl", "Expt:")
+	excb := gi.AddNewComboBox(tbar, "expt")
+	excb.ItemsFromStringList(ExptNames(), false, 0)
+	if ss.Expt != "" {
+		excb.SetCurVal(ss.Expt)
+	}
+	excb.ComboSig.Connect(win.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		cb := send.(*gi.ComboBox)
+		nm, _ := cb.CurVal.(string)
+		if err := ss.SetExpt(nm); err != nil {
+			log.Println(err)
+			return
+		}
+		ss.ReConfig()
+		vp.FullRender2DTree()
+	})
+
+	vp.UpdateEndNoSig(updt)
+
+	// main menu
+	appnm := gi.AppName()
+	mmen := win.MainMenu
+	mmen.ConfigMenus([]string{appnm, "File", "Edit", "Window"})
+
+	amen := win.MainMenu.ChildByName(appnm, 0).(*gi.Action)
+	amen.Menu.AddAppMenu(win)
+
+	emen := win.MainMenu.ChildByName("Edit", 1).(*gi.Action)
+	emen.Menu.AddCopyCutPaste(win)
+
+	// note: Command in shortcuts is automatically translated into Control for
+	// Linux, Windows or Meta for MacOS
+	// fmen := win.MainMenu.ChildByName("File", 0).(*gi.Action)
+	// fmen.Menu.AddAction(gi.ActOpts{Label: "Open", Shortcut: "Command+O"},
+	// 	win.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+	// 		FileViewOpenSVG(vp)
+	// 	})
+	// fmen.Menu.AddSeparator("csep")
+	// fmen.Menu.AddAction(gi.ActOpts{Label: "Close Window", Shortcut: "Command+W"},
+	// 	win.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+	// 		win.Close()
+	// 	})
+
+	win.SetCloseCleanFunc(func(w *gi.Window) {
+		if err := ss.SaveSessionFile(w, split, tv); err != nil {
+			log.Println(err)
+		}
+		go gi.Quit() // once main window is closed, quit
+	})
+
+	win.MainMenuUpdated()
+	return win
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// smooth.go adds smoothed versions of the key epoch stats to the EpcLog,
+// because the raw curves from 25 patterns are too noisy to judge
+// convergence.  For each stat named in SmoothVals there are two extra
+// columns, both computed in LogEpoch and plottable via PlotVals:
+//
+//	<stat>Roll   average of the stat over the last SmoothWin epochs
+//	<stat>Ewma   exponentially-weighted moving average, with the same
+//	             center of mass as a SmoothWin window: a = 2 / (SmoothWin+1)
+//
+// Both are computed from the running SmoothState of each stat, updated once
+// per epoch, and reset at Init -- not from the EpcLog rows, which do not
+// hold every epoch if EpcLogEvery > 1 (or the log is streamed to a file).
+
+import (
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// SmoothState is the running smoothing state of one of the SmoothVals
+type SmoothState struct {
+	Win  []float64 `desc:"values of the last SmoothWin epochs, oldest first"`
+	Ewma float64   `desc:"exponentially-weighted moving average up to the last epoch"`
+	N    int       `desc:"number of epochs so far"`
+}
+
+// Add adds the value of the next epoch, keeping the last win values, and
+// returns the rolling average and EWMA (with given weight a) including it
+func (sm *SmoothState) Add(val float64, win int, a float64) (roll, ewma float64) {
+	sm.Win = append(sm.Win, val)
+	if len(sm.Win) > win {
+		sm.Win = sm.Win[len(sm.Win)-win:]
+	}
+	sum := 0.0
+	for _, v := range sm.Win {
+		sum += v
+	}
+	if sm.N == 0 {
+		sm.Ewma = val
+	} else {
+		sm.Ewma = a*val + (1-a)*sm.Ewma
+	}
+	sm.N++
+	return sum / float64(len(sm.Win)), sm.Ewma
+}
+
+// ResetSmooth resets the SmoothState of all the SmoothVals -- called in Init
+func (ss *Sim) ResetSmooth() {
+	ss.Smooth = map[string]*SmoothState{}
+}
+
+// SmoothSchema returns the EpcLog columns for the smoothed SmoothVals
+func (ss *Sim) SmoothSchema() etable.Schema {
+	var sc etable.Schema
+	for _, v := range ss.SmoothVals {
+		sc = append(sc, etable.Column{v + "Roll", etensor.FLOAT32, nil, nil})
+		sc = append(sc, etable.Column{v + "Ewma", etensor.FLOAT32, nil, nil})
+	}
+	return sc
+}
+
+// LogSmooth adds the current epoch's SmoothVals, from given row of the
+// EpcLog whose raw values must already be set, to their SmoothState, and
+// sets their smoothed values in the row -- called once per epoch at the end
+// of LogEpoch
+func (ss *Sim) LogSmooth(row int) {
+	et := ss.EpcLog
+	win := ss.SmoothWin
+	if win < 1 {
+		win = 1
+	}
+	a := 2 / float64(win+1)
+	if ss.Smooth == nil {
+		ss.ResetSmooth()
+	}
+	for _, v := range ss.SmoothVals {
+		col := et.ColByName(v)
+		if col == nil {
+			continue
+		}
+		sm, has := ss.Smooth[v]
+		if !has {
+			sm = &SmoothState{}
+			ss.Smooth[v] = sm
+		}
+		roll, ewma := sm.Add(col.FloatVal1D(row), win, a)
+		et.ColByName(v+"Roll").SetFloat1D(row, roll)
+		et.ColByName(v+"Ewma").SetFloat1D(row, ewma)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// split.go has the hold-out split of the ExtReps patterns into train,
+// validation and test subsets, stratified by item Group (see groups.go --
+// all the items are one group if there is no Group column) so each Group
+// is represented in each subset in the same proportions.  Stratifying by
+// the exact Outcome pattern would leave nothing to hold out with the
+// default one-to-one Context -> Outcome items.  If SplitOn, the
+// split is made at each Init (ConfigEnvs) and the row assignments are
+// written to SplitFile: the TrainEnv then presents the train items, the
+// ValEnv (used by Validate, every ValInterval epochs) the validation items
+// and the TestEnv (used by TestAll) the test items.
+
+import (
+	"log"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// Split names, as written to the SplitFile
+const (
+	SplitTrain = "train"
+	SplitVal   = "val"
+	SplitTest  = "test"
+)
+
+// SplitPatterns returns the assignment of each row of given table to
+// SplitTrain, SplitVal or SplitTest: within each Group of rows (ItemGroup),
+// a random valPct of them are assigned to SplitVal and tstPct to SplitTest
+// (rounded, keeping at least one row for SplitTrain), the rest to SplitTrain
+func SplitPatterns(et *etable.Table, valPct, tstPct float32) []string {
+	strata := map[string][]int{}
+	var keys []string
+	for row := 0; row < et.NumRows(); row++ {
+		key := ItemGroup(et, row)
+		if _, has := strata[key]; !has {
+			keys = append(keys, key)
+		}
+		strata[key] = append(strata[key], row)
+	}
+	sort.Strings(keys) // deterministic given the random seed
+	asgn := make([]string, et.NumRows())
+	for _, key := range keys {
+		rows := strata[key]
+		n := len(rows)
+		nv := int(math.Round(float64(valPct) * float64(n)))
+		nt := int(math.Round(float64(tstPct) * float64(n)))
+		for nv+nt >= n && nv+nt > 0 {
+			if nv >= nt {
+				nv--
+			} else {
+				nt--
+			}
+		}
+		for i, pi := range rand.Perm(n) {
+			row := rows[pi]
+			switch {
+			case i < nv:
+				asgn[row] = SplitVal
+			case i < nv+nt:
+				asgn[row] = SplitTest
+			default:
+				asgn[row] = SplitTrain
+			}
+		}
+	}
+	return asgn
+}
+
+// SplitRows returns a new table with the rows of given table assigned to
+// given split
+func SplitRows(et *etable.Table, asgn []string, split string) *etable.Table {
+	ix := etable.NewIdxView(et)
+	ix.Idxs = nil
+	for row, sp := range asgn {
+		if sp == split {
+			ix.Idxs = append(ix.Idxs, row)
+		}
+	}
+	return ix.NewTable()
+}
+
+// SaveSplit saves given row assignments to given .tsv file, as Row, Split
+func SaveSplit(asgn []string, fname string) error {
+	dt := &etable.Table{}
+	dt.SetFromSchema(etable.Schema{
+		{"Row", etensor.INT64, nil, nil},
+		{"Split", etensor.STRING, nil, nil},
+	}, len(asgn))
+	for row, sp := range asgn {
+		dt.ColByName("Row").SetFloat1D(row, float64(row))
+		dt.ColByName("Split").SetString1D(row, sp)
+	}
+	return SaveTable(dt, fname)
+}
+
+// SplitPats splits the ExtReps per SplitVal and SplitTest, saving the
+// assignments to SplitFile, and returns the train, validation and test
+// tables -- called in ConfigEnvs if SplitOn
+func (ss *Sim) SplitPats() (trn, val, tst *etable.Table) {
+	asgn := SplitPatterns(ss.ExtReps, ss.SplitValPct, ss.SplitTestPct)
+	if ss.SplitFile != "" {
+		if err := SaveSplit(asgn, ss.SplitFile); err != nil {
+			log.Println(err)
+		}
+	}
+	trn = SplitRows(ss.ExtReps, asgn, SplitTrain)
+	val = SplitRows(ss.ExtReps, asgn, SplitVal)
+	tst = SplitRows(ss.ExtReps, asgn, SplitTest)
+	if val.NumRows() == 0 && ss.SplitValPct > 0 {
+		log.Printf("SplitPats: the validation split of the %d ExtReps is empty -- too few items per Group for SplitValPct %g\n", ss.ExtReps.NumRows(), ss.SplitValPct)
+	}
+	if tst.NumRows() == 0 && ss.SplitTestPct > 0 {
+		log.Printf("SplitPats: the test split of the %d ExtReps is empty -- too few items per Group for SplitTestPct %g\n", ss.ExtReps.NumRows(), ss.SplitTestPct)
+	}
+	return
+}
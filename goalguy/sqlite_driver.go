@@ -0,0 +1,16 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build sqlite
+// +build sqlite
+
+package goalguy
+
+// sqlite_driver.go registers the (cgo) sqlite3 database/sql driver used by
+// the SQLite logging backend (sqlitelog.go) -- only built with -tags sqlite,
+// so the default build does not require cgo.
+
+import (
+	_ "github.com/mattn/go-sqlite3" // registers the sqlite3 driver
+)
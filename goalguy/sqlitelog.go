@@ -0,0 +1,259 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// sqlitelog.go is the optional SQLite logging backend: if DBFile is set,
+// the logs are also written to tables of that single .db file, so results
+// can be queried across hundreds of runs with SQL:
+//
+//	runs     one row per run (Init): run_id, start time, Expt, RndSeed,
+//	         PatsHash, Params (JSON), and at the end of Train the RunLog
+//	         summary: Epochs, FirstZero, LastZero, NZero, Halt
+//	epochs   each EpcLog row, with its run_id
+//	trials   each TstTrlLog row at the end of each TestAll, with its run_id
+//	         and Epoch
+//
+// The epochs and trials tables have a column per scalar column of the
+// etable.Table -- columns added by later configs (e.g., ItiLog) are added to
+// the existing tables.  Rows are written as they are logged, so the db is
+// complete up to the point a run stops.  Runs of a sweep can share one db.
+//
+// The sqlite3 driver needs cgo, so it is only built in with -tags sqlite
+// (see sqlite_driver.go) -- otherwise OpenDB returns an error if DBFile is
+// set.
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// DBDriver is the database/sql driver of the SQLite logging backend
+const DBDriver = "sqlite3"
+
+// HasDBDriver returns whether the DBDriver is built in (-tags sqlite)
+func HasDBDriver() bool {
+	for _, d := range sql.Drivers() {
+		if d == DBDriver {
+			return true
+		}
+	}
+	return false
+}
+
+// DBBusyMs is the time in msec to wait for the db to be unlocked by other
+// runs writing to it, e.g., other sims running at the same time
+var DBBusyMs = 10000
+
+// DefaultDBFile is the DBFile of new Sims -- set by the -db flag
+var DefaultDBFile = ""
+
+// SQLType returns the SQLite type of given etensor type
+func SQLType(typ etensor.Type) string {
+	switch typ {
+	case etensor.STRING:
+		return "TEXT"
+	case etensor.INT64, etensor.INT32:
+		return "INTEGER"
+	}
+	return "REAL"
+}
+
+// DBCols returns the indexes of the scalar columns of given table, which
+// are the ones written to the db
+func DBCols(dt *etable.Table) []int {
+	var cis []int
+	for ci, col := range dt.Cols {
+		if _, cells := col.RowCellSize(); cells == 1 {
+			cis = append(cis, ci)
+		}
+	}
+	return cis
+}
+
+// DBEnsureTable creates the db table of given name with given key columns
+// (name and SQL type pairs) and a column per scalar column of dt, if it
+// does not exist, else adds any columns it does not have yet
+func DBEnsureTable(db *sql.DB, name string, keys [][2]string, dt *etable.Table) error {
+	cols := append([][2]string{}, keys...)
+	for _, ci := range DBCols(dt) {
+		cols = append(cols, [2]string{dt.ColNames[ci], SQLType(dt.Cols[ci].DataType())})
+	}
+	defs := make([]string, len(cols))
+	for i, c := range cols {
+		defs[i] = fmt.Sprintf("%q %s", c[0], c[1])
+	}
+	if _, err := db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %q (%s)", name, strings.Join(defs, ", "))); err != nil {
+		return err
+	}
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%q)", name))
+	if err != nil {
+		return err
+	}
+	has := map[string]bool{}
+	for rows.Next() {
+		var cid, notnull, pk int
+		var nm, typ string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &nm, &typ, &notnull, &dflt, &pk); err != nil {
+			rows.Close()
+			return err
+		}
+		has[nm] = true
+	}
+	rows.Close()
+	for _, c := range cols {
+		if has[c[0]] {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %q ADD COLUMN %q %s", name, c[0], c[1])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DBInsertRows inserts given rows of dt into the db table of given name,
+// with the given key column values first, in one transaction
+func DBInsertRows(db *sql.DB, name string, keys []string, keyVals []interface{}, dt *etable.Table, rows []int) error {
+	cis := DBCols(dt)
+	nms := make([]string, 0, len(keys)+len(cis))
+	for _, k := range keys {
+		nms = append(nms, fmt.Sprintf("%q", k))
+	}
+	for _, ci := range cis {
+		nms = append(nms, fmt.Sprintf("%q", dt.ColNames[ci]))
+	}
+	qs := strings.TrimSuffix(strings.Repeat("?, ", len(nms)), ", ")
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	st, err := tx.Prepare(fmt.Sprintf("INSERT INTO %q (%s) VALUES (%s)", name, strings.Join(nms, ", "), qs))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer st.Close()
+	for _, row := range rows {
+		vals := append([]interface{}{}, keyVals...)
+		for _, ci := range cis {
+			col := dt.Cols[ci]
+			if col.DataType() == etensor.STRING {
+				vals = append(vals, col.StringVal1D(row))
+			} else {
+				vals = append(vals, col.FloatVal1D(row))
+			}
+		}
+		if _, err := st.Exec(vals...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// OpenDB opens the DBFile, if set and not already open, creating the runs,
+// epochs and trials tables as needed, and starts a new run record -- called
+// at Init.  On error, db logging is turned off for the run.
+func (ss *Sim) OpenDB() error {
+	ss.DBRunID = 0
+	if ss.DBFile == "" {
+		ss.CloseDB()
+		return nil
+	}
+	if !HasDBDriver() {
+		return fmt.Errorf("OpenDB: no %s driver for DBFile %s -- build with -tags sqlite (needs cgo)", DBDriver, ss.DBFile)
+	}
+	if ss.DB == nil {
+		db, err := sql.Open(DBDriver, fmt.Sprintf("file:%s?_busy_timeout=%d", ss.DBFile, DBBusyMs))
+		if err != nil {
+			return err
+		}
+		ss.DB = db
+	}
+	err := DBEnsureTable(ss.DB, "runs", [][2]string{{"run_id", "INTEGER PRIMARY KEY AUTOINCREMENT"},
+		{"Start", "TEXT"}, {"Expt", "TEXT"}, {"RndSeed", "INTEGER"}, {"PatsHash", "TEXT"}, {"Params", "TEXT"},
+		{"Epochs", "INTEGER"}, {"FirstZero", "INTEGER"}, {"LastZero", "INTEGER"}, {"NZero", "INTEGER"}, {"Halt", "TEXT"}}, &etable.Table{})
+	if err == nil {
+		err = DBEnsureTable(ss.DB, "epochs", [][2]string{{"run_id", "INTEGER"}}, ss.EpcLog)
+	}
+	if err == nil {
+		err = DBEnsureTable(ss.DB, "trials", [][2]string{{"run_id", "INTEGER"}, {"Epoch", "INTEGER"}}, ss.TstTrlLog)
+	}
+	if err != nil {
+		ss.CloseDB()
+		return err
+	}
+	pb, _ := json.Marshal(ss.Params)
+	res, err := ss.DB.Exec(`INSERT INTO runs (Start, Expt, RndSeed, PatsHash, Params) VALUES (?, ?, ?, ?, ?)`,
+		time.Now().Format(time.RFC3339), ss.Expt, ss.RndSeed, ss.PatsHash, string(pb))
+	if err != nil {
+		ss.CloseDB()
+		return err
+	}
+	ss.DBRunID, err = res.LastInsertId()
+	return err
+}
+
+// CloseDB closes the DBFile, if open
+func (ss *Sim) CloseDB() {
+	if ss.DB == nil {
+		return
+	}
+	ss.DB.Close()
+	ss.DB = nil
+	ss.DBRunID = 0
+}
+
+// DBOn returns whether db logging is on for the current run
+func (ss *Sim) DBOn() bool {
+	return ss.DB != nil && ss.DBRunID > 0
+}
+
+// DBLogEpoch writes given row of the EpcLog to the epochs table, if DBOn
+// -- called at the end of LogEpoch
+func (ss *Sim) DBLogEpoch(row int) {
+	if !ss.DBOn() {
+		return
+	}
+	if err := DBInsertRows(ss.DB, "epochs", []string{"run_id"}, []interface{}{ss.DBRunID}, ss.EpcLog, []int{row}); err != nil {
+		log.Println(err)
+	}
+}
+
+// DBLogTstTrls writes the TstTrlLog to the trials table, if DBOn -- called
+// at the end of TestAll
+func (ss *Sim) DBLogTstTrls() {
+	if !ss.DBOn() {
+		return
+	}
+	rows := make([]int, ss.TstTrlLog.NumRows())
+	for i := range rows {
+		rows[i] = i
+	}
+	if err := DBInsertRows(ss.DB, "trials", []string{"run_id", "Epoch"}, []interface{}{ss.DBRunID, ss.Epoch}, ss.TstTrlLog, rows); err != nil {
+		log.Println(err)
+	}
+}
+
+// DBLogRun updates the run record with the RunLog summary, if DBOn --
+// called at the end of LogRun
+func (ss *Sim) DBLogRun() {
+	if !ss.DBOn() {
+		return
+	}
+	_, err := ss.DB.Exec(`UPDATE runs SET Epochs = ?, FirstZero = ?, LastZero = ?, NZero = ?, Halt = ? WHERE run_id = ?`,
+		ss.Epoch, ss.FirstZero, ss.LastZero, ss.NZero, ss.Halt, ss.DBRunID)
+	if err != nil {
+		log.Println(err)
+	}
+}
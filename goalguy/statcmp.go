@@ -0,0 +1,341 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// statcmp.go has the statistical comparison of two sets of runs, e.g., the
+// runs of two configurations over the same seeds, each in its own run
+// directory (one run bundle per sub-directory, as for the aggplot.go
+// report).  For each run, the measures are computed from its epc_log.tsv:
+//
+//	FirstZero    first Epoch where OutGoalPctErr == 0 -- runs that never
+//	             reached 0 are censored at their last Epoch + 1
+//	FinalPctErr  OutGoalPctErr of the last epoch
+//
+// and the two sets are compared with Welch's t-test and the Mann-Whitney U
+// test (normal approximation, with tie and continuity corrections), both
+// two-sided.  The rank-based Mann-Whitney is the safer one for FirstZero,
+// given the censoring and the typically skewed distributions.
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// StatCmpMeasures are the names of the per-run measures compared by StatCmp
+var StatCmpMeasures = []string{"FirstZero", "FinalPctErr"}
+
+// RunMeasure returns the value of given StatCmpMeasures measure of given
+// epoch log, and whether it is censored (FirstZero never reached)
+func RunMeasure(dt *etable.Table, meas string) (float64, bool, error) {
+	ec := dt.ColByName("Epoch")
+	vc := dt.ColByName("OutGoalPctErr")
+	if ec == nil || vc == nil {
+		return 0, false, fmt.Errorf("RunMeasure: epoch log has no Epoch or OutGoalPctErr column")
+	}
+	nr := dt.NumRows()
+	if nr == 0 {
+		return 0, false, fmt.Errorf("RunMeasure: epoch log is empty")
+	}
+	switch meas {
+	case "FirstZero":
+		for r := 0; r < nr; r++ {
+			if vc.FloatVal1D(r) == 0 {
+				return ec.FloatVal1D(r), false, nil
+			}
+		}
+		return ec.FloatVal1D(nr-1) + 1, true, nil
+	case "FinalPctErr":
+		return vc.FloatVal1D(nr - 1), false, nil
+	}
+	return 0, false, fmt.Errorf("RunMeasure: unknown measure: %s", meas)
+}
+
+// MeanSD returns the mean and sample standard deviation of given values
+func MeanSD(vals []float64) (mean, sd float64) {
+	n := float64(len(vals))
+	if n == 0 {
+		return 0, 0
+	}
+	for _, v := range vals {
+		mean += v
+	}
+	mean /= n
+	if n < 2 {
+		return mean, 0
+	}
+	for _, v := range vals {
+		sd += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sd / (n - 1))
+}
+
+// Median returns the median of given values
+func Median(vals []float64) float64 {
+	n := len(vals)
+	if n == 0 {
+		return 0
+	}
+	sv := append([]float64(nil), vals...)
+	sort.Float64s(sv)
+	if n%2 == 1 {
+		return sv[n/2]
+	}
+	return 0.5 * (sv[n/2-1] + sv[n/2])
+}
+
+// WelchT returns Welch's unequal variances t statistic of a - b, its
+// degrees of freedom and the two-sided p value -- p is NaN if either set has
+// fewer than 2 values, or both have zero variance
+func WelchT(a, b []float64) (t, df, p float64) {
+	na, nb := float64(len(a)), float64(len(b))
+	if na < 2 || nb < 2 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	ma, sa := MeanSD(a)
+	mb, sb := MeanSD(b)
+	va, vb := sa*sa/na, sb*sb/nb
+	if va+vb == 0 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	t = (ma - mb) / math.Sqrt(va+vb)
+	df = (va + vb) * (va + vb) / (va*va/(na-1) + vb*vb/(nb-1))
+	p = RegIncBeta(0.5*df, 0.5, df/(df+t*t))
+	return
+}
+
+// MannWhitneyU returns the Mann-Whitney U statistic of a vs. b (the number
+// of pairs where a > b, counting ties as 1/2), and its two-sided p value from
+// the normal approximation with tie and continuity corrections
+func MannWhitneyU(a, b []float64) (u, p float64) {
+	na, nb := len(a), len(b)
+	if na == 0 || nb == 0 {
+		return math.NaN(), math.NaN()
+	}
+	type rv struct {
+		v float64
+		a bool
+	}
+	all := make([]rv, 0, na+nb)
+	for _, v := range a {
+		all = append(all, rv{v, true})
+	}
+	for _, v := range b {
+		all = append(all, rv{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].v < all[j].v })
+
+	n := float64(na + nb)
+	ra := 0.0   // rank sum of a
+	ties := 0.0 // sum of t^3 - t over tie groups
+	for i := 0; i < len(all); {
+		j := i + 1
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		rk := 0.5 * float64(i+1+j) // average of ranks i+1 .. j
+		for k := i; k < j; k++ {
+			if all[k].a {
+				ra += rk
+			}
+		}
+		tn := float64(j - i)
+		ties += tn*tn*tn - tn
+		i = j
+	}
+	fa, fb := float64(na), float64(nb)
+	u = ra - fa*(fa+1)/2
+	mu := fa * fb / 2
+	sd := math.Sqrt(fa * fb / 12 * ((n + 1) - ties/(n*(n-1))))
+	if sd == 0 {
+		return u, math.NaN()
+	}
+	z := (math.Abs(u-mu) - 0.5) / sd
+	if z < 0 {
+		z = 0
+	}
+	p = math.Erfc(z / math.Sqrt2)
+	return
+}
+
+// RegIncBeta returns the regularized incomplete beta function I_x(a, b),
+// computed from its continued fraction
+func RegIncBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a + b)
+	lb, _ := math.Lgamma(a)
+	lc, _ := math.Lgamma(b)
+	bt := math.Exp(la - lb - lc + a*math.Log(x) + b*math.Log(1-x))
+	if x < (a+1)/(a+b+2) {
+		return bt * betaCF(a, b, x) / a
+	}
+	return 1 - bt*betaCF(b, a, 1-x)/b
+}
+
+// betaCF evaluates the continued fraction of the incomplete beta function
+// by the modified Lentz method
+func betaCF(a, b, x float64) float64 {
+	const maxIt = 200
+	const eps = 3e-14
+	const fpMin = 1e-300
+	qab, qap, qam := a+b, a+1, a-1
+	c := 1.0
+	d := 1 - qab*x/qap
+	if math.Abs(d) < fpMin {
+		d = fpMin
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIt; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < fpMin {
+			d = fpMin
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < fpMin {
+			c = fpMin
+		}
+		d = 1 / d
+		h *= d * c
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < fpMin {
+			d = fpMin
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < fpMin {
+			c = fpMin
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < eps {
+			break
+		}
+	}
+	return h
+}
+
+// StatCmp returns the report table comparing the StatCmpMeasures of the
+// runs in run directory a vs. those in run directory b: one row per measure
+func StatCmp(dirA, dirB string) (*etable.Table, error) {
+	logsA, _, err := OpenRunEpcLogs(dirA)
+	if err != nil {
+		return nil, err
+	}
+	logsB, _, err := OpenRunEpcLogs(dirB)
+	if err != nil {
+		return nil, err
+	}
+	dt := &etable.Table{}
+	dt.SetFromSchema(etable.Schema{
+		{"Measure", etensor.STRING, nil, nil},
+		{"NA", etensor.INT64, nil, nil},
+		{"NB", etensor.INT64, nil, nil},
+		{"NCensA", etensor.INT64, nil, nil},
+		{"NCensB", etensor.INT64, nil, nil},
+		{"MeanA", etensor.FLOAT64, nil, nil},
+		{"MeanB", etensor.FLOAT64, nil, nil},
+		{"SDA", etensor.FLOAT64, nil, nil},
+		{"SDB", etensor.FLOAT64, nil, nil},
+		{"MedianA", etensor.FLOAT64, nil, nil},
+		{"MedianB", etensor.FLOAT64, nil, nil},
+		{"T", etensor.FLOAT64, nil, nil},
+		{"DF", etensor.FLOAT64, nil, nil},
+		{"TP", etensor.FLOAT64, nil, nil},
+		{"U", etensor.FLOAT64, nil, nil},
+		{"UP", etensor.FLOAT64, nil, nil},
+	}, len(StatCmpMeasures))
+
+	measVals := func(logs []*etable.Table, meas string) ([]float64, int, error) {
+		vals := make([]float64, len(logs))
+		ncens := 0
+		for i, lg := range logs {
+			v, cens, err := RunMeasure(lg, meas)
+			if err != nil {
+				return nil, 0, err
+			}
+			vals[i] = v
+			if cens {
+				ncens++
+			}
+		}
+		return vals, ncens, nil
+	}
+
+	for row, meas := range StatCmpMeasures {
+		a, ca, err := measVals(logsA, meas)
+		if err != nil {
+			return nil, err
+		}
+		b, cb, err := measVals(logsB, meas)
+		if err != nil {
+			return nil, err
+		}
+		ma, sa := MeanSD(a)
+		mb, sb := MeanSD(b)
+		t, df, tp := WelchT(a, b)
+		u, up := MannWhitneyU(a, b)
+		dt.ColByName("Measure").SetString1D(row, meas)
+		dt.ColByName("NA").SetFloat1D(row, float64(len(a)))
+		dt.ColByName("NB").SetFloat1D(row, float64(len(b)))
+		dt.ColByName("NCensA").SetFloat1D(row, float64(ca))
+		dt.ColByName("NCensB").SetFloat1D(row, float64(cb))
+		dt.ColByName("MeanA").SetFloat1D(row, ma)
+		dt.ColByName("MeanB").SetFloat1D(row, mb)
+		dt.ColByName("SDA").SetFloat1D(row, sa)
+		dt.ColByName("SDB").SetFloat1D(row, sb)
+		dt.ColByName("MedianA").SetFloat1D(row, Median(a))
+		dt.ColByName("MedianB").SetFloat1D(row, Median(b))
+		dt.ColByName("T").SetFloat1D(row, t)
+		dt.ColByName("DF").SetFloat1D(row, df)
+		dt.ColByName("TP").SetFloat1D(row, tp)
+		dt.ColByName("U").SetFloat1D(row, u)
+		dt.ColByName("UP").SetFloat1D(row, up)
+	}
+	return dt, nil
+}
+
+// StatCmpReport returns a printable summary of given StatCmp report table,
+// labeling the two run sets with given names
+func StatCmpReport(dt *etable.Table, nmA, nmB string) string {
+	s := fmt.Sprintf("A: %s  vs.  B: %s\n", nmA, nmB)
+	for r := 0; r < dt.NumRows(); r++ {
+		val := func(cl string) float64 { return dt.ColByName(cl).FloatVal1D(r) }
+		s += fmt.Sprintf("%-12s A: %.3g +/- %.3g (median %.3g, n=%d, censored %d)  B: %.3g +/- %.3g (median %.3g, n=%d, censored %d)\n",
+			dt.ColByName("Measure").StringVal1D(r),
+			val("MeanA"), val("SDA"), val("MedianA"), int(val("NA")), int(val("NCensA")),
+			val("MeanB"), val("SDB"), val("MedianB"), int(val("NB")), int(val("NCensB")))
+		s += fmt.Sprintf("%-12s Welch t(%.1f) = %.3f, p = %.4g   Mann-Whitney U = %g, p = %.4g\n",
+			"", val("DF"), val("T"), val("TP"), val("U"), val("UP"))
+	}
+	return s
+}
+
+// SaveStatCmp compares the runs in run directories a and b with StatCmp,
+// saving the report table to given .tsv file and printing its summary
+func SaveStatCmp(dirA, dirB, fname string) error {
+	dt, err := StatCmp(dirA, dirB)
+	if err != nil {
+		return err
+	}
+	if err := SaveTable(dt, fname); err != nil {
+		return err
+	}
+	fmt.Print(StatCmpReport(dt, dirA, dirB))
+	fmt.Printf("saved run comparison statistics to: %s\n", fname)
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// stats.go has the StatsRecorder that accumulates the trial-level stats
+// (SSE, CosDiff, error counts) into epoch averages.  Each stat is recorded
+// under a name exactly once per trial, at the alpha cycle where it is
+// computed (Outcome stats in the first, Motor stats in the second), so a
+// trial can never be counted twice, and the epoch averages are divided by
+// the number of trials actually recorded, not the number of patterns.
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+)
+
+// StatsRecorder records named trial-level stats and computes their
+// epoch averages
+type StatsRecorder struct {
+	Trial   map[string]float32   `desc:"values recorded in the current trial"`
+	Vals    map[string][]float32 `desc:"values recorded for each trial so far in the current epoch"`
+	NTrials int                  `desc:"number of trials done so far in the current epoch"`
+	Epc     map[string]float32   `desc:"epoch averages computed by the last EpochDone"`
+	EpcSums map[string]float32   `desc:"epoch sums computed by the last EpochDone"`
+	EpcVals map[string][]float32 `desc:"per-trial values of the last epoch, for Verify"`
+	EpcN    int                  `desc:"number of trials in the last epoch"`
+	DupRecs int                  `desc:"number of times a stat was recorded more than once in the same trial -- these are ignored"`
+}
+
+// Init resets all the recorded values
+func (sr *StatsRecorder) Init() {
+	sr.Trial = make(map[string]float32)
+	sr.Vals = make(map[string][]float32)
+	sr.NTrials = 0
+	sr.Epc = make(map[string]float32)
+	sr.EpcSums = make(map[string]float32)
+	sr.EpcVals = nil
+	sr.EpcN = 0
+	sr.DupRecs = 0
+}
+
+// Rec records the value of named stat for the current trial -- a second
+// Rec of the same stat in the same trial is logged and ignored
+func (sr *StatsRecorder) Rec(key string, val float32) {
+	if sr.Trial == nil {
+		sr.Init()
+	}
+	if _, has := sr.Trial[key]; has {
+		sr.DupRecs++
+		log.Printf("StatsRecorder: stat %s already recorded this trial -- ignored\n", key)
+		return
+	}
+	sr.Trial[key] = val
+}
+
+// RecBool records 1 for true and 0 for false, so the epoch average is the
+// proportion of trials where it was true
+func (sr *StatsRecorder) RecBool(key string, val bool) {
+	if val {
+		sr.Rec(key, 1)
+	} else {
+		sr.Rec(key, 0)
+	}
+}
+
+// TrialDone adds the values recorded in the current trial to the epoch,
+// and starts a new trial -- call exactly once per trial
+func (sr *StatsRecorder) TrialDone() {
+	if sr.Trial == nil {
+		sr.Init()
+	}
+	for key, val := range sr.Trial {
+		sr.Vals[key] = append(sr.Vals[key], val)
+	}
+	sr.NTrials++
+	sr.Trial = make(map[string]float32)
+}
+
+// EpochDone computes the epoch sums and averages of all the stats, and
+// starts a new epoch
+func (sr *StatsRecorder) EpochDone() {
+	if sr.Trial == nil {
+		sr.Init()
+	}
+	sr.Epc = make(map[string]float32)
+	sr.EpcSums = make(map[string]float32)
+	for key, vals := range sr.Vals {
+		sum := float32(0)
+		for _, v := range vals {
+			sum += v
+		}
+		sr.EpcSums[key] = sum
+		sr.Epc[key] = sum / float32(len(vals))
+	}
+	sr.EpcVals = sr.Vals
+	sr.EpcN = sr.NTrials
+	sr.Vals = make(map[string][]float32)
+	sr.NTrials = 0
+}
+
+// EpcAvg returns the average over trials of named stat in the last epoch
+func (sr *StatsRecorder) EpcAvg(key string) float32 {
+	return sr.Epc[key]
+}
+
+// EpcSum returns the sum over trials of named stat in the last epoch
+func (sr *StatsRecorder) EpcSum(key string) float32 {
+	return sr.EpcSums[key]
+}
+
+// Verify checks that each stat of the last epoch was recorded exactly once
+// in every trial, and that its epoch average equals the mean of the
+// per-trial values (to float32 precision) -- returns an error otherwise
+func (sr *StatsRecorder) Verify() error {
+	if sr.DupRecs > 0 {
+		return fmt.Errorf("StatsRecorder: %d stats were recorded more than once in a trial", sr.DupRecs)
+	}
+	keys := make([]string, 0, len(sr.EpcVals))
+	for key := range sr.EpcVals {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		vals := sr.EpcVals[key]
+		if len(vals) != sr.EpcN {
+			return fmt.Errorf("StatsRecorder: stat %s recorded in %d of %d trials", key, len(vals), sr.EpcN)
+		}
+		sum := 0.0
+		for _, v := range vals {
+			sum += float64(v)
+		}
+		mean := sum / float64(len(vals))
+		if math.Abs(mean-float64(sr.Epc[key])) > 1.0e-5*math.Max(1, math.Abs(mean)) {
+			return fmt.Errorf("StatsRecorder: stat %s epoch average %g != per-trial mean %g", key, sr.Epc[key], mean)
+		}
+	}
+	return nil
+}
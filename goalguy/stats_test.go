@@ -0,0 +1,98 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"testing"
+)
+
+func TestStatsRecorderEpoch(t *testing.T) {
+	var sr StatsRecorder
+	sr.Init()
+	sses := []float32{2, 0, 1, 5}
+	for i, sse := range sses {
+		sr.Rec("SSE", sse)
+		sr.RecBool("Err", sse > 0)
+		sr.TrialDone()
+		if sr.NTrials != i+1 {
+			t.Errorf("NTrials: %d, want %d", sr.NTrials, i+1)
+		}
+	}
+	sr.EpochDone()
+	if sr.EpcN != 4 {
+		t.Errorf("EpcN: %d, want 4", sr.EpcN)
+	}
+	if s := sr.EpcSum("SSE"); s != 8 {
+		t.Errorf("EpcSum SSE: %g, want 8", s)
+	}
+	if a := sr.EpcAvg("SSE"); a != 2 {
+		t.Errorf("EpcAvg SSE: %g, want 2", a)
+	}
+	if s := sr.EpcSum("Err"); s != 3 {
+		t.Errorf("EpcSum Err: %g, want 3", s)
+	}
+	if a := sr.EpcAvg("Err"); a != 0.75 {
+		t.Errorf("EpcAvg Err: %g, want 0.75", a)
+	}
+	if err := sr.Verify(); err != nil {
+		t.Error(err)
+	}
+	if sr.NTrials != 0 || len(sr.Vals) != 0 {
+		t.Errorf("EpochDone did not start a new epoch: NTrials %d, %d stats", sr.NTrials, len(sr.Vals))
+	}
+
+	// next epoch does not include the previous one
+	sr.Rec("SSE", 3)
+	sr.TrialDone()
+	sr.EpochDone()
+	if a := sr.EpcAvg("SSE"); a != 3 {
+		t.Errorf("2nd epoch EpcAvg SSE: %g, want 3", a)
+	}
+	if a := sr.EpcAvg("Err"); a != 0 {
+		t.Errorf("2nd epoch EpcAvg Err: %g, want 0 (not recorded)", a)
+	}
+}
+
+func TestStatsRecorderDupRec(t *testing.T) {
+	var sr StatsRecorder
+	sr.Init()
+	sr.Rec("SSE", 1)
+	sr.Rec("SSE", 4) // ignored
+	sr.TrialDone()
+	sr.Rec("SSE", 3)
+	sr.TrialDone()
+	sr.EpochDone()
+	if sr.DupRecs != 1 {
+		t.Errorf("DupRecs: %d, want 1", sr.DupRecs)
+	}
+	if a := sr.EpcAvg("SSE"); a != 2 {
+		t.Errorf("EpcAvg SSE: %g, want 2 (duplicate ignored)", a)
+	}
+	if err := sr.Verify(); err == nil {
+		t.Errorf("Verify did not report the duplicate Rec")
+	}
+}
+
+func TestStatsRecorderMissingTrials(t *testing.T) {
+	var sr StatsRecorder
+	sr.Init()
+	for i := 0; i < 4; i++ {
+		sr.Rec("SSE", 1)
+		if i%2 == 0 {
+			sr.Rec("Delay", float32(i))
+		}
+		sr.TrialDone()
+	}
+	sr.EpochDone()
+	if sr.EpcN != 4 {
+		t.Errorf("EpcN: %d, want 4", sr.EpcN)
+	}
+	if a := sr.EpcAvg("Delay"); a != 1 {
+		t.Errorf("EpcAvg Delay: %g, want 1 (over the 2 trials recorded)", a)
+	}
+	if err := sr.Verify(); err == nil {
+		t.Errorf("Verify did not report the stat recorded in only 2 of 4 trials")
+	}
+}
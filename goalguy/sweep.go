@@ -0,0 +1,218 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// sweep.go runs a param sweep headlessly: all combinations of the values
+// given for each swept param are run with a fresh Sim, and a results table
+// with one row per combination (the param values, FirstZero / LastZero and
+// the final epoch stats) is returned.  The sweep spec file has one param
+// per line, as whitespace-separated:
+//
+//	<Sel> <Param> <val1> <val2> ...
+//
+// e.g., "#Motor Layer.Inhib.Layer.Gi 1.8 2.0 2.2" -- blank lines and lines
+// starting with # followed by a space are skipped.  The values override the
+// param of the same Sel in the Params of the experiment (or DefaultParams),
+// or are added as a new ParamSel if there is none.
+//
+// The runs are done serially: they all use the global math/rand source
+// (as do the network weight init and pattern generation), reseeded from
+// RndSeed at the start of each run, so each run is exactly reproducible.
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+const (
+	// SweepMaxEpcs is the maximum number of epochs to train each sweep run
+	SweepMaxEpcs = 200
+
+	// SweepNZeroStop is the number of consecutive zero-error epochs after
+	// which a sweep run stops
+	SweepNZeroStop = 5
+
+	// SweepNPats is the number of ExtReps patterns generated for each sweep run
+	SweepNPats = 25
+)
+
+// SweepParam is one swept param, with the values to run
+type SweepParam struct {
+	Sel   string    `desc:"selector of the ParamSel to set the param in, e.g., #Motor"`
+	Param string    `desc:"param path, e.g., Layer.Inhib.Layer.Gi"`
+	Vals  []float32 `desc:"values of the param to run"`
+}
+
+// Name returns the name of the param, as used for the results column
+func (sp *SweepParam) Name() string {
+	return sp.Sel + ":" + sp.Param
+}
+
+// OpenSweep reads the sweep spec from given file
+func OpenSweep(fname string) ([]SweepParam, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var sps []SweepParam
+	scan := bufio.NewScanner(f)
+	ln := 0
+	for scan.Scan() {
+		ln++
+		line := strings.TrimSpace(scan.Text())
+		if line == "" || strings.HasPrefix(line, "# ") {
+			continue
+		}
+		fs := strings.Fields(line)
+		if len(fs) < 3 {
+			return nil, fmt.Errorf("%s:%d: need a Sel, Param and at least one value", fname, ln)
+		}
+		sp := SweepParam{Sel: fs[0], Param: fs[1]}
+		for _, vs := range fs[2:] {
+			v, err := strconv.ParseFloat(vs, 32)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", fname, ln, err)
+			}
+			sp.Vals = append(sp.Vals, float32(v))
+		}
+		sps = append(sps, sp)
+	}
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+	if len(sps) == 0 {
+		return nil, fmt.Errorf("%s: no params to sweep", fname)
+	}
+	return sps, nil
+}
+
+// SweepCombos returns all combinations of the values of the swept params,
+// as indexes into each param's Vals, with the last param varying fastest
+func SweepCombos(sps []SweepParam) [][]int {
+	cmbs := [][]int{{}}
+	for _, sp := range sps {
+		var ncmbs [][]int
+		for _, cmb := range cmbs {
+			for vi := range sp.Vals {
+				nc := append(append([]int{}, cmb...), vi)
+				ncmbs = append(ncmbs, nc)
+			}
+		}
+		cmbs = ncmbs
+	}
+	return cmbs
+}
+
+// SweepParams returns a copy of base with the swept params set to the
+// values of given combination -- base is not modified
+func SweepParams(base emer.ParamStyle, sps []SweepParam, cmb []int) emer.ParamStyle {
+	ps := CopyParams(base)
+	for i, sp := range sps {
+		ps = SetParamVal(ps, sp.Sel, sp.Param, sp.Vals[cmb[i]])
+	}
+	return ps
+}
+
+// SweepRun trains a fresh Sim with given experiment and params, without
+// the gui, and returns it
+func SweepRun(expt string, params emer.ParamStyle, dir string) (*Sim, error) {
+	ss := &Sim{}
+	ss.New()
+	if expt != "" {
+		if err := ss.SetExpt(expt); err != nil {
+			return nil, err
+		}
+	}
+	ss.Params = params
+	ss.MaxEpcs = SweepMaxEpcs
+	ss.NZeroStop = SweepNZeroStop
+	ss.ViewOn = false
+	ss.Plot = false
+	ss.Config()
+	rand.Seed(ss.RndSeed)
+	ss.GenExtReps(ss.ExtReps, SweepNPats)
+	ss.ValReps.SetNumRows(0)
+	if dir != "" {
+		ss.UseRunDir(dir)
+	}
+	ss.Init()
+	ss.Train()
+	ss.CloseEpcLogFile()
+	return ss, nil
+}
+
+// ConfigSweepResults returns a new results table for given swept params
+func ConfigSweepResults(sps []SweepParam, rows int) *etable.Table {
+	sc := etable.Schema{}
+	for i := range sps {
+		sc = append(sc, etable.Column{sps[i].Name(), etensor.FLOAT32, nil, nil})
+	}
+	sc = append(sc, etable.Schema{
+		{"Epochs", etensor.INT64, nil, nil},
+		{"FirstZero", etensor.INT64, nil, nil},
+		{"LastZero", etensor.INT64, nil, nil},
+		{"OutGoalPctErr", etensor.FLOAT32, nil, nil},
+		{"OutPredPctErr", etensor.FLOAT32, nil, nil},
+		{"OutSSE", etensor.FLOAT32, nil, nil},
+		{"MotSSE", etensor.FLOAT32, nil, nil},
+	}...)
+	dt := &etable.Table{}
+	dt.SetFromSchema(sc, rows)
+	return dt
+}
+
+// RunSweep runs all combinations of the swept params, one after the other,
+// and returns the results table -- if rd is non-nil, the EpcLog of each run
+// is streamed to its own run_<row> subdirectory of rd, and only its last
+// epochs are kept in memory
+func RunSweep(expt string, sps []SweepParam, rd *RunDirs) (*etable.Table, error) {
+	base := DefaultParams
+	if expt != "" {
+		ex, err := ExptByName(expt)
+		if err != nil {
+			return nil, err
+		}
+		base = ex.Params
+	}
+	cmbs := SweepCombos(sps)
+	dt := ConfigSweepResults(sps, len(cmbs))
+	var rerr error
+	for row, cmb := range cmbs {
+		dir := ""
+		var err error
+		if rd != nil {
+			dir, err = rd.Create(fmt.Sprintf("run_%03d", row))
+		}
+		var ss *Sim
+		if err == nil {
+			ss, err = SweepRun(expt, SweepParams(base, sps, cmb), dir)
+		}
+		if err != nil {
+			rerr = err
+			continue
+		}
+		for i := range sps {
+			dt.ColByName(sps[i].Name()).SetFloat1D(row, float64(sps[i].Vals[cmb[i]]))
+		}
+		dt.ColByName("Epochs").SetFloat1D(row, float64(ss.Epoch))
+		dt.ColByName("FirstZero").SetFloat1D(row, float64(ss.FirstZero))
+		dt.ColByName("LastZero").SetFloat1D(row, float64(ss.LastZero))
+		dt.ColByName("OutGoalPctErr").SetFloat1D(row, float64(ss.EpcOutGoalPctErr))
+		dt.ColByName("OutPredPctErr").SetFloat1D(row, float64(ss.EpcOutPredPctErr))
+		dt.ColByName("OutSSE").SetFloat1D(row, float64(ss.EpcOutSSE))
+		dt.ColByName("MotSSE").SetFloat1D(row, float64(ss.EpcMotSSE))
+		fmt.Printf("sweep run %d of %d done: FirstZero: %d\n", row+1, len(cmbs), ss.FirstZero)
+	}
+	return dt, rerr
+}
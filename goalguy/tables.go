@@ -0,0 +1,33 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// tables.go has the saving and opening of all the tables, in the native
+// etable .tsv format: tab separated, with the typed headers of the columns
+// (e.g., %Context[2:0,0]<2:5,5>), from which the tensor cell shapes of the
+// columns are reconstructed when the tables are opened.
+
+import (
+	"github.com/emer/etable/etable"
+	"github.com/goki/gi/gi"
+)
+
+// TableDelim is the delimiter of the saved tables
+const TableDelim = '\t'
+
+// SaveTable saves given table to given .tsv file, with typed headers
+func SaveTable(dt *etable.Table, fname string) error {
+	return dt.SaveCSV(gi.FileName(fname), TableDelim, etable.Headers)
+}
+
+// OpenTable opens given table from given .tsv file saved by SaveTable:
+// any existing columns of the table are replaced by the columns given by
+// the typed headers of the file, with their tensor cell shapes
+func OpenTable(dt *etable.Table, fname string) error {
+	dt.Cols = nil
+	dt.ColNames = nil
+	dt.Rows = 0
+	return dt.OpenCSV(gi.FileName(fname), TableDelim)
+}
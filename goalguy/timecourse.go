@@ -0,0 +1,88 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"fmt"
+
+	"github.com/emer/etable/eplot"
+	"github.com/emer/leabra/leabra"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// The unit timecourse recorder (TcOn) records the activity of the TcUnits
+// of the TcLayer on every cycle across a trial (all alpha cycles), and
+// plots it as one cycle-by-activation line per unit in the Timecourse tab
+// -- for studying competition and settling in the k ~= 1 layers.
+
+// TcUnitIdxs returns the indexes of the units to record: TcUnits, or all
+// units in the layer if empty
+func (ss *Sim) TcUnitIdxs(ly *leabra.Layer) []int {
+	if len(ss.TcUnits) > 0 {
+		return ss.TcUnits
+	}
+	idxs := make([]int, len(ly.Neurons))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	return idxs
+}
+
+// ResetTimecourse clears the recorded timecourse -- called at the start of each trial
+func (ss *Sim) ResetTimecourse() {
+	ss.TcActs = ss.TcActs[:0]
+}
+
+// RecTimecourse records the current activity of the units -- called every
+// cycle in AlphaCyc
+func (ss *Sim) RecTimecourse() {
+	if !ss.TcOn {
+		return
+	}
+	ly, ok := ss.Net.LayerByName(ss.TcLayer).(*leabra.Layer)
+	if !ok {
+		return
+	}
+	idxs := ss.TcUnitIdxs(ly)
+	acts := make([]float32, len(idxs))
+	for i, ui := range idxs {
+		if ui >= 0 && ui < len(ly.Neurons) {
+			acts[i] = ly.Neurons[ui].Act
+		}
+	}
+	ss.TcActs = append(ss.TcActs, acts)
+}
+
+// PlotTimecourse plots the recorded timecourse into TcSvg, one line per unit
+func (ss *Sim) PlotTimecourse() *plot.Plot {
+	if !ss.TcOn || ss.TcSvg == nil || !ss.TcSvg.IsVisible() || len(ss.TcActs) == 0 {
+		return nil
+	}
+	ly, ok := ss.Net.LayerByName(ss.TcLayer).(*leabra.Layer)
+	if !ok {
+		return nil
+	}
+	plt := NewPlot()
+	plt.Title.Text = ss.TcLayer + " Unit Timecourse"
+	plt.X.Label.Text = "Cycle"
+	plt.Y.Label.Text = "Act"
+	for i, ui := range ss.TcUnitIdxs(ly) {
+		xy := make(plotter.XYs, len(ss.TcActs))
+		for cyc, acts := range ss.TcActs {
+			xy[cyc].X = float64(cyc)
+			xy[cyc].Y = float64(acts[i])
+		}
+		l, _ := plotter.NewLine(xy)
+		l.LineStyle.Width = vg.Points(CurPlotStyle.LineWidth)
+		l.LineStyle.Color = CurPlotStyle.Color(i)
+		plt.Add(l)
+		plt.Legend.Add(fmt.Sprintf("%d", ui), l)
+	}
+	plt.Legend.Top = true
+	eplot.PlotViewSVG(plt, ss.TcSvg, 5)
+	return plt
+}
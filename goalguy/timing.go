@@ -0,0 +1,75 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// timing.go has the settling time controls of AlphaCyc: the number of
+// cycles per quarter and of quarters per alpha cycle, with per-AlphaCycle
+// overrides (AlphaTimings) so that e.g., the 2nd (Goal -> Motor) alpha cycle
+// can settle longer, as the k=1 Motor competition may need more cycles to
+// resolve.  The last NPlusQtrs quarters are the plus phase (1 by default,
+// the leabra 3+1), e.g., 2 for a longer plus phase for the Motor target on
+// the goal-driven AlphaCycle, and the one before them always ends the minus
+// phase: extra minus quarters are added to the start of the minus phase,
+// and fewer (down to 1) removed from it.  Only the last plus quarter ends
+// the plus phase (leabra QuarterFinal), so ActP is from its end.
+
+// MinQuarters is the minimum number of quarters per alpha cycle: one for
+// the minus phase and one for the plus phase
+const MinQuarters = 2
+
+// AlphaTiming has the settling time settings for one AlphaCycle of the trial
+// -- zero values use the Sim-wide CycPerQtr and NQuarters
+type AlphaTiming struct {
+	CycPerQtr int `desc:"number of cycles per quarter -- 0 = CycPerQtr"`
+	NQuarters int `desc:"number of quarters per alpha cycle, the last NPlusQtrs of which are the plus phase -- 0 = NQuarters"`
+	NPlusQtrs int `desc:"number of quarters of the plus phase, at the end of the alpha cycle -- 0 = NPlusQtrs"`
+}
+
+// AlphaTimes returns the cycles per quarter, quarters per alpha cycle and
+// plus phase quarters to use for the current AlphaCycle
+func (ss *Sim) AlphaTimes() (cycPerQtr, nQtrs, nPlus int) {
+	cycPerQtr, nQtrs, nPlus = ss.CycPerQtr, ss.NQuarters, ss.NPlusQtrs
+	if ss.AlphaCycle < len(ss.AlphaTimings) {
+		at := ss.AlphaTimings[ss.AlphaCycle]
+		if at.CycPerQtr > 0 {
+			cycPerQtr = at.CycPerQtr
+		}
+		if at.NQuarters > 0 {
+			nQtrs = at.NQuarters
+		}
+		if at.NPlusQtrs > 0 {
+			nPlus = at.NPlusQtrs
+		}
+	}
+	if cycPerQtr <= 0 {
+		cycPerQtr = 25
+	}
+	if nQtrs < MinQuarters {
+		nQtrs = 4
+	}
+	if nPlus < 1 {
+		nPlus = 1
+	}
+	if nPlus > nQtrs-1 {
+		nPlus = nQtrs - 1 // need at least one minus quarter
+	}
+	return
+}
+
+// LeabraQtr returns the standard (0-3) leabra quarter for given quarter of
+// an alpha cycle of nQtrs quarters, the last nPlus of which are the plus
+// phase: 3 for the plus phase quarters, 2 for the one before them (end of
+// minus phase), and the earlier quarters counting back from 1, floored at 0
+func LeabraQtr(qtr, nQtrs, nPlus int) int {
+	nMinus := nQtrs - nPlus
+	if qtr >= nMinus {
+		return 3
+	}
+	lq := 2 - (nMinus - 1 - qtr)
+	if lq < 0 {
+		lq = 0
+	}
+	return lq
+}
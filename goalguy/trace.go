@@ -0,0 +1,90 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"log"
+
+	"github.com/emer/leabra/leabra"
+)
+
+// TracePrjn wraps a projection whose DWt's are held in an eligibility
+// trace over the trial instead of being applied immediately, and only
+// committed to the weights if the trial is rewarded
+type TracePrjn struct {
+	Prjn  *leabra.Prjn `desc:"the projection"`
+	Trace []float32    `desc:"eligibility trace: DWt's accumulated over the current trial, per synapse"`
+}
+
+// Init initializes the trace for given projection
+func (tp *TracePrjn) Init(pj *leabra.Prjn) {
+	tp.Prjn = pj
+	tp.Trace = make([]float32, len(pj.Syns))
+}
+
+// Hold moves the current DWt's of the projection into the trace, so they
+// are not applied by WtFmDWt
+func (tp *TracePrjn) Hold() {
+	for si := range tp.Prjn.Syns {
+		sy := &tp.Prjn.Syns[si]
+		tp.Trace[si] += sy.DWt
+		sy.DWt = 0
+	}
+}
+
+// Commit applies the trace to the weights, and clears it
+func (tp *TracePrjn) Commit() {
+	for si := range tp.Prjn.Syns {
+		tp.Prjn.Syns[si].DWt = tp.Trace[si]
+		tp.Trace[si] = 0
+	}
+	tp.Prjn.WtFmDWt()
+}
+
+// Discard clears the trace without applying it
+func (tp *TracePrjn) Discard() {
+	for si := range tp.Trace {
+		tp.Trace[si] = 0
+	}
+}
+
+// ConfigTrace configures the eligibility trace for the TracePrjnPath
+// projection if TraceOn -- called in Config after ConfigNet
+func (ss *Sim) ConfigTrace() {
+	ss.Trace.Prjn = nil
+	if !ss.TraceOn {
+		return
+	}
+	pj, err := ss.PrjnByPath(ss.TracePrjnPath)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	ss.Trace.Init(pj)
+}
+
+// HoldTrace holds the DWt's of the trace projection, if any -- called in
+// AlphaCyc after DWt, prior to WtFmDWt
+func (ss *Sim) HoldTrace() {
+	if ss.Trace.Prjn == nil {
+		return
+	}
+	ss.Trace.Hold()
+}
+
+// CommitTrace commits the eligibility trace if the trial was rewarded
+// (the Outcome matched the Goal), and otherwise discards it
+// -- called at the end of each TrainTrial
+func (ss *Sim) CommitTrace(rew bool) {
+	if ss.Trace.Prjn == nil {
+		return
+	}
+	if rew {
+		ss.Trace.Commit()
+		ss.TraceCommitCnt++
+	} else {
+		ss.Trace.Discard()
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// trialspec.go has the TrialSpec, which declares the structure of a trial:
+// for each of its named steps (alpha cycles), the type of each layer and
+// the source of the pattern clamped onto it.  ApplyInputs just applies the
+// step of the current AlphaCycle, so new trial structures can be tried by
+// loading a TrialSpec from a JSON file (TrialSpecFile), without
+// recompiling.  DefaultTrialSpec is the standard two-step trial: outcome
+// prediction / goal setting, then goal -> motor.  Training and testing run
+// all the steps, with the Outcome and Motor trial stats computed after the
+// steps marked OutStats and MotStats, and the ActP stored for the clamps of
+// the later steps after the one marked StoreActP (see OutStep, MotStep and
+// ActPStep for the defaults of a spec with none marked).
+//
+// A source is either a column of the pattern table, clamped from the row
+// of the current item, or one of the computed sources starting with $
+// (see TrialSrcs), which depend on the Sim settings (e.g., SeqOn, DriveOn).
+// A computed source can be unavailable on a given trial (e.g., $Goal when
+// the goal is not clamped), in which case an Input or Target layer is made
+// Hidden instead.  Clamps onto layers not in the network are skipped.
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// StepClamp specifies the setup of one layer on one step of a trial
+type StepClamp struct {
+	Layer string `desc:"name of the layer"`
+	Type  string `desc:"layer type on this step: Input, Target, Compare or Hidden -- empty to leave it as is"`
+	Src   string `desc:"source of the pattern clamped onto the layer: a column of the pattern table, or one of the computed TrialSrcs (starting with $) -- empty for none"`
+}
+
+// TrialStep is one named step (alpha cycle) of a trial
+type TrialStep struct {
+	Name      string      `desc:"name of the step"`
+	Clamps    []StepClamp `desc:"setup of each layer on this step, in order"`
+	OutStats  bool        `desc:"compute the Outcome trial stats (outcome prediction / goal setting) after this step"`
+	MotStats  bool        `desc:"compute the Motor trial stats (goal -> motor) after this step"`
+	StoreActP bool        `desc:"store the Motor and Outcome ActP of this step in the pattern table after it, for the clamps of the later steps (restored at the end of the trial)"`
+}
+
+// TrialSpec declares the steps of a trial
+type TrialSpec struct {
+	Name  string      `desc:"name of the trial structure"`
+	Steps []TrialStep `desc:"steps of the trial, one per alpha cycle"`
+}
+
+// TrialSrcs are the computed sources, with their descriptions
+var TrialSrcs = map[string]string{
+	"$Context":   "the item Context, or the SeqCtxt state if SeqOn, with the env Noise",
+	"$SeqCtxt":   "the SeqCtxt state if SeqOn",
+	"$Outcome":   "the item Outcome, or the DriveOut if DriveOn",
+	"$Goal":      "the desired Outcome as the Goal, if the Goal is clamped on this trial (GoalClampRow)",
+	"$MotorTarg": "the Motor target per MotorTarg, unless the outcome is aversive",
+	"$Drive":     "the DriveInput, if DriveOn",
+	"$Valence":   "the USValence pattern of the item, if ValenceOn",
+}
+
+// LayerTypes are the layer types that can be named in a StepClamp
+var LayerTypes = map[string]emer.LayerType{
+	"Input":   emer.Input,
+	"Target":  emer.Target,
+	"Compare": emer.Compare,
+	"Hidden":  emer.Hidden,
+}
+
+// DefaultTrialSpec is the standard two-step trial
+var DefaultTrialSpec = TrialSpec{
+	Name: "GoalMotor",
+	Steps: []TrialStep{
+		{Name: "OutcomeGoal", OutStats: true, StoreActP: true, Clamps: []StepClamp{
+			{"Goal", "Hidden", ""},
+			{"Motor", "Hidden", ""},
+			{"Outcome", "Target", "$Outcome"},
+			{"Critic", "Target", ""}, // target is applied at plus phase start
+			{"Drive", "", "$Drive"},
+			{"Context", "", "$Context"},
+			{"USValence", "", "$Valence"},
+		}},
+		{Name: "GoalMotor", MotStats: true, Clamps: []StepClamp{
+			{"Goal", "Input", "$Goal"}, // else maintained through the delay, or devalued
+			{"Motor", "Target", "$MotorTarg"},
+			{"Outcome", "Hidden", ""},
+			{"Critic", "Hidden", ""},
+			{"Drive", "", "$Drive"},
+			{"Context", "", "$SeqCtxt"}, // state determines the step within the sequence
+		}},
+	},
+}
+
+// OpenTrialSpec opens a TrialSpec from given JSON file, checking the layer
+// types and computed sources
+func OpenTrialSpec(fname string) (*TrialSpec, error) {
+	b, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	ts := &TrialSpec{}
+	if err := json.Unmarshal(b, ts); err != nil {
+		return nil, fmt.Errorf("OpenTrialSpec: %s: %v", fname, err)
+	}
+	if len(ts.Steps) == 0 {
+		return nil, fmt.Errorf("OpenTrialSpec: %s has no steps", fname)
+	}
+	for _, st := range ts.Steps {
+		for _, cl := range st.Clamps {
+			if _, ok := LayerTypes[cl.Type]; cl.Type != "" && !ok {
+				return nil, fmt.Errorf("OpenTrialSpec: step %s: invalid layer type %q for %s", st.Name, cl.Type, cl.Layer)
+			}
+			if _, ok := TrialSrcs[cl.Src]; len(cl.Src) > 0 && cl.Src[0] == '$' && !ok {
+				return nil, fmt.Errorf("OpenTrialSpec: step %s: invalid computed source %q for %s", st.Name, cl.Src, cl.Layer)
+			}
+		}
+	}
+	return ts, nil
+}
+
+// SaveTrialSpec saves given TrialSpec to given JSON file -- e.g., the
+// DefaultTrialSpec as a starting point for a new one
+func SaveTrialSpec(ts *TrialSpec, fname string) error {
+	b, err := json.MarshalIndent(ts, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fname, b, 0644)
+}
+
+// OpenTrialSpecFile opens the TrialSpec from TrialSpecFile if set, else
+// reverts to the DefaultTrialSpec -- called in Init
+func (ss *Sim) OpenTrialSpecFile() error {
+	ss.TrialSpec = nil
+	if ss.TrialSpecFile == "" {
+		return nil
+	}
+	ts, err := OpenTrialSpec(ss.TrialSpecFile)
+	if err != nil {
+		return err
+	}
+	ss.TrialSpec = ts
+	return nil
+}
+
+// CurTrialSpec returns the TrialSpec in use: TrialSpec if set, else the
+// DefaultTrialSpec
+func (ss *Sim) CurTrialSpec() *TrialSpec {
+	if ss.TrialSpec != nil {
+		return ss.TrialSpec
+	}
+	return &DefaultTrialSpec
+}
+
+// NTrialSteps returns the number of steps (alpha cycles) per trial
+func (ss *Sim) NTrialSteps() int {
+	return len(ss.CurTrialSpec().Steps)
+}
+
+// OutStep returns the index of the step marked OutStats -- the first step
+// if none is
+func (ts *TrialSpec) OutStep() int {
+	for i := range ts.Steps {
+		if ts.Steps[i].OutStats {
+			return i
+		}
+	}
+	return 0
+}
+
+// MotStep returns the index of the step marked MotStats -- the last step
+// if none is
+func (ts *TrialSpec) MotStep() int {
+	for i := range ts.Steps {
+		if ts.Steps[i].MotStats {
+			return i
+		}
+	}
+	return len(ts.Steps) - 1
+}
+
+// ActPStep returns the index of the step marked StoreActP -- the OutStep
+// if none is
+func (ts *TrialSpec) ActPStep() int {
+	for i := range ts.Steps {
+		if ts.Steps[i].StoreActP {
+			return i
+		}
+	}
+	return ts.OutStep()
+}
+
+// TrialSrc returns the pattern of given source for given row of the env
+// table -- nil if not available on this trial
+func (ss *Sim) TrialSrc(env *Env, row int, src string) etensor.Tensor {
+	et := env.Table
+	switch src {
+	case "$Context":
+		c := RowCell(et.ColByName("Context"), row)
+		if ss.SeqOn() {
+			c = ss.SeqCtxt // current environment state
+		}
+		return ss.CarryCtxt(env.CtxtInput(c))
+	case "$SeqCtxt":
+		if ss.SeqOn() {
+			return ss.SeqCtxt
+		}
+	case "$Outcome":
+		return ss.OutPat
+	case "$Goal":
+		if ss.GoalClampRow(row) {
+			return ss.OutPat
+		}
+	case "$MotorTarg":
+		if ss.TrialValence >= 0 { // not trained toward actions leading to aversive outcomes
+			return ss.MotorTargPat(et, row, ss.OutPat)
+		}
+	case "$Drive":
+		if ss.DriveOn {
+			return ss.DriveInput
+		}
+	case "$Valence":
+		if ss.ValenceOn {
+			return ss.ValencePat(ss.TrialValence)
+		}
+	default:
+		if col := et.ColByName(src); col != nil {
+			return RowCell(col, row)
+		}
+	}
+	return nil
+}
+
+// ApplyTrialStep sets up the layers per given step of the trial, for given
+// row of the env table
+func (ss *Sim) ApplyTrialStep(env *Env, row int, st *TrialStep) {
+	for _, cl := range st.Clamps {
+		ly, ok := ss.Net.LayerByName(cl.Layer).(*leabra.Layer)
+		if !ok || ly == nil {
+			continue
+		}
+		typ, hasTyp := LayerTypes[cl.Type]
+		if hasTyp {
+			ly.SetType(typ)
+		}
+		if cl.Src == "" {
+			continue
+		}
+		pat := ss.TrialSrc(env, row, cl.Src)
+		if pat == nil {
+			if hasTyp && (typ == emer.Input || typ == emer.Target) {
+				ly.SetType(emer.Hidden)
+			}
+			continue
+		}
+		ly.ApplyExt(pat)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"math/rand"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// Outcome valence (ValenceOn): each item has a Valence (+1 = appetitive,
+// -1 = aversive), which is presented on the USValence input layer along
+// with the Outcome on the 1st AlphaCycle (unit 0 = appetitive, 1 =
+// aversive).  Aversive outcomes are never rewarding (for the trace and
+// critic), and on the motor-production AlphaCycle the Motor layer is not
+// trained toward the action that led to them, so the network should learn
+// to approach appetitive outcomes and avoid aversive ones.  Approach is
+// measured as the proportion of appetitive trials on which the Motor
+// output in the minus phase of the 2nd AlphaCycle is the action taken, and
+// avoidance as the proportion of aversive trials on which it is not.
+
+// GenValence sets the Valence column of given table to -1 for a random
+// AversivePct proportion of items, and +1 for the rest
+func (ss *Sim) GenValence(et *etable.Table) {
+	vc := et.ColByName("Valence")
+	if vc == nil {
+		return
+	}
+	nr := et.NumRows()
+	nav := int(float32(nr)*ss.AversivePct + 0.5)
+	for i, pi := range rand.Perm(nr) {
+		v := 1.0
+		if i < nav {
+			v = -1
+		}
+		vc.SetFloat1D(pi, v)
+	}
+}
+
+// ItemValence returns the Valence of given row of given table, which is
+// +1 (appetitive) if the table has no Valence column or ValenceOn is off
+func (ss *Sim) ItemValence(et *etable.Table, row int) float32 {
+	if !ss.ValenceOn {
+		return 1
+	}
+	vc := et.ColByName("Valence")
+	if vc == nil {
+		return 1
+	}
+	if vc.FloatVal1D(row) < 0 {
+		return -1
+	}
+	return 1
+}
+
+// ValencePat returns the USValence input pattern for given valence
+func (ss *Sim) ValencePat(v float32) *etensor.Float32 {
+	if ss.ValenceInput == nil {
+		ss.ValenceInput = etensor.NewFloat32([]int{1, 2}, nil, []string{"Y", "X"})
+	}
+	ss.ValenceInput.SetZeros()
+	if v < 0 {
+		ss.ValenceInput.Values[1] = 1
+	} else {
+		ss.ValenceInput.Values[0] = 1
+	}
+	return ss.ValenceInput
+}
+
+// ValenceTrialStats accumulates the approach / avoidance stats for the
+// current trial, given the action taken on the 1st AlphaCycle -- called
+// after the 2nd AlphaCycle if training
+func (ss *Sim) ValenceTrialStats(tact int) {
+	if !ss.ValenceOn || tact < 0 {
+		return
+	}
+	motorLay := ss.Net.LayerByName("Motor").(*leabra.Layer)
+	same := ss.ActIdx(motorLay, "ActM") == tact
+	if ss.TrialValence > 0 {
+		ss.ApprTrlCnt++
+		if same {
+			ss.ApprCnt++
+		}
+	} else {
+		ss.AvoidTrlCnt++
+		if !same {
+			ss.AvoidCnt++
+		}
+	}
+}
+
+// LogValenceStats computes the epoch approach and avoidance proportions
+// and resets the counts -- called in LogEpoch
+func (ss *Sim) LogValenceStats() {
+	ss.EpcApproachPct = 0
+	ss.EpcAvoidPct = 0
+	if ss.ApprTrlCnt > 0 {
+		ss.EpcApproachPct = float32(ss.ApprCnt) / float32(ss.ApprTrlCnt)
+	}
+	if ss.AvoidTrlCnt > 0 {
+		ss.EpcAvoidPct = float32(ss.AvoidCnt) / float32(ss.AvoidTrlCnt)
+	}
+	ss.ApprCnt, ss.ApprTrlCnt, ss.AvoidCnt, ss.AvoidTrlCnt = 0, 0, 0, 0
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// validate.go checks that the ExtReps table can actually drive the network
+// before Init / Train: it must have rows, and each of the Context, Goal,
+// Motor and Outcome columns must exist with one cell per unit of the layer
+// of the same name.  Otherwise ApplyInputs silently applies partial
+// patterns (or panics) and the epoch stats are meaningless.
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/emer/etable/etable"
+)
+
+// ExtRepsLays are the layers that have a column of the same name in ExtReps
+var ExtRepsLays = []string{"Context", "Goal", "Motor", "Outcome"}
+
+// ValidateExtReps returns an error if given table does not have any rows,
+// or is missing a column for one of the ExtRepsLays, or the column cell
+// shape does not match the layer size
+func (ss *Sim) ValidateExtReps(et *etable.Table) error {
+	if et == nil || et.NumRows() == 0 {
+		return fmt.Errorf("ValidateExtReps: ExtReps table has no rows")
+	}
+	for _, lnm := range ExtRepsLays {
+		col := et.ColByName(lnm)
+		if col == nil {
+			return fmt.Errorf("ValidateExtReps: ExtReps table has no %s column", lnm)
+		}
+		ly := ss.Net.LayerByName(lnm)
+		if ly == nil {
+			continue // e.g., before Config
+		}
+		_, cells := col.RowCellSize()
+		if nu := ly.Shape().Len(); cells != nu {
+			return fmt.Errorf("ValidateExtReps: ExtReps %s column has %d cells per row, but layer has %d units", lnm, cells, nu)
+		}
+	}
+	return nil
+}
+
+// CheckExtReps validates ExtReps, logging any error -- returns false if
+// not valid, in which case training should not proceed
+func (ss *Sim) CheckExtReps() bool {
+	if err := ss.ValidateExtReps(ss.ExtReps); err != nil {
+		log.Println(err)
+		return false
+	}
+	return true
+}
+
+// ResetEpcStats resets all the sums and counts accumulated over the trials
+// of the current epoch -- called in Init so that an epoch interrupted by
+// Stop does not contribute its partial sums to the next one
+func (ss *Sim) ResetEpcStats() {
+	ss.Stats.Init()
+	ss.WtUpdtCnt = 0
+	ss.DecayAmt = 0
+	ss.TraceCommitCnt = 0
+	ss.CriticSumV = 0
+	ss.CriticSumTD = 0
+	ss.SeqCorCnt = 0
+	ss.DegradActCnt = 0
+	ss.ExtinctActCnt = 0
+	ss.ExtinctSumMotAct = 0
+	ss.MotActCnts = nil
+	ss.PerseverCnt = 0
+	ss.ItiSums = nil
+	ss.ItiN = 0
+	ss.ApprCnt, ss.ApprTrlCnt, ss.AvoidCnt, ss.AvoidTrlCnt = 0, 0, 0, 0
+	for i := range ss.DelaySums {
+		ss.DelaySums[i] = DelaySum{}
+	}
+	for i := range ss.DWtSums {
+		ss.DWtSums[i] = DWtSum{}
+	}
+	for i := range ss.ContingSums {
+		ss.ContingSums[i] = ContingSum{}
+	}
+	for i := range ss.DriveSums {
+		ss.DriveSums[i] = DriveSum{}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// viewupdt.go throttles the NetView updates while running: at most
+// ViewMaxHz updates are rendered per second, and the ones in between are
+// coalesced -- skipped, with the latest state rendered by the next update
+// that is due, or by FlushView at the end of the run.  This way viewing at
+// the Cycle level (TrainUpdt = Cycle) does not slow training down by orders
+// of magnitude.  The View Updt toolbar action steps the TrainUpdt and
+// TestUpdt granularity through ViewUpdtScales, and takes effect at the next
+// AlphaCycle, so it can be switched while running.
+
+import (
+	"time"
+
+	"github.com/emer/leabra/leabra"
+)
+
+// ViewUpdtScales are the view update time scales stepped through by
+// NextViewUpdt
+var ViewUpdtScales = []leabra.TimeScales{leabra.Cycle, leabra.FastSpike, leabra.Quarter, leabra.Phase, leabra.AlphaCycle, leabra.Trial}
+
+// ViewDue returns whether a NetView update is due per ViewMaxHz -- if not,
+// the update is recorded as pending
+func (ss *Sim) ViewDue() bool {
+	if ss.ViewMaxHz <= 0 {
+		return true
+	}
+	now := time.Now()
+	if now.Sub(ss.ViewLast).Seconds() < 1/float64(ss.ViewMaxHz) {
+		ss.ViewPending = true
+		return false
+	}
+	ss.ViewLast = now
+	return true
+}
+
+// FlushView renders any NetView update skipped by the ViewMaxHz throttling
+// -- called at the end of each run, so the final state is shown
+func (ss *Sim) FlushView() {
+	if !ss.ViewPending {
+		return
+	}
+	ss.ViewLast = time.Time{}
+	ss.UpdateView()
+}
+
+// NextViewUpdt sets TrainUpdt and TestUpdt to the next of the
+// ViewUpdtScales after the current TrainUpdt, wrapping around, and returns it
+func (ss *Sim) NextViewUpdt() leabra.TimeScales {
+	nxt := ViewUpdtScales[0]
+	for i, ts := range ViewUpdtScales {
+		if ts == ss.TrainUpdt && i+1 < len(ViewUpdtScales) {
+			nxt = ViewUpdtScales[i+1]
+			break
+		}
+	}
+	ss.TrainUpdt = nxt
+	ss.TestUpdt = nxt
+	return nxt
+}
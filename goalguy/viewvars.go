@@ -0,0 +1,131 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"github.com/chewxy/math32"
+	"github.com/emer/emergent/emer"
+	"github.com/emer/leabra/leabra"
+)
+
+// ViewVars are derived unit variables, computed from the standard leabra
+// neuron and synapse variables, that are added to the NetView variable
+// list so that phase-based learning signals can be visualized directly on
+// the network:
+//
+//	PhaseDif    ActP - ActM: the plus - minus phase activation difference
+//	ErrContrib  (ActP - ActM)^2: the unit's contribution to the layer SSE
+//	DWtMag      mean |DWt| over the unit's receiving synapses, as of the last
+//	            weight change (recorded by RecDWtMags prior to WtFmDWt)
+var ViewVars = []string{"PhaseDif", "ErrContrib", "DWtMag"}
+
+// ViewNet wraps the network for display in the NetView, adding the derived
+// ViewVars to the unit variables of each layer -- it is display-only, and
+// the simulation itself always uses the leabra.Network directly
+type ViewNet struct {
+	*leabra.Network
+	Sim  *Sim
+	Lays []*ViewLayer
+}
+
+// NewViewNet returns a new ViewNet for the network of given sim
+func NewViewNet(ss *Sim) *ViewNet {
+	vn := &ViewNet{Network: ss.Net, Sim: ss}
+	for _, ly := range ss.Net.Layers {
+		vn.Lays = append(vn.Lays, &ViewLayer{Layer: ly.(*leabra.Layer), Sim: ss})
+	}
+	return vn
+}
+
+// Layer returns the wrapped layer at given index
+func (vn *ViewNet) Layer(idx int) emer.Layer {
+	return vn.Lays[idx]
+}
+
+// LayerByName returns the wrapped layer of given name, nil if not found
+func (vn *ViewNet) LayerByName(name string) emer.Layer {
+	for _, ly := range vn.Lays {
+		if ly.Nm == name {
+			return ly
+		}
+	}
+	return nil
+}
+
+// UnitVarNames returns the standard unit variables plus the ViewVars
+func (vn *ViewNet) UnitVarNames() []string {
+	return append(append([]string{}, vn.Network.UnitVarNames()...), ViewVars...)
+}
+
+// ViewLayer wraps a layer for display in the NetView, adding the ViewVars
+type ViewLayer struct {
+	*leabra.Layer
+	Sim *Sim
+}
+
+// UnitVarNames returns the standard unit variables plus the ViewVars
+func (vl *ViewLayer) UnitVarNames() []string {
+	return append(append([]string{}, vl.Layer.UnitVarNames()...), ViewVars...)
+}
+
+// UnitVals returns the values of given variable for all units in the layer,
+// including the ViewVars
+func (vl *ViewLayer) UnitVals(varNm string) ([]float32, error) {
+	switch varNm {
+	case "PhaseDif", "ErrContrib":
+		vals := make([]float32, len(vl.Neurons))
+		for ni := range vl.Neurons {
+			nrn := &vl.Neurons[ni]
+			d := nrn.ActP - nrn.ActM
+			if varNm == "ErrContrib" {
+				d *= d
+			}
+			vals[ni] = d
+		}
+		return vals, nil
+	case "DWtMag":
+		vals := make([]float32, len(vl.Neurons))
+		copy(vals, vl.Sim.DWtMags[vl.Nm])
+		return vals, nil
+	}
+	return vl.Layer.UnitVals(varNm)
+}
+
+// RecDWtMags records the mean |DWt| over the receiving synapses of each
+// unit, for the DWtMag view variable -- called after DWt and prior to
+// WtFmDWt (which zeros the DWt's) in AlphaCyc, only if there are NetViews
+func (ss *Sim) RecDWtMags() {
+	if !ss.HasNetView() {
+		return
+	}
+	if ss.DWtMags == nil {
+		ss.DWtMags = make(map[string][]float32)
+	}
+	for _, l := range ss.Net.Layers {
+		ly := l.(*leabra.Layer)
+		mags := ss.DWtMags[ly.Nm]
+		if len(mags) != len(ly.Neurons) {
+			mags = make([]float32, len(ly.Neurons))
+			ss.DWtMags[ly.Nm] = mags
+		}
+		for ri := range mags {
+			sum := float32(0)
+			n := 0
+			for _, p := range ly.RcvPrjns {
+				pj := p.(*leabra.Prjn)
+				nc := int(pj.RConN[ri])
+				st := int(pj.RConIdxSt[ri])
+				for ci := 0; ci < nc; ci++ {
+					sum += math32.Abs(pj.Syns[pj.RSynIdx[st+ci]].DWt)
+				}
+				n += nc
+			}
+			if n > 0 {
+				sum /= float32(n)
+			}
+			mags[ri] = sum
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// wtdecay.go has the per-projection weight decay and synaptic scaling,
+// applied after each weight update (WtFmDWt) on the projections listed in
+// PrjnDecays, to counter runaway weights over hundreds of epochs of
+// training, e.g., of the full Goal -> Motor mapping.  Both operate on the
+// linear weights (LWt), from which the effective sigmoidal weights are
+// recomputed.  The total absolute LWt change they make is accumulated over
+// the epoch, and logged as WtDecay in the EpcLog.
+
+import (
+	"log"
+
+	"github.com/emer/leabra/leabra"
+	"github.com/goki/ki/kit"
+)
+
+// DecayModes are the modes of weight decay
+type DecayModes int32
+
+//go:generate stringer -type=DecayModes
+
+var KiT_DecayModes = kit.Enums.AddEnum(DecayModesN, false, nil)
+
+const (
+	// WtDecay decays each weight toward zero by Rate times its value
+	WtDecay DecayModes = iota
+
+	// SynScale multiplicatively scales the weights of each receiving unit
+	// so that their mean moves toward Target by Rate, preserving their
+	// relative sizes
+	SynScale
+
+	DecayModesN
+)
+
+// PrjnDecay specifies the weight decay or synaptic scaling of one projection
+type PrjnDecay struct {
+	Prjn   string     `desc:"projection as Send:Recv layer names, e.g., Goal:Motor"`
+	Mode   DecayModes `desc:"mode of weight decay"`
+	Rate   float32    `desc:"rate of decay (WtDecay) or of approach to the Target mean weight (SynScale) per weight update"`
+	Target float32    `desc:"for SynScale, the target mean linear weight of each receiving unit"`
+}
+
+// ApplyPrjnDecays applies the PrjnDecays to the network projections --
+// called after each WtFmDWt
+func (ss *Sim) ApplyPrjnDecays() {
+	for _, pd := range ss.PrjnDecays {
+		if pd.Rate == 0 {
+			continue
+		}
+		pj, err := ss.PrjnByPath(pd.Prjn)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		switch pd.Mode {
+		case WtDecay:
+			for si := range pj.Syns {
+				sy := &pj.Syns[si]
+				ss.SetLWt(pj, sy, sy.LWt-pd.Rate*sy.LWt)
+			}
+		case SynScale:
+			ss.SynScalePrjn(pj, pd.Rate, pd.Target)
+		}
+	}
+}
+
+// SynScalePrjn scales the linear weights of each receiving unit of given
+// projection so that their mean moves toward given target by given rate
+func (ss *Sim) SynScalePrjn(pj *leabra.Prjn, rate, targ float32) {
+	for ri := range pj.RConN {
+		nc := int(pj.RConN[ri])
+		st := int(pj.RConIdxSt[ri])
+		if nc == 0 {
+			continue
+		}
+		mean := float32(0)
+		for ci := 0; ci < nc; ci++ {
+			mean += pj.Syns[pj.RSynIdx[st+ci]].LWt
+		}
+		mean /= float32(nc)
+		if mean <= 0 {
+			continue
+		}
+		sc := 1 + rate*(targ/mean-1)
+		for ci := 0; ci < nc; ci++ {
+			sy := &pj.Syns[pj.RSynIdx[st+ci]]
+			ss.SetLWt(pj, sy, sy.LWt*sc)
+		}
+	}
+}
+
+// SetLWt sets the linear weight of given synapse of given projection,
+// clipped to the 0..1 range, and its effective weight from it, adding the
+// absolute change to the DecayAmt
+func (ss *Sim) SetLWt(pj *leabra.Prjn, sy *leabra.Synapse, lwt float32) {
+	switch {
+	case lwt < 0:
+		lwt = 0
+	case lwt > 1:
+		lwt = 1
+	}
+	d := lwt - sy.LWt
+	if d < 0 {
+		d = -d
+	}
+	ss.DecayAmt += d
+	sy.LWt = lwt
+	sy.Wt = pj.Learn.WtSig.SigFmLinWt(lwt)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// PrjnByName returns the projection from send layer to recv layer,
+// or nil if not found
+func (ss *Sim) PrjnByName(send, recv string) *leabra.Prjn {
+	rly, ok := ss.Net.LayerByName(recv).(*leabra.Layer)
+	if !ok || rly == nil {
+		return nil
+	}
+	for _, p := range rly.RcvPrjns {
+		if p.SendLay().Name() == send {
+			return p.(*leabra.Prjn)
+		}
+	}
+	return nil
+}
+
+// PrjnByPath returns the projection specified as "Send:Recv" layer names,
+// e.g., "Goal:Motor", or an error if not found
+func (ss *Sim) PrjnByPath(path string) (*leabra.Prjn, error) {
+	nms := strings.Split(path, ":")
+	if len(nms) != 2 {
+		return nil, fmt.Errorf("projection %q must be specified as Send:Recv", path)
+	}
+	pj := ss.PrjnByName(nms[0], nms[1])
+	if pj == nil {
+		return nil, fmt.Errorf("projection %q not found", path)
+	}
+	return pj, nil
+}
+
+// PrjnWts returns the full weight matrix of the projection as a 2D tensor,
+// with rows as receiving units and columns as sending units (zero where
+// there is no connection), reusing given tensor if it is the right size
+func PrjnWts(pj *leabra.Prjn, tsr *etensor.Float32) *etensor.Float32 {
+	rly := pj.Recv.(*leabra.Layer)
+	sly := pj.Send.(*leabra.Layer)
+	nr := len(rly.Neurons)
+	ns := len(sly.Neurons)
+	if tsr == nil || tsr.Dim(0) != nr || tsr.Dim(1) != ns {
+		tsr = etensor.NewFloat32([]int{nr, ns}, nil, []string{"Recv", "Send"})
+	} else {
+		tsr.SetZeros()
+	}
+	for si := 0; si < ns; si++ {
+		nc := int(pj.SConN[si])
+		st := int(pj.SConIdxSt[si])
+		for ci := 0; ci < nc; ci++ {
+			ri := int(pj.SConIdx[st+ci])
+			tsr.Values[ri*ns+si] = pj.Syns[st+ci].Wt
+		}
+	}
+	return tsr
+}
+
+// UpdtWtGrid updates the WtGrid tab with the current weights of the
+// WtGridPrjn projection
+func (ss *Sim) UpdtWtGrid() {
+	if ss.WtGridSvg == nil || !ss.WtGridSvg.IsVisible() {
+		return
+	}
+	pj, err := ss.PrjnByPath(ss.WtGridPrjn)
+	if err != nil {
+		return
+	}
+	ss.WtGrid = PrjnWts(pj, ss.WtGrid)
+	PlotTensorGrid(ss.WtGridSvg, ss.WtGrid, ss.WtGridPrjn+" Weights", "Sending Unit", "Receiving Unit")
+}
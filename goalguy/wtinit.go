@@ -0,0 +1,84 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+import (
+	"log"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/ki/kit"
+)
+
+// WtSyms are the options for the symmetry of the initial weights of
+// reciprocal projections (e.g., Motor -> Outcome and the Outcome -> Motor
+// Back projection), applied to all projections by WtSym
+type WtSyms int32
+
+//go:generate stringer -type=WtSyms
+
+var KiT_WtSyms = kit.Enums.AddEnum(WtSymsN, false, nil)
+
+const (
+	// SymDefault leaves WtInit.Sym at the library default (on) or as set
+	// by the Params
+	SymDefault WtSyms = iota
+
+	// SymOn initializes the weights of each projection symmetric with those
+	// of its reciprocal projection: the receiving projection weights are
+	// the transpose of the sending ones
+	SymOn
+
+	// SymOff initializes the weights of reciprocal projections
+	// independently at random
+	SymOff
+
+	WtSymsN
+)
+
+// PrjnWtInit specifies the initial random weight distribution for one
+// projection, overriding the library defaults (and Params)
+type PrjnWtInit struct {
+	Prjn string  `desc:"projection as Send:Recv layer names, e.g., Context:Goal"`
+	Mean float32 `desc:"mean of the initial random weights -- WtInit.Mean"`
+	Var  float32 `desc:"variance of the initial random weights -- WtInit.Var"`
+	Sym  bool    `desc:"make the initial weights symmetric with those of the reciprocal projection, if any -- WtInit.Sym"`
+}
+
+// ApplyPrjnWtInits applies the WtSym symmetry to all the network
+// projections, and then the PrjnWtInits initial weight settings, which take
+// precedence -- called after params are styled in ConfigNet and Init, before
+// InitWts
+func (ss *Sim) ApplyPrjnWtInits() {
+	if ss.WtSym != SymDefault {
+		for _, pj := range ss.AllPrjns() {
+			pj.WtInit.Sym = ss.WtSym == SymOn
+		}
+	}
+	for _, pw := range ss.PrjnWtInits {
+		pj, err := ss.PrjnByPath(pw.Prjn)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		pj.WtInit.Mean = float64(pw.Mean)
+		pj.WtInit.Var = float64(pw.Var)
+		pj.WtInit.Sym = pw.Sym
+	}
+}
+
+// InitWts initializes the network weights: randomly per the PrjnWtInits
+// and params, and then from InitWtsFile if set -- e.g., for a deliberately
+// structured (identity-ish Context -> Goal) initialization saved with
+// Save Weights
+func (ss *Sim) InitWts() {
+	ss.ApplyPrjnWtInits()
+	ss.Net.InitWts()
+	if ss.InitWtsFile == "" {
+		return
+	}
+	if err := ss.OpenWts(gi.FileName(ss.InitWtsFile)); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goalguy
+
+// yoked.go has the yoked-control mode, for tight comparisons between two
+// model variants (experiments, e.g., with and without the Outcome -> Motor
+// back projection): both are trained within one process, one after the
+// other, from the same random seed, on the same patterns, and on the same
+// trial sequence -- a schedule of the training item order for every epoch
+// is drawn up front and replayed by the TrainEnv of both (YokeOrder).  Both
+// are trained for YokeMaxEpcs epochs, without stopping early, and the
+// combined comparison log has, per epoch, each of the YokeCols of the
+// EpcLog of both variants (<col>A, <col>B) and their difference
+// (<col>Diff = B - A).
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// YokeMaxEpcs is the number of epochs each yoked variant is trained
+const YokeMaxEpcs = 200
+
+// YokeCols are the EpcLog columns compared in the yoked comparison log
+var YokeCols = []string{"OutGoalPctErr", "OutPredPctErr", "OutSSE", "MotSSE"}
+
+// YokedSim returns a fresh Sim with given experiment, without the gui,
+// set up for a yoked run with given seed (0 = the default)
+func YokedSim(expt string, seed int64) (*Sim, error) {
+	ss := &Sim{}
+	ss.New()
+	if expt != "" {
+		if err := ss.SetExpt(expt); err != nil {
+			return nil, err
+		}
+	}
+	ss.MaxEpcs = YokeMaxEpcs
+	ss.NZeroStop = 0
+	ss.ViewOn = false
+	ss.Plot = false
+	if seed != 0 {
+		ss.RndSeed = seed
+	}
+	ss.Config()
+	ss.GenExtReps(ss.ExtReps, SweepNPats)
+	ss.ValReps.SetNumRows(0)
+	return ss, nil
+}
+
+// CopyTableVals copies the values of all the columns of src into the
+// columns of the same name of dst, which must have the same sizes
+func CopyTableVals(dst, src *etable.Table) error {
+	for i, scol := range src.Cols {
+		nm := src.ColNames[i]
+		dcol := dst.ColByName(nm)
+		if dcol == nil || dcol.Len() != scol.Len() {
+			return fmt.Errorf("CopyTableVals: column %s is missing or of a different size", nm)
+		}
+		for j := 0; j < scol.Len(); j++ {
+			if scol.DataType() == etensor.STRING {
+				dcol.SetString1D(j, scol.StringVal1D(j))
+			} else {
+				dcol.SetFloat1D(j, scol.FloatVal1D(j))
+			}
+		}
+	}
+	return nil
+}
+
+// YokeSchedule returns the training item order of each of given number of
+// epochs of given table, per given order
+func YokeSchedule(et *etable.Table, order Orders, epcs int) [][]int {
+	sched := Env{Nm: "YokeSchedule", Order: order}
+	sched.Init(et)
+	for len(sched.Hist) < epcs {
+		sched.NewPorder()
+	}
+	return sched.Hist
+}
+
+// RunYoked trains the two given experiments as yoked variants A and B, with
+// given seed (0 = the default), and returns the combined comparison log
+func RunYoked(exptA, exptB string, seed int64) (*etable.Table, error) {
+	a, err := YokedSim(exptA, seed)
+	if err != nil {
+		return nil, err
+	}
+	b, err := YokedSim(exptB, seed)
+	if err != nil {
+		return nil, err
+	}
+	if err := CopyTableVals(b.ExtReps, a.ExtReps); err != nil {
+		return nil, fmt.Errorf("RunYoked: variants %s and %s need the same pattern shapes: %v", exptA, exptB, err)
+	}
+	b.UpdtPatsHash()
+	rand.Seed(a.RndSeed)
+	sched := YokeSchedule(a.ExtReps, a.TrainEnv.Order, YokeMaxEpcs+1)
+	for _, ss := range []*Sim{a, b} {
+		ss.YokeOrder = sched
+		ss.Init()
+		ss.Train()
+		fmt.Printf("yoked variant %s done: FirstZero: %d\n", ss.Expt, ss.FirstZero)
+	}
+	return YokeLog(a.EpcLog, b.EpcLog), nil
+}
+
+// YokeLog returns the combined comparison log of the YokeCols of given
+// EpcLogs of variants A and B, for the epochs in both
+func YokeLog(la, lb *etable.Table) *etable.Table {
+	brow := map[int]int{}
+	for r := 0; r < lb.NumRows(); r++ {
+		brow[int(lb.ColByName("Epoch").FloatVal1D(r))] = r
+	}
+	sc := etable.Schema{{"Epoch", etensor.INT64, nil, nil}}
+	for _, cl := range YokeCols {
+		sc = append(sc, etable.Schema{
+			{cl + "A", etensor.FLOAT32, nil, nil},
+			{cl + "B", etensor.FLOAT32, nil, nil},
+			{cl + "Diff", etensor.FLOAT32, nil, nil},
+		}...)
+	}
+	dt := &etable.Table{}
+	dt.SetFromSchema(sc, 0)
+	for ra := 0; ra < la.NumRows(); ra++ {
+		epc := int(la.ColByName("Epoch").FloatVal1D(ra))
+		rb, has := brow[epc]
+		if !has {
+			continue
+		}
+		row := dt.NumRows()
+		dt.AddRows(1)
+		dt.ColByName("Epoch").SetFloat1D(row, float64(epc))
+		for _, cl := range YokeCols {
+			va := la.ColByName(cl).FloatVal1D(ra)
+			vb := lb.ColByName(cl).FloatVal1D(rb)
+			dt.ColByName(cl+"A").SetFloat1D(row, va)
+			dt.ColByName(cl+"B").SetFloat1D(row, vb)
+			dt.ColByName(cl+"Diff").SetFloat1D(row, vb-va)
+		}
+	}
+	return dt
+}